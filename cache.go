@@ -0,0 +1,162 @@
+package gotwi
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cache stores the response bodies of GET endpoints.
+// Implement it to use an external backend such as Redis.
+type Cache interface {
+	// Get returns the value and true if the key exists and is not expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value for the ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// CacheConfig enables the response cache of GotwiClient. Only the GET calls with a positive TTL are cached,
+// and the errors of the Cache are counted in the stats and do not fail the calls.
+type CacheConfig struct {
+	// hits, misses, sets and errors are accessed atomically, so they are kept at the top for 64-bit alignment.
+	hits   int64
+	misses int64
+	sets   int64
+	errors int64
+
+	Cache Cache
+	// DefaultTTL is the TTL of the endpoints that are not in TTLs. Zero means they are not cached.
+	DefaultTTL time.Duration
+	// TTLs is the TTL per endpoint, keyed by the endpoint constant such as users.UserLookupByUsernameEndpoint.
+	TTLs map[string]time.Duration
+}
+
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+	Sets   int64
+	Errors int64
+}
+
+func (cc *CacheConfig) ttl(endpointBase, method string) time.Duration {
+	if cc == nil || cc.Cache == nil || method != http.MethodGet {
+		return 0
+	}
+
+	if ttl, ok := cc.TTLs[endpointBase]; ok {
+		return ttl
+	}
+
+	return cc.DefaultTTL
+}
+
+// Stats returns the counts of the cache hits, misses, stored responses and cache errors.
+func (cc *CacheConfig) Stats() CacheStats {
+	if cc == nil {
+		return CacheStats{}
+	}
+
+	return CacheStats{
+		Hits:   atomic.LoadInt64(&cc.hits),
+		Misses: atomic.LoadInt64(&cc.misses),
+		Sets:   atomic.LoadInt64(&cc.sets),
+		Errors: atomic.LoadInt64(&cc.errors),
+	}
+}
+
+// CacheStats returns the stats of the response cache. It is zero if the cache is not enabled.
+func (c *GotwiClient) CacheStats() CacheStats {
+	return c.Cache.Stats()
+}
+
+// cacheKey returns the key of the resolved endpoint for the identity of the client,
+// so that the responses for other users or apps are not shared.
+func (c *GotwiClient) cacheKey(req *http.Request) string {
+	identity := sha256.Sum256([]byte(string(c.AuthenticationMethod) + "\n" + c.OAuthConsumerKey + "\n" + c.OAuthToken + "\n" + c.AccessToken))
+	return hex.EncodeToString(identity[:16]) + " " + req.Method + " " + req.URL.String()
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// LRUCache is an in-memory Cache that evicts the least recently used entry when it is full.
+type LRUCache struct {
+	capacity int
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+// NewLRUCache returns LRUCache that holds up to capacity entries. A capacity less than 1 is treated as 1.
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &LRUCache{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (l *LRUCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := e.Value.(*lruEntry)
+	if !time.Now().Before(entry.expiresAt) {
+		l.remove(e)
+		return nil, false, nil
+	}
+
+	l.order.MoveToFront(e)
+	return entry.value, true, nil
+}
+
+func (l *LRUCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+	if e, ok := l.entries[key]; ok {
+		entry := e.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		l.order.MoveToFront(e)
+		return nil
+	}
+
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for l.order.Len() > l.capacity {
+		l.remove(l.order.Back())
+	}
+
+	return nil
+}
+
+// Len returns the number of the entries including expired ones that are not evicted yet.
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
+
+func (l *LRUCache) remove(e *list.Element) {
+	l.order.Remove(e)
+	delete(l.entries, e.Value.(*lruEntry).key)
+}
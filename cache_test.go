@@ -0,0 +1,139 @@
+package gotwi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/internal/testutil"
+	"github.com/michimani/gotwi/users"
+	"github.com/michimani/gotwi/users/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CallAPI_Cache(t *testing.T) {
+	var calls int64
+	hc := testutil.HTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/2/users/by/username/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"title":"Not Found Error"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"id":"%d","name":"gopher","username":"gopher"}}`, n)
+	}))
+
+	cc := &gotwi.CacheConfig{
+		Cache: gotwi.NewLRUCache(10),
+		TTLs: map[string]time.Duration{
+			users.UserLookupByUsernameEndpoint: time.Minute,
+		},
+	}
+	c := newCacheTestClient(t, hc, "token", cc)
+	ctx := context.Background()
+
+	// cached
+	for i := 0; i < 3; i++ {
+		res, err := users.UserLookupByUsername(ctx, c, &types.UserLookupByUsernameParams{Username: "gopher"})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", gotwi.StringValue(res.Data.ID))
+	}
+	assert.Equal(t, int64(1), atomic.LoadInt64(&calls))
+	assert.Equal(t, gotwi.CacheStats{Hits: 2, Misses: 1, Sets: 1}, c.CacheStats())
+
+	// another user is another key
+	_, err := users.UserLookupByUsername(ctx, c, &types.UserLookupByUsernameParams{Username: "other"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&calls))
+
+	// non 2XX responses are not cached
+	for i := 0; i < 2; i++ {
+		_, err := users.UserLookupByUsername(ctx, c, &types.UserLookupByUsernameParams{Username: "missing"})
+		assert.Error(t, err)
+	}
+	assert.Equal(t, int64(4), atomic.LoadInt64(&calls))
+
+	// endpoints without TTL are not cached
+	for i := 0; i < 2; i++ {
+		_, err := users.UserLookupID(ctx, c, &types.UserLookupIDParams{ID: "1"})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(6), atomic.LoadInt64(&calls))
+
+	// another identity does not share the cache
+	other := newCacheTestClient(t, hc, "other-token", cc)
+	_, err = users.UserLookupByUsername(ctx, other, &types.UserLookupByUsernameParams{Username: "gopher"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), atomic.LoadInt64(&calls))
+}
+
+func Test_CallAPI_Cache_NonGET(t *testing.T) {
+	var calls int64
+	hc := testutil.HTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data":{"blocking":true}}`)
+	}))
+
+	cc := &gotwi.CacheConfig{Cache: gotwi.NewLRUCache(10), DefaultTTL: time.Minute}
+	c := newCacheTestClient(t, hc, "token", cc)
+
+	for i := 0; i < 2; i++ {
+		_, err := users.BlocksBlockingPost(context.Background(), c, &types.BlocksBlockingPostParams{ID: "1", TargetUserID: gotwi.String("2")})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(2), atomic.LoadInt64(&calls))
+	assert.Equal(t, gotwi.CacheStats{}, c.CacheStats())
+}
+
+func Test_LRUCache(t *testing.T) {
+	ctx := context.Background()
+	l := gotwi.NewLRUCache(2)
+
+	assert.NoError(t, l.Set(ctx, "a", []byte("1"), time.Minute))
+	assert.NoError(t, l.Set(ctx, "b", []byte("2"), time.Minute))
+
+	// a is used, so b is evicted
+	v, ok, err := l.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), v)
+	assert.NoError(t, l.Set(ctx, "c", []byte("3"), time.Minute))
+
+	_, ok, _ = l.Get(ctx, "b")
+	assert.False(t, ok)
+	_, ok, _ = l.Get(ctx, "c")
+	assert.True(t, ok)
+	assert.Equal(t, 2, l.Len())
+
+	// expired
+	assert.NoError(t, l.Set(ctx, "d", []byte("4"), time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+	_, ok, _ = l.Get(ctx, "d")
+	assert.False(t, ok)
+	assert.Equal(t, 1, l.Len())
+}
+
+func newCacheTestClient(t *testing.T, hc *http.Client, token string, cc *gotwi.CacheConfig) *gotwi.GotwiClient {
+	t.Helper()
+	t.Setenv(gotwi.APIKeyEnvName, "api-key")
+	t.Setenv(gotwi.APIKeySecretEnvName, "api-key-secret")
+
+	c, err := gotwi.NewGotwiClient(&gotwi.NewGotwiClientInput{
+		HTTPClient:           hc,
+		AuthenticationMethod: gotwi.AuthenMethodOAuth1UserContext,
+		OAuthToken:           token,
+		OAuthTokenSecret:     "token-secret",
+		Cache:                cc,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
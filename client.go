@@ -1,14 +1,17 @@
 package gotwi
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/michimani/gotwi/internal/gotwierrors"
@@ -35,8 +38,15 @@ func (a AuthenticationMethod) Valid() bool {
 type NewGotwiClientInput struct {
 	HTTPClient           *http.Client
 	AuthenticationMethod AuthenticationMethod
+	APIKey               string // If APIKey or APIKeySecret is empty, the environment variables are used.
+	APIKeySecret         string
 	OAuthToken           string
 	OAuthTokenSecret     string
+	AccessToken          string // OAuth 2.0 token such as a user token of the PKCE flow. If empty, an app-only token is generated.
+	Cache                *CacheConfig
+	Middlewares          []Middleware // They run for every request of CallAPI and CallStreamAPI.
+	Logger               Logger       // If not nil, the requests and the responses are logged at the debug level.
+	Instrumentation      Instrumentation
 }
 
 type GotwiClient struct {
@@ -46,6 +56,10 @@ type GotwiClient struct {
 	OAuthToken           string
 	SigningKey           string
 	OAuthConsumerKey     string
+	Cache                *CacheConfig
+	Middlewares          []Middleware
+	Logger               Logger
+	Instrumentation      Instrumentation
 }
 
 type ClientResponse struct {
@@ -72,22 +86,33 @@ func NewGotwiClient(in *NewGotwiClientInput) (*GotwiClient, error) {
 	c := GotwiClient{
 		Client:               defaultHTTPClient,
 		AuthenticationMethod: in.AuthenticationMethod,
+		Cache:                in.Cache,
+		Middlewares:          in.Middlewares,
+		Logger:               in.Logger,
+		Instrumentation:      in.Instrumentation,
 	}
 
 	if in.HTTPClient != nil {
 		c.Client = in.HTTPClient
 	}
 
-	if err := c.authorize(in.OAuthToken, in.OAuthTokenSecret); err != nil {
+	if in.AuthenticationMethod == AuthenMethodOAuth2BearerToken && in.AccessToken != "" {
+		c.AccessToken = in.AccessToken
+		return &c, nil
+	}
+
+	if err := c.authorize(in.APIKey, in.APIKeySecret, in.OAuthToken, in.OAuthTokenSecret); err != nil {
 		return nil, err
 	}
 
 	return &c, nil
 }
 
-func (c *GotwiClient) authorize(oauthToken, oauthTokenSecret string) error {
-	apiKey := os.Getenv(APIKeyEnvName)
-	apiKeySecret := os.Getenv(APIKeySecretEnvName)
+func (c *GotwiClient) authorize(apiKey, apiKeySecret, oauthToken, oauthTokenSecret string) error {
+	if apiKey == "" || apiKeySecret == "" {
+		apiKey = os.Getenv(APIKeyEnvName)
+		apiKeySecret = os.Getenv(APIKeySecretEnvName)
+	}
 	if apiKey == "" || apiKeySecret == "" {
 		return fmt.Errorf("env '%s' and '%s' is required.", APIKeyEnvName, APIKeySecretEnvName)
 	}
@@ -142,31 +167,123 @@ func (c *GotwiClient) IsReady() bool {
 	return true
 }
 
-func (c *GotwiClient) CallAPI(ctx context.Context, endpoint, method string, p util.Parameters, i util.Response) error {
+// CallAPI calls the API and decodes the 2XX response to i.
+// For a non-2XX response, it returns *resources.Non2XXError, whose message is the same as before it was typed.
+func (c *GotwiClient) CallAPI(ctx context.Context, endpoint, method string, p util.Parameters, i util.Response) (err error) {
+	ctx, _, end := c.startCall(ctx, endpoint, method, false)
+	defer func() { end(err) }()
+
 	req, err := c.prepare(ctx, endpoint, method, p)
 	if err != nil {
 		return err
 	}
 
-	not200err, err := c.Exec(req, i)
+	ttl := c.Cache.ttl(endpoint, method)
+	if ttl <= 0 {
+		not200err, err := c.Exec(req, i)
+		if err != nil {
+			return err
+		}
+
+		if not200err != nil {
+			return not200err
+		}
+
+		return nil
+	}
+
+	return c.callAPIWithCache(ctx, req, ttl, i)
+}
+
+// callAPIWithCache returns the cached response if exists, so that the call does not consume the rate limit.
+// Otherwise it calls the API and caches the 2XX response.
+func (c *GotwiClient) callAPIWithCache(ctx context.Context, req *http.Request, ttl time.Duration, i util.Response) error {
+	key := c.cacheKey(req)
+	cached, ok, err := c.Cache.Cache.Get(ctx, key)
+	if err != nil {
+		atomic.AddInt64(&c.Cache.errors, 1)
+	}
+	if ok {
+		if err := json.NewDecoder(bytes.NewReader(cached)).Decode(i); err == nil {
+			atomic.AddInt64(&c.Cache.hits, 1)
+			if r := callResultFromContext(ctx); r != nil {
+				r.Cached = true
+			}
+			c.Debug("gotwi: cached response", "method", req.Method, "url", RedactURL(req.URL))
+			return nil
+		}
+		atomic.AddInt64(&c.Cache.errors, 1)
+	}
+	atomic.AddInt64(&c.Cache.misses, 1)
+
+	body := &bytes.Buffer{}
+	not200err, err := c.exec(req, i, body)
 	if err != nil {
 		return err
 	}
 
 	if not200err != nil {
-		return fmt.Errorf(gotwierrors.ErrorNon2XXStatus, not200err.Summary())
+		return not200err
+	}
+
+	if err := c.Cache.Cache.Set(ctx, key, body.Bytes(), ttl); err != nil {
+		atomic.AddInt64(&c.Cache.errors, 1)
+		return nil
 	}
+	atomic.AddInt64(&c.Cache.sets, 1)
 
 	return nil
 }
 
+// CallStreamAPI calls the streaming API and returns the 2XX response, whose body is the stream.
+// The caller must close the body. The timeout of the http.Client is not applied to the stream.
+func (c *GotwiClient) CallStreamAPI(ctx context.Context, endpoint, method string, p util.Parameters) (_ *http.Response, err error) {
+	ctx, ci, end := c.startCall(ctx, endpoint, method, true)
+	defer func() { end(err) }()
+
+	req, err := c.prepare(ctx, endpoint, method, p)
+	if err != nil {
+		return nil, err
+	}
+
+	hc := c.Client
+	if hc.Timeout > 0 {
+		copied := *hc
+		copied.Timeout = 0
+		hc = &copied
+	}
+
+	res, err := c.do(hc, req.WithContext(context.WithValue(req.Context(), streamKey, true)))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := okCodes[res.StatusCode]; !ok {
+		defer res.Body.Close()
+		non200err, err := resolveNon2XXResponse(res)
+		if err != nil {
+			return nil, err
+		}
+		c.logNon2XX(non200err)
+		return nil, non200err
+	}
+
+	c.instrumentStream(ci, res)
+	return res, nil
+}
+
 var okCodes map[int]struct{} = map[int]struct{}{
 	http.StatusOK:      {},
 	http.StatusCreated: {},
 }
 
 func (c *GotwiClient) Exec(req *http.Request, i util.Response) (*resources.Non2XXError, error) {
-	res, err := c.Client.Do(req)
+	return c.exec(req, i, nil)
+}
+
+// exec is Exec that also copies the 2XX response body to the body if it is not nil.
+func (c *GotwiClient) exec(req *http.Request, i util.Response, body io.Writer) (*resources.Non2XXError, error) {
+	res, err := c.do(c.Client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -177,10 +294,16 @@ func (c *GotwiClient) Exec(req *http.Request, i util.Response) (*resources.Non2X
 		if err != nil {
 			return nil, err
 		}
+		c.logNon2XX(non200err)
 		return non200err, nil
 	}
 
-	if err := json.NewDecoder(res.Body).Decode(i); err != nil {
+	var r io.Reader = res.Body
+	if body != nil {
+		r = io.TeeReader(res.Body, body)
+	}
+
+	if err := json.NewDecoder(r).Decode(i); err != nil {
 		return nil, err
 	}
 
@@ -215,6 +338,7 @@ func (c *GotwiClient) prepare(ctx context.Context, endpointBase, method string,
 	default:
 		// noop
 	}
+	c.logRequest(req)
 
 	return req, nil
 }
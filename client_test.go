@@ -0,0 +1,77 @@
+package gotwi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/resources"
+	"github.com/michimani/gotwi/users"
+	"github.com/michimani/gotwi/users/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CallAPI_Non2XXError(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Truncate(time.Second)
+
+	cases := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectStatus  int
+		expectMessage string
+		expectReset   bool
+	}{
+		{
+			name: "not found",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"title":"Not Found Error","detail":"Could not find user with id: [1].","type":"https://api.twitter.com/2/problems/resource-not-found"}`)
+			},
+			expectStatus:  http.StatusNotFound,
+			expectMessage: `Twitter API returned a status other than 200. httpStatus="404 Not Found" httpStatusCode=404 title="Not Found Error" detail="Could not find user with id: [1]." `,
+		},
+		{
+			name: "too many requests",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("x-rate-limit-limit", "900")
+				w.Header().Set("x-rate-limit-remaining", "0")
+				w.Header().Set("x-rate-limit-reset", strconv.FormatInt(reset.Unix(), 10))
+				w.WriteHeader(http.StatusTooManyRequests)
+				fmt.Fprint(w, `{"title":"Too Many Requests","detail":"Too Many Requests","type":"about:blank","status":429}`)
+			},
+			expectStatus:  http.StatusTooManyRequests,
+			expectMessage: fmt.Sprintf(`Twitter API returned a status other than 200. httpStatus="429 Too Many Requests" httpStatusCode=429 title="Too Many Requests" detail="Too Many Requests" rateLimit=900 rateLimitRemaining=0 rateLimitReset="%s"`, reset),
+			expectReset:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			client := newMiddlewareClient(tt, c.handler)
+
+			res, err := users.UserLookupID(context.Background(), client, &types.UserLookupIDParams{ID: "1"})
+			assert.Nil(tt, res)
+			require.Error(tt, err)
+			// the message is the same as the error of fmt.Errorf(gotwierrors.ErrorNon2XXStatus, summary)
+			assert.Equal(tt, c.expectMessage, err.Error())
+
+			non2xx := &resources.Non2XXError{}
+			require.True(tt, errors.As(fmt.Errorf("wrapped: %w", err), &non2xx))
+			assert.Equal(tt, c.expectStatus, gotwi.IntValue(non2xx.StatusCode))
+			if c.expectReset {
+				require.NotNil(tt, non2xx.RateLimitInfo)
+				assert.True(tt, reset.Equal(*non2xx.RateLimitInfo.ResetAt))
+			} else {
+				assert.Nil(tt, non2xx.RateLimitInfo)
+			}
+		})
+	}
+}
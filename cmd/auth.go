@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/profile"
+	"github.com/michimani/gotwi/users"
+	"github.com/michimani/gotwi/users/types"
+)
+
+const (
+	defaultRedirectURI = "http://127.0.0.1:8910/callback"
+	defaultScopes      = "tweet.read tweet.write users.read follows.read follows.write offline.access"
+)
+
+func init() {
+	register("auth",
+		command{"login", "Log in with OAuth 1.0a PIN flow or OAuth 2.0 PKCE flow, and save the tokens to a profile.", authLogin},
+		command{"status", "Show the active profile and user.", authStatus},
+		command{"use", "Make a profile the current profile.", authUse},
+	)
+}
+
+func (fs *flagSet) configPath() (string, error) {
+	if fs.config != "" {
+		return fs.config, nil
+	}
+	return profile.DefaultPath()
+}
+
+func authLogin(e *env, args []string) error {
+	fs := newFlagSet(e, "auth", "login")
+	method := fs.String("method", "oauth1", "login flow: oauth1 (PIN) or oauth2 (PKCE)")
+	clientID := fs.String("client-id", "", "OAuth 2.0 client ID (required for oauth2)")
+	clientSecret := fs.String("client-secret", "", "OAuth 2.0 client secret for confidential clients")
+	redirectURI := fs.String("redirect-uri", defaultRedirectURI, "OAuth 2.0 callback URL on localhost registered for the app")
+	scopes := fs.String("scopes", defaultScopes, "space separated OAuth 2.0 scopes")
+	timeout := fs.Duration("timeout", 5*time.Minute, "time to wait for the authorization")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+
+	path, err := fs.configPath()
+	if err != nil {
+		return err
+	}
+	config, err := profile.Load(path)
+	if err != nil {
+		return err
+	}
+	name := config.Name(fs.profile)
+	current := config.Profiles[name]
+	if current == nil {
+		current = &profile.Profile{}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var p *profile.Profile
+	switch *method {
+	case "oauth1":
+		p, err = fs.loginOAuth1(ctx, current)
+	case "oauth2":
+		if *clientID == "" {
+			*clientID = current.ClientID
+		}
+		if *clientID == "" {
+			fmt.Fprintln(e.stderr, "flag -client-id is required for oauth2")
+			return errUsage
+		}
+		p, err = fs.loginOAuth2(ctx, *clientID, *clientSecret, *redirectURI, strings.Fields(*scopes))
+	default:
+		return fmt.Errorf("method '%s' is invalid. It must be oauth1 or oauth2.", *method)
+	}
+	if err != nil {
+		return err
+	}
+
+	config.Set(name, p)
+	if err := config.Save(path); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stderr, "Logged in as @%s (%s). The tokens are saved to the profile '%s' in %s.\n", p.Username, p.UserID, name, path)
+	return nil
+}
+
+func (fs *flagSet) loginOAuth1(ctx context.Context, current *profile.Profile) (*profile.Profile, error) {
+	apiKey := first(fs.apiKey, os.Getenv(gotwi.APIKeyEnvName), current.APIKey)
+	apiKeySecret := first(fs.apiKeySecret, os.Getenv(gotwi.APIKeySecretEnvName), current.APIKeySecret)
+
+	rt, err := gotwi.RequestOAuth1Token(ctx, fs.e.httpClient, apiKey, apiKeySecret, gotwi.OAuth1CallbackOOB)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(fs.e.stderr, "Open the URL and authorize the app:\n\n  %s\n\nEnter the PIN: ", rt.AuthorizeURL())
+	pin, err := bufio.NewReader(fs.e.stdin).ReadString('\n')
+	if err != nil && pin == "" {
+		return nil, fmt.Errorf("failed to read the PIN: %w", err)
+	}
+
+	at, err := gotwi.AccessOAuth1Token(ctx, fs.e.httpClient, apiKey, apiKeySecret, rt, strings.TrimSpace(pin))
+	if err != nil {
+		return nil, err
+	}
+
+	return &profile.Profile{
+		AuthenticationMethod: gotwi.AuthenMethodOAuth1UserContext,
+		APIKey:               apiKey,
+		APIKeySecret:         apiKeySecret,
+		OAuthToken:           at.OAuthToken,
+		OAuthTokenSecret:     at.OAuthTokenSecret,
+		UserID:               at.UserID,
+		Username:             at.ScreenName,
+	}, nil
+}
+
+func (fs *flagSet) loginOAuth2(ctx context.Context, clientID, clientSecret, redirectURI string, scopes []string) (*profile.Profile, error) {
+	u, err := url.Parse(redirectURI)
+	if err != nil || u.Scheme != "http" || (u.Hostname() != "127.0.0.1" && u.Hostname() != "localhost") {
+		return nil, fmt.Errorf("redirect URI must be http://127.0.0.1 or http://localhost with a port.")
+	}
+
+	pkce, err := gotwi.NewOAuth2PKCE(clientID, clientSecret, redirectURI, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	ln, err := net.Listen("tcp", u.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(fs.e.stderr, "Open the URL and authorize the app:\n\n  %s\n\nWaiting for the callback on %s ...\n", pkce.AuthorizeURL(), redirectURI)
+	code, err := waitForCode(ctx, ln, u.Path, pkce.State)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := pkce.Exchange(ctx, fs.e.httpClient, code)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := gotwi.NewGotwiClient(&gotwi.NewGotwiClientInput{
+		HTTPClient:           fs.e.httpClient,
+		AuthenticationMethod: gotwi.AuthenMethodOAuth2BearerToken,
+		AccessToken:          token.AccessToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	me, err := users.UserLookupMe(ctx, c, &types.UserLookupMeParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	p := &profile.Profile{
+		AuthenticationMethod: gotwi.AuthenMethodOAuth2BearerToken,
+		ClientID:             clientID,
+		ClientSecret:         clientSecret,
+		UserID:               gotwi.StringValue(me.Data.ID),
+		Username:             gotwi.StringValue(me.Data.Username),
+	}
+	p.SetToken(token, time.Now())
+	return p, nil
+}
+
+// refreshProfile refreshes the OAuth 2.0 user token of the profile, and saves it to the profile of the config file.
+func (fs *flagSet) refreshProfile(p *profile.Profile) error {
+	pkce := &gotwi.OAuth2PKCE{ClientID: p.ClientID, ClientSecret: p.ClientSecret}
+	token, err := pkce.Refresh(context.Background(), fs.e.httpClient, p.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to refresh the access token. Run 'gotwi auth login' again: %w", err)
+	}
+	p.SetToken(token, time.Now())
+
+	path, err := fs.configPath()
+	if err != nil {
+		return err
+	}
+	config, err := profile.Load(path)
+	if err != nil {
+		return err
+	}
+	config.Set(config.Name(fs.profile), p)
+	return config.Save(path)
+}
+
+// waitForCode serves the callback on the listener until it receives the authorization code of the state,
+// and closes the listener.
+func waitForCode(ctx context.Context, ln net.Listener, path, state string) (string, error) {
+	type result struct {
+		code string
+		err  error
+	}
+	ch := make(chan result, 1)
+
+	mux := http.NewServeMux()
+	if path == "" {
+		path = "/"
+	}
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("state") != state {
+			http.Error(w, "state is invalid.", http.StatusBadRequest)
+			return
+		}
+
+		res := result{code: q.Get("code")}
+		if e := q.Get("error"); e != "" {
+			res.err = fmt.Errorf("authorization failed: %s", e)
+			fmt.Fprintln(w, "Authorization failed. You can close this window.")
+		} else {
+			fmt.Fprintln(w, "Authorized. You can close this window.")
+		}
+
+		select {
+		case ch <- res:
+		default:
+		}
+	})
+
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	select {
+	case res := <-ch:
+		return res.code, res.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("authorization is not completed: %w", ctx.Err())
+	}
+}
+
+type authStatusResponse struct {
+	Data authStatusData `json:"data"`
+}
+
+type authStatusData struct {
+	Config               string `json:"config"`
+	Profile              string `json:"profile"`
+	Current              bool   `json:"current"`
+	AuthenticationMethod string `json:"authentication_method"`
+	UserID               string `json:"user_id"`
+	Username             string `json:"username"`
+}
+
+func authStatus(e *env, args []string) error {
+	fs := newFlagSet(e, "auth", "status")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+
+	path, err := fs.configPath()
+	if err != nil {
+		return err
+	}
+	config, err := profile.Load(path)
+	if err != nil {
+		return err
+	}
+
+	name := config.Name(fs.profile)
+	p := config.Profiles[name]
+	if p == nil {
+		return fmt.Errorf("profile '%s' is not found in '%s'. Run 'gotwi auth login' to create it.", name, path)
+	}
+
+	return fs.print(&authStatusResponse{Data: authStatusData{
+		Config:               path,
+		Profile:              name,
+		Current:              config.Current == name,
+		AuthenticationMethod: string(p.ClientInput().AuthenticationMethod),
+		UserID:               p.UserID,
+		Username:             p.Username,
+	}}, nil)
+}
+
+func authUse(e *env, args []string) error {
+	fs := newFlagSet(e, "auth", "use")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("profile"); err != nil {
+		return err
+	}
+
+	path, err := fs.configPath()
+	if err != nil {
+		return err
+	}
+	config, err := profile.Load(path)
+	if err != nil {
+		return err
+	}
+	if config.Profiles[fs.profile] == nil {
+		return fmt.Errorf("profile '%s' is not found in '%s'.", fs.profile, path)
+	}
+
+	config.Current = fs.profile
+	return config.Save(path)
+}
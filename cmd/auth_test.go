@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/profile"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_authLogin_OAuth1(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/request_token":
+			if !strings.Contains(r.Header.Get("Authorization"), `oauth_callback="oob"`) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, "oauth_token=request-token&oauth_token_secret=request-secret&oauth_callback_confirmed=true")
+		case "/oauth/access_token":
+			r.ParseForm()
+			if r.PostForm.Get("oauth_verifier") != "1234" || !strings.Contains(r.Header.Get("Authorization"), `oauth_token="request-token"`) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, "oauth_token=user-token&oauth_token_secret=user-secret&user_id=42&screen_name=gopher")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	e, stdout, stderr := newTestEnv(t, srv)
+	config := filepath.Join(t.TempDir(), "gotwi", "config.json")
+	t.Setenv(profile.PathEnvName, config)
+	e.stdin = strings.NewReader("1234\n")
+
+	code := e.run([]string{"auth", "login", "-profile", "work"})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stderr.String(), "https://api.twitter.com/oauth/authorize?oauth_token=request-token")
+	assert.Contains(t, stderr.String(), "Logged in as @gopher (42)")
+
+	info, err := os.Stat(config)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	c, err := profile.Load(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "work", c.Current)
+	assert.Equal(t, &profile.Profile{
+		AuthenticationMethod: gotwi.AuthenMethodOAuth1UserContext,
+		APIKey:               "api-key",
+		APIKeySecret:         "api-key-secret",
+		OAuthToken:           "user-token",
+		OAuthTokenSecret:     "user-secret",
+		UserID:               "42",
+		Username:             "gopher",
+	}, c.Profiles["work"])
+
+	code = e.run([]string{"auth", "status"})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.JSONEq(t, fmt.Sprintf(`{"data":{
+		"config":%q,
+		"profile":"work",
+		"current":true,
+		"authentication_method":"OAuth 1.0a User context",
+		"user_id":"42",
+		"username":"gopher"
+	}}`, config), stdout.String())
+}
+
+func Test_authStatus_NoProfile(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	e, _, stderr := newTestEnv(t, srv)
+
+	code := e.run([]string{"auth", "status"})
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), "profile 'default' is not found")
+}
+
+func Test_waitForCode(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	callback := "http://" + ln.Addr().String() + "/callback"
+
+	go func() {
+		res, err := http.Get(callback + "?state=wrong&code=bad")
+		if err == nil {
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+			res.Body.Close()
+		}
+		res, err = http.Get(callback + "?state=state&code=good")
+		if err == nil {
+			b, _ := ioutil.ReadAll(res.Body)
+			assert.Contains(t, string(b), "Authorized.")
+			res.Body.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	code, err := waitForCode(ctx, ln, "/callback", "state")
+	assert.NoError(t, err)
+	assert.Equal(t, "good", code)
+}
@@ -0,0 +1,455 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/job"
+	"github.com/michimani/gotwi/lists"
+	ltypes "github.com/michimani/gotwi/lists/types"
+	"github.com/michimani/gotwi/users"
+	"github.com/michimani/gotwi/users/types"
+)
+
+// bulkAction is an action for a target user. The interval paces the calls under the rate limit of the endpoint.
+type bulkAction struct {
+	name     string
+	summary  string
+	list     bool
+	interval time.Duration
+	do       func(ctx context.Context, c *gotwi.GotwiClient, sourceID, targetID string) error
+}
+
+// The rate limits are per user per 15 minutes.
+// https://developer.twitter.com/en/docs/twitter-api/rate-limits
+var bulkActions = []bulkAction{
+	{
+		name: "block", summary: "Block the users.", interval: 15 * time.Minute / 50,
+		do: func(ctx context.Context, c *gotwi.GotwiClient, sourceID, targetID string) error {
+			_, err := users.BlocksBlockingPost(ctx, c, &types.BlocksBlockingPostParams{ID: sourceID, TargetUserID: gotwi.String(targetID)})
+			return err
+		},
+	},
+	{
+		name: "unblock", summary: "Unblock the users.", interval: 15 * time.Minute / 50,
+		do: func(ctx context.Context, c *gotwi.GotwiClient, sourceID, targetID string) error {
+			_, err := users.BlocksBlockingDelete(ctx, c, &types.BlocksBlockingDeleteParams{SourceUserID: sourceID, TargetUserID: targetID})
+			return err
+		},
+	},
+	{
+		name: "mute", summary: "Mute the users.", interval: 15 * time.Minute / 50,
+		do: func(ctx context.Context, c *gotwi.GotwiClient, sourceID, targetID string) error {
+			_, err := users.MutesMutingPost(ctx, c, &types.MutesMutingPostParams{ID: sourceID, TargetUserID: gotwi.String(targetID)})
+			return err
+		},
+	},
+	{
+		name: "unmute", summary: "Unmute the users.", interval: 15 * time.Minute / 50,
+		do: func(ctx context.Context, c *gotwi.GotwiClient, sourceID, targetID string) error {
+			_, err := users.MutesMutingDelete(ctx, c, &types.MutesMutingDeleteParams{SourceUserID: sourceID, TargetUserID: targetID})
+			return err
+		},
+	},
+	{
+		name: "follow", summary: "Follow the users.", interval: 15 * time.Minute / 50,
+		do: func(ctx context.Context, c *gotwi.GotwiClient, sourceID, targetID string) error {
+			_, err := users.FollowsFollowingPost(ctx, c, &types.FollowsFollowingPostParams{ID: sourceID, TargetUserID: gotwi.String(targetID)})
+			return err
+		},
+	},
+	{
+		name: "unfollow", summary: "Unfollow the users.", interval: 15 * time.Minute / 50,
+		do: func(ctx context.Context, c *gotwi.GotwiClient, sourceID, targetID string) error {
+			_, err := users.FollowsFollowingDelete(ctx, c, &types.FollowsFollowingDeleteParams{SourceUserID: sourceID, TargetUserID: targetID})
+			return err
+		},
+	},
+	{
+		name: "list-add", summary: "Add the users to the list of -list-id.", list: true, interval: 15 * time.Minute / 300,
+		do: func(ctx context.Context, c *gotwi.GotwiClient, listID, targetID string) error {
+			_, err := lists.ListMembersPost(ctx, c, &ltypes.ListMembersPostParams{ID: listID, UserID: gotwi.String(targetID)})
+			return err
+		},
+	},
+	{
+		name: "list-remove", summary: "Remove the users from the list of -list-id.", list: true, interval: 15 * time.Minute / 300,
+		do: func(ctx context.Context, c *gotwi.GotwiClient, listID, targetID string) error {
+			_, err := lists.ListMembersDelete(ctx, c, &ltypes.ListMembersDeleteParams{ID: listID, UserID: targetID})
+			return err
+		},
+	},
+}
+
+func init() {
+	for _, a := range bulkActions {
+		a := a
+		register("bulk", command{a.name, a.summary, func(e *env, args []string) error {
+			return bulkRun(e, a, args)
+		}})
+	}
+}
+
+// The statuses of a row in the results CSV. The rows of ok and not_found are skipped on resume.
+const (
+	bulkStatusOK       = "ok"
+	bulkStatusNotFound = "not_found"
+	bulkStatusError    = "error"
+	bulkStatusDryRun   = "dry_run"
+)
+
+// The source_id is the user ID or the list ID of the action, so that a results file is shared by the actions and the sources.
+var bulkResultsHeader = []string{"row", "input", "user_id", "username", "action", "source_id", "status", "error"}
+
+// bulkTarget is a row of the input CSV. The row is the number of the record, where empty lines are not counted.
+type bulkTarget struct {
+	row      int
+	input    string
+	userID   string
+	username string
+}
+
+// key identifies the target in the results, regardless of the row number.
+func (t *bulkTarget) key() string {
+	if t.username != "" {
+		return "@" + strings.ToLower(t.username)
+	}
+	return t.userID
+}
+
+// readBulkTargets reads the CSV of the users. If the header has "id" or "username" columns, they are used.
+// Otherwise the first column is a user ID if it is numeric, or a username with or without "@".
+func readBulkTargets(r io.Reader) ([]*bulkTarget, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	idCol, nameCol, start := -1, -1, 0
+	if len(records) > 0 {
+		for i, h := range records[0] {
+			switch strings.ToLower(strings.TrimSpace(h)) {
+			case "id", "user_id":
+				idCol = i
+			case "username":
+				nameCol = i
+			}
+		}
+		if idCol >= 0 || nameCol >= 0 {
+			start = 1
+		}
+	}
+
+	targets := []*bulkTarget{}
+	for i := start; i < len(records); i++ {
+		rec := records[i]
+		t := &bulkTarget{row: i + 1}
+		switch {
+		case idCol >= 0 && idCol < len(rec) && strings.TrimSpace(rec[idCol]) != "":
+			t.userID = strings.TrimSpace(rec[idCol])
+			t.input = t.userID
+		case nameCol >= 0 && nameCol < len(rec):
+			t.input = strings.TrimSpace(rec[nameCol])
+			t.username = strings.TrimPrefix(t.input, "@")
+		case start == 0 && len(rec) > 0:
+			t.input = strings.TrimSpace(rec[0])
+			if _, err := strconv.ParseUint(t.input, 10, 64); err == nil {
+				t.userID = t.input
+			} else {
+				t.username = strings.TrimPrefix(t.input, "@")
+			}
+		}
+		if t.userID == "" && t.username == "" {
+			continue
+		}
+		targets = append(targets, t)
+	}
+
+	return targets, nil
+}
+
+// readBulkDone returns the keys of the targets that are done by the action for the source in the results CSV.
+// The rows of the other actions and sources are ignored. The last row of a target wins,
+// so a target that failed and succeeded later is done.
+func readBulkDone(path, action, sourceID string) (map[string]bool, error) {
+	done := map[string]bool{}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cr := csv.NewReader(f)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("results file '%s' is invalid: %w", path, err)
+	}
+
+	if len(records) == 0 {
+		return done, nil
+	}
+	col := map[string]int{}
+	for i, h := range records[0] {
+		col[h] = i
+	}
+	for _, h := range bulkResultsHeader {
+		if _, ok := col[h]; !ok {
+			return nil, fmt.Errorf("results file '%s' is invalid: '%s' column is missing.", path, h)
+		}
+	}
+
+	for _, rec := range records[1:] {
+		if len(rec) != len(records[0]) || rec[col["action"]] != action || rec[col["source_id"]] != sourceID {
+			continue
+		}
+		t := &bulkTarget{userID: rec[col["user_id"]], username: rec[col["username"]]}
+		status := rec[col["status"]]
+		done[t.key()] = status == bulkStatusOK || status == bulkStatusNotFound
+	}
+
+	return done, nil
+}
+
+// resolveUsernames sets the user IDs of the targets by UserLookupBy, 100 usernames per call.
+func resolveUsernames(ctx context.Context, c *gotwi.GotwiClient, targets []*bulkTarget) error {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, t := range targets {
+		if t.userID == "" && !seen[strings.ToLower(t.username)] {
+			seen[strings.ToLower(t.username)] = true
+			names = append(names, t.username)
+		}
+	}
+
+	ids := map[string]string{}
+	for len(names) > 0 {
+		n := len(names)
+		if n > 100 {
+			n = 100
+		}
+
+		var res *types.UserLookupByResponse
+		err := retryRateLimit(ctx, c, func() error {
+			var err error
+			res, err = users.UserLookupBy(ctx, c, &types.UserLookupByParams{Usernames: names[:n]})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		for _, u := range res.Data {
+			ids[strings.ToLower(gotwi.StringValue(u.Username))] = gotwi.StringValue(u.ID)
+		}
+		names = names[n:]
+	}
+
+	for _, t := range targets {
+		if t.userID == "" {
+			t.userID = ids[strings.ToLower(t.username)]
+		}
+	}
+	return nil
+}
+
+// retryRateLimit calls f again after the rate limit is reset when it returns a 429 response.
+// The retries are logged by the logger of the client.
+func retryRateLimit(ctx context.Context, c *gotwi.GotwiClient, f func() error) error {
+	for {
+		err := f()
+		wait, limited := job.RateLimitWait(err)
+		if !limited {
+			return err
+		}
+		c.Debug("gotwi: retry after rate limit", "wait", wait)
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func bulkRun(e *env, a bulkAction, args []string) error {
+	fs := newFlagSet(e, "bulk", a.name)
+	input := fs.String("input", "", "CSV file of the user IDs or usernames (required)")
+	results := fs.String("results", "", "CSV file of the results, which is resumed if it exists (default: <input>-<action>-results.csv)")
+	dryRun := fs.Bool("dry-run", false, "resolve the usernames and write the results without running the action")
+	interval := fs.Duration("interval", 0, fmt.Sprintf("interval between the calls (default: %s by the rate limit)", a.interval))
+	var sourceID *string
+	if a.list {
+		sourceID = fs.String("list-id", "", "list ID (required)")
+	} else {
+		sourceID = fs.String("user-id", "", "authenticated user ID (default: the user of the profile)")
+	}
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if !a.list && *sourceID == "" {
+		p, err := fs.loadProfile()
+		if err != nil {
+			return err
+		}
+		*sourceID = p.UserID
+	}
+	if a.list {
+		if err := fs.required("input", "list-id"); err != nil {
+			return err
+		}
+	} else if err := fs.required("input", "user-id"); err != nil {
+		return err
+	}
+	if *results == "" {
+		*results = strings.TrimSuffix(*input, filepath.Ext(*input)) + "-" + a.name + "-results.csv"
+	}
+	if *interval == 0 {
+		*interval = a.interval
+	}
+
+	in, err := os.Open(*input)
+	if err != nil {
+		return err
+	}
+	targets, err := readBulkTargets(in)
+	in.Close()
+	if err != nil {
+		return fmt.Errorf("input file '%s' is invalid: %w", *input, err)
+	}
+
+	done, err := readBulkDone(*results, a.name, *sourceID)
+	if err != nil {
+		return err
+	}
+	pending := []*bulkTarget{}
+	seen := map[string]bool{}
+	for _, t := range targets {
+		if !done[t.key()] && !seen[t.key()] {
+			seen[t.key()] = true
+			pending = append(pending, t)
+		}
+	}
+	skipped := len(targets) - len(pending)
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := resolveUsernames(ctx, c, pending); err != nil {
+		return err
+	}
+
+	w, err := openBulkResults(*results)
+	if err != nil {
+		return err
+	}
+	defer w.close()
+
+	counts := map[string]int{}
+	var last time.Time
+	for _, t := range pending {
+		status, msg := bulkStatusOK, ""
+		switch {
+		case t.userID == "":
+			status, msg = bulkStatusNotFound, "username is not found"
+		case *dryRun:
+			status = bulkStatusDryRun
+		default:
+			if err := sleepContext(ctx, time.Until(last.Add(*interval))); err != nil {
+				break
+			}
+			err := retryRateLimit(ctx, c, func() error {
+				last = time.Now()
+				return a.do(ctx, c, *sourceID, t.userID)
+			})
+			if err != nil {
+				status, msg = bulkStatusError, err.Error()
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		counts[status]++
+		if err := w.write([]string{strconv.Itoa(t.row), t.input, t.userID, t.username, a.name, *sourceID, status, msg}); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(e.stderr, "%s: %d ok, %d dry run, %d not found, %d failed, %d skipped as done or duplicated. The results are written to %s.\n",
+		a.name, counts[bulkStatusOK], counts[bulkStatusDryRun], counts[bulkStatusNotFound], counts[bulkStatusError], skipped, *results)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("interrupted. Run the same command again to resume.")
+	}
+	if counts[bulkStatusError] > 0 {
+		return errors.New("some actions failed. Run the same command again to retry them.")
+	}
+	return nil
+}
+
+// bulkResults appends the rows to the results CSV, and flushes each row so that the results survive a crash.
+type bulkResults struct {
+	f *os.File
+	w *csv.Writer
+}
+
+func openBulkResults(path string) (*bulkResults, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	r := &bulkResults{f: f, w: csv.NewWriter(f)}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.Size() == 0 {
+		if err := r.write(bulkResultsHeader); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *bulkResults) write(row []string) error {
+	if err := r.w.Write(row); err != nil {
+		return err
+	}
+	r.w.Flush()
+	return r.w.Error()
+}
+
+func (r *bulkResults) close() error {
+	return r.f.Close()
+}
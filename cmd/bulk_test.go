@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_readBulkTargets(t *testing.T) {
+	cases := []struct {
+		name   string
+		csv    string
+		expect []*bulkTarget
+	}{
+		{
+			name: "no header",
+			csv:  "@alice\n12345\n\nbob,ignored\n",
+			expect: []*bulkTarget{
+				{row: 1, input: "@alice", username: "alice"},
+				{row: 2, input: "12345", userID: "12345"},
+				{row: 3, input: "bob", username: "bob"},
+			},
+		},
+		{
+			name: "header",
+			csv:  "note,username,id\nx,alice,\ny,,12345\nz,@bob,678\n",
+			expect: []*bulkTarget{
+				{row: 2, input: "alice", username: "alice"},
+				{row: 3, input: "12345", userID: "12345"},
+				{row: 4, input: "678", userID: "678"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			targets, err := readBulkTargets(strings.NewReader(c.csv))
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, targets)
+		})
+	}
+}
+
+func Test_bulkBlock(t *testing.T) {
+	var mu sync.Mutex
+	blocked := []string{}
+	limited := false
+	fail := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/2/users/by":
+			// ghost is not looked up again once it is recorded as not found
+			assert.Contains(t, []string{"alice,ghost", "alice"}, r.URL.Query().Get("usernames"))
+			fmt.Fprint(w, `{"data":[{"id":"1","username":"Alice"}],"errors":[{"value":"ghost","title":"Not Found Error"}]}`)
+		case r.URL.Path == "/2/users/99/blocking" && r.Method == "POST":
+			b, _ := ioutil.ReadAll(r.Body)
+			target := strings.Split(string(b), `"`)[3]
+			if target == "3" && !limited {
+				limited = true
+				w.Header().Set("x-rate-limit-reset", strconv.FormatInt(time.Now().Unix(), 10))
+				w.WriteHeader(http.StatusTooManyRequests)
+				fmt.Fprint(w, `{"title":"Too Many Requests"}`)
+				return
+			}
+			if target == "4" && fail {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, `{"title":"Internal Server Error"}`)
+				return
+			}
+			blocked = append(blocked, target)
+			fmt.Fprint(w, `{"data":{"blocking":true}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "users.csv")
+	if err := ioutil.WriteFile(input, []byte("@alice\nghost\n3\n4\n3\nALICE\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	results := filepath.Join(dir, "users-block-results.csv")
+	e, _, stderr := newTestEnv(t, srv)
+
+	// dry run
+	code := e.run([]string{"bulk", "block", "-input", input, "-user-id", "99", "-dry-run"})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Empty(t, blocked)
+	assert.Contains(t, stderr.String(), "block: 0 ok, 3 dry run, 1 not found, 0 failed, 2 skipped as done or duplicated.")
+	assert.Equal(t, [][]string{
+		bulkResultsHeader,
+		{"1", "@alice", "1", "alice", "block", "99", "dry_run", ""},
+		{"2", "ghost", "", "ghost", "block", "99", "not_found", "username is not found"},
+		{"3", "3", "3", "", "block", "99", "dry_run", ""},
+		{"4", "4", "4", "", "block", "99", "dry_run", ""},
+	}, readResults(t, results))
+
+	// the 429 response is retried, and the 500 response is recorded as an error
+	stderr.Reset()
+	code = e.run([]string{"bulk", "block", "-input", input, "-user-id", "99", "-interval", "1ms", "-debug"})
+	assert.Equal(t, 1, code)
+	assert.Equal(t, []string{"1", "3"}, blocked)
+	assert.Contains(t, stderr.String(), "gotwi: retry after rate limit wait=")
+	assert.Contains(t, stderr.String(), "block: 2 ok, 0 dry run, 0 not found, 1 failed, 3 skipped as done or duplicated.")
+	rows := readResults(t, results)
+	assert.Len(t, rows, 8)
+	assert.Equal(t, []string{"4", "4", "4", "", "block", "99", "error"}, rows[7][:7])
+
+	// resume only the failed row
+	fail = false
+	stderr.Reset()
+	code = e.run([]string{"bulk", "block", "-input", input, "-user-id", "99", "-interval", "1ms"})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, []string{"1", "3", "4"}, blocked)
+	assert.Contains(t, stderr.String(), "block: 1 ok, 0 dry run, 0 not found, 0 failed, 5 skipped as done or duplicated.")
+}
+
+func Test_bulk_actions(t *testing.T) {
+	var mu sync.Mutex
+	calls := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && (strings.HasSuffix(r.URL.Path, "/blocking") || strings.HasSuffix(r.URL.Path, "/muting")):
+			b, _ := ioutil.ReadAll(r.Body)
+			calls = append(calls, r.URL.Path+" "+strings.Split(string(b), `"`)[3])
+			fmt.Fprint(w, `{"data":{"blocking":true}}`)
+		case r.Method == "POST" && strings.HasPrefix(r.URL.Path, "/2/lists/"):
+			b, _ := ioutil.ReadAll(r.Body)
+			calls = append(calls, r.URL.Path+" "+strings.Split(string(b), `"`)[3])
+			fmt.Fprint(w, `{"data":{"is_member":true}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "users.csv")
+	if err := ioutil.WriteFile(input, []byte("1\n2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	shared := filepath.Join(dir, "shared.csv")
+
+	cases := []struct {
+		name   string
+		args   []string
+		expect []string
+	}{
+		{
+			name:   "block",
+			args:   []string{"bulk", "block", "-input", input, "-user-id", "99", "-interval", "1ms"},
+			expect: []string{"/2/users/99/blocking 1", "/2/users/99/blocking 2"},
+		},
+		{
+			name:   "mute after block with the default results",
+			args:   []string{"bulk", "mute", "-input", input, "-user-id", "99", "-interval", "1ms"},
+			expect: []string{"/2/users/99/muting 1", "/2/users/99/muting 2"},
+		},
+		{
+			name:   "block again is skipped",
+			args:   []string{"bulk", "block", "-input", input, "-user-id", "99", "-interval", "1ms"},
+			expect: []string{},
+		},
+		{
+			name:   "list-add",
+			args:   []string{"bulk", "list-add", "-input", input, "-list-id", "10", "-interval", "1ms", "-results", shared},
+			expect: []string{"/2/lists/10/members 1", "/2/lists/10/members 2"},
+		},
+		{
+			name:   "list-add to another list with the same results",
+			args:   []string{"bulk", "list-add", "-input", input, "-list-id", "20", "-interval", "1ms", "-results", shared},
+			expect: []string{"/2/lists/20/members 1", "/2/lists/20/members 2"},
+		},
+		{
+			name:   "mute with the same results",
+			args:   []string{"bulk", "mute", "-input", input, "-user-id", "99", "-interval", "1ms", "-results", shared},
+			expect: []string{"/2/users/99/muting 1", "/2/users/99/muting 2"},
+		},
+	}
+
+	for _, c := range cases {
+		mu.Lock()
+		calls = []string{}
+		mu.Unlock()
+
+		e, _, stderr := newTestEnv(t, srv)
+		code := e.run(c.args)
+		assert.Equal(t, 0, code, c.name+": "+stderr.String())
+		assert.Equal(t, c.expect, calls, c.name)
+	}
+
+	for _, name := range []string{"users-block-results.csv", "users-mute-results.csv"} {
+		assert.Len(t, readResults(t, filepath.Join(dir, name)), 3, name)
+	}
+	assert.Len(t, readResults(t, shared), 7)
+}
+
+func Test_readBulkDone_invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.csv")
+	if err := ioutil.WriteFile(path, []byte("row,input,user_id,username,action,status,error\n1,1,1,,block,ok,\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	done, err := readBulkDone(path, "block", "99")
+	assert.Nil(t, done)
+	assert.EqualError(t, err, "results file '"+path+"' is invalid: 'source_id' column is missing.")
+}
+
+func readResults(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rows
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/fields"
+	"github.com/michimani/gotwi/profile"
+)
+
+const (
+	// OAuthTokenEnvName and OAuthTokenSecretEnvName are the environment variables of the user token.
+	OAuthTokenEnvName       = "GOTWI_ACCESS_TOKEN"
+	OAuthTokenSecretEnvName = "GOTWI_ACCESS_TOKEN_SECRET"
+)
+
+// errUsage is returned when the flags are invalid. The usage is already printed.
+var errUsage = errors.New("usage")
+
+type flagSet struct {
+	*flag.FlagSet
+	e *env
+
+	// credentials
+	profile      string
+	config       string
+	auth         string
+	apiKey       string
+	apiKeySecret string
+	token        string
+	tokenSecret  string
+
+	output string
+	debug  bool
+	fields map[string]*string
+}
+
+func newFlagSet(e *env, group, name string) *flagSet {
+	fs := &flagSet{
+		FlagSet: flag.NewFlagSet("gotwi "+group+" "+name, flag.ContinueOnError),
+		e:       e,
+		fields:  map[string]*string{},
+	}
+	fs.SetOutput(e.stderr)
+
+	fs.StringVar(&fs.profile, "profile", "", "profile name in the config file (env: "+profile.NameEnvName+")")
+	fs.StringVar(&fs.config, "config", "", "config file path (env: "+profile.PathEnvName+")")
+	fs.StringVar(&fs.auth, "auth", "", "authentication method: oauth1 or oauth2")
+	fs.StringVar(&fs.apiKey, "api-key", "", "API key (env: "+gotwi.APIKeyEnvName+")")
+	fs.StringVar(&fs.apiKeySecret, "api-key-secret", "", "API key secret (env: "+gotwi.APIKeySecretEnvName+")")
+	fs.StringVar(&fs.token, "access-token", "", "access token for OAuth 1.0a (env: "+OAuthTokenEnvName+")")
+	fs.StringVar(&fs.tokenSecret, "access-token-secret", "", "access token secret for OAuth 1.0a (env: "+OAuthTokenSecretEnvName+")")
+	fs.StringVar(&fs.output, "output", "json", "output format: json or table")
+	fs.BoolVar(&fs.debug, "debug", false, "log the requests, the responses and the retries to stderr")
+
+	return fs
+}
+
+// fieldFlags adds the flags of the expansions and the fields of the kinds, such as "tweet" for -tweet-fields.
+func (fs *flagSet) fieldFlags(kinds ...string) {
+	fs.fields["expansions"] = fs.String("expansions", "", "comma separated expansions")
+	for _, k := range kinds {
+		fs.fields[k] = fs.String(k+"-fields", "", "comma separated "+k+" fields")
+	}
+}
+
+func (fs *flagSet) parse(args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.e.stderr, "unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		fs.Usage()
+		return errUsage
+	}
+	if fs.output != "json" && fs.output != "table" {
+		fmt.Fprintf(fs.e.stderr, "invalid value %q for flag -output\n", fs.output)
+		return errUsage
+	}
+	return nil
+}
+
+// required returns an error if any of the flags is empty.
+func (fs *flagSet) required(names ...string) error {
+	for _, n := range names {
+		f := fs.Lookup(n)
+		if f == nil || f.Value.String() == "" {
+			fmt.Fprintf(fs.e.stderr, "flag -%s is required\n", n)
+			fs.Usage()
+			return errUsage
+		}
+	}
+	return nil
+}
+
+func (fs *flagSet) loadProfile() (*profile.Profile, error) {
+	path, err := fs.configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := profile.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := c.Profile(fs.profile)
+	if p == nil {
+		if fs.profile != "" {
+			return nil, fmt.Errorf("profile '%s' is not found in '%s'.", fs.profile, path)
+		}
+		p = &profile.Profile{}
+	}
+
+	return p, nil
+}
+
+// client returns GotwiClient with the credentials of the flags, the environment variables and the profile, in this order.
+// When a profile is selected by -profile or GOTWI_PROFILE, the environment variables of the credentials are not used,
+// so that the credentials of the profile are not mixed with the ones of another account.
+func (fs *flagSet) client() (*gotwi.GotwiClient, error) {
+	p, err := fs.loadProfile()
+	if err != nil {
+		return nil, err
+	}
+	if p.NeedsRefresh(time.Now()) {
+		if err := fs.refreshProfile(p); err != nil {
+			return nil, err
+		}
+	}
+
+	getenv := os.Getenv
+	if fs.profile != "" || os.Getenv(profile.NameEnvName) != "" {
+		getenv = func(string) string { return "" }
+	}
+
+	in := p.ClientInput()
+	in.HTTPClient = fs.e.httpClient
+	if fs.debug {
+		in.Logger = stderrLogger{w: fs.e.stderr}
+	}
+	in.APIKey = first(fs.apiKey, getenv(gotwi.APIKeyEnvName), in.APIKey)
+	in.APIKeySecret = first(fs.apiKeySecret, getenv(gotwi.APIKeySecretEnvName), in.APIKeySecret)
+	in.OAuthToken = first(fs.token, getenv(OAuthTokenEnvName), in.OAuthToken)
+	in.OAuthTokenSecret = first(fs.tokenSecret, getenv(OAuthTokenSecretEnvName), in.OAuthTokenSecret)
+
+	switch fs.auth {
+	case "oauth1":
+		in.AuthenticationMethod = gotwi.AuthenMethodOAuth1UserContext
+	case "oauth2":
+		in.AuthenticationMethod = gotwi.AuthenMethodOAuth2BearerToken
+	case "":
+		if p.AuthenticationMethod == "" && in.OAuthToken != "" {
+			in.AuthenticationMethod = gotwi.AuthenMethodOAuth1UserContext
+		}
+	default:
+		return nil, fmt.Errorf("auth '%s' is invalid. It must be oauth1 or oauth2.", fs.auth)
+	}
+
+	return gotwi.NewGotwiClient(in)
+}
+
+// stderrLogger writes the debug logs to w in the form of "msg key=value ...".
+type stderrLogger struct {
+	w io.Writer
+}
+
+func (l stderrLogger) Debug(msg string, args ...interface{}) {
+	b := &strings.Builder{}
+	b.WriteString(msg)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(b, " %v=%v", args[i], args[i+1])
+	}
+	fmt.Fprintln(l.w, b.String())
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func (fs *flagSet) list(kind string) []string {
+	v, ok := fs.fields[kind]
+	if !ok {
+		return nil
+	}
+	return splitList(*v)
+}
+
+func (fs *flagSet) expansions() fields.ExpansionList {
+	l := fields.ExpansionList{}
+	for _, v := range fs.list("expansions") {
+		l = append(l, fields.Expansion(v))
+	}
+	return l
+}
+
+func (fs *flagSet) tweetFields() fields.TweetFieldList {
+	l := fields.TweetFieldList{}
+	for _, v := range fs.list("tweet") {
+		l = append(l, fields.TweetField(v))
+	}
+	return l
+}
+
+func (fs *flagSet) userFields() fields.UserFieldList {
+	l := fields.UserFieldList{}
+	for _, v := range fs.list("user") {
+		l = append(l, fields.UserField(v))
+	}
+	return l
+}
+
+func (fs *flagSet) mediaFields() fields.MediaFieldList {
+	l := fields.MediaFieldList{}
+	for _, v := range fs.list("media") {
+		l = append(l, fields.MediaField(v))
+	}
+	return l
+}
+
+func (fs *flagSet) placeFields() fields.PlaceFieldList {
+	l := fields.PlaceFieldList{}
+	for _, v := range fs.list("place") {
+		l = append(l, fields.PlaceField(v))
+	}
+	return l
+}
+
+func (fs *flagSet) pollFields() fields.PollFieldList {
+	l := fields.PollFieldList{}
+	for _, v := range fs.list("poll") {
+		l = append(l, fields.PollField(v))
+	}
+	return l
+}
+
+func (fs *flagSet) listFields() fields.ListFieldList {
+	l := fields.ListFieldList{}
+	for _, v := range fs.list("list") {
+		l = append(l, fields.ListField(v))
+	}
+	return l
+}
+
+func (fs *flagSet) spaceFields() fields.SpaceFieldList {
+	l := fields.SpaceFieldList{}
+	for _, v := range fs.list("space") {
+		l = append(l, fields.SpaceField(v))
+	}
+	return l
+}
+
+// splitList splits the comma separated values, ignoring the empty values.
+func splitList(s string) []string {
+	l := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+// parseTime parses the RFC3339 time. It returns nil for the empty string.
+func parseTime(name, s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, fmt.Errorf("-%s must be RFC3339 time such as 2021-01-02T03:04:05Z.", name)
+	}
+	return &t, nil
+}
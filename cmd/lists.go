@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/export"
+	"github.com/michimani/gotwi/lists"
+	"github.com/michimani/gotwi/lists/types"
+	"github.com/michimani/gotwi/resources"
+)
+
+func init() {
+	register("lists",
+		command{"lookup", "Look up a list by ID.", listsLookup},
+		command{"owned", "Get the lists owned by a user.", listsOwned},
+		command{"create", "Create a list.", listsCreate},
+		command{"update", "Update a list.", listsUpdate},
+		command{"delete", "Delete a list.", listsDelete},
+		command{"members", "Get the members of a list.", listsMembers},
+		command{"add-member", "Add a user to a list.", listsAddMember},
+		command{"remove-member", "Remove a user from a list.", listsRemoveMember},
+	)
+}
+
+func listsLookup(e *env, args []string) error {
+	fs := newFlagSet(e, "lists", "lookup")
+	id := fs.String("id", "", "list ID (required)")
+	fs.fieldFlags("list", "user")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("id"); err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	res, err := lists.ListLookupID(context.Background(), c, &types.ListLookupIDParams{
+		ID:         *id,
+		Expansions: fs.expansions(),
+		ListFields: fs.listFields(),
+		UserFields: fs.userFields(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return fs.print(res, export.Lists([]resources.List{res.Data}, fs.listFields()))
+}
+
+func listsOwned(e *env, args []string) error {
+	fs := newFlagSet(e, "lists", "owned")
+	userID := fs.String("user-id", "", "owner user ID (required)")
+	maxResults := fs.Int("max-results", 0, "max results per page")
+	token := fs.String("pagination-token", "", "pagination token of the previous page")
+	fs.fieldFlags("list", "user")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("user-id"); err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	res, err := lists.ListLookupOwnedLists(context.Background(), c, &types.ListLookupOwnedListsParams{
+		ID:              *userID,
+		MaxResults:      types.ListLookupOwnedListsMaxResults(*maxResults),
+		PaginationToken: *token,
+		Expansions:      fs.expansions(),
+		ListFields:      fs.listFields(),
+		UserFields:      fs.userFields(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return fs.print(res, export.Lists(res.Data, fs.listFields()))
+}
+
+func listsCreate(e *env, args []string) error {
+	fs := newFlagSet(e, "lists", "create")
+	name := fs.String("name", "", "list name (required)")
+	description := fs.String("description", "", "list description")
+	private := fs.Bool("private", false, "make the list private")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("name"); err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	p := &types.ManageListsPostParams{
+		Name:    gotwi.String(*name),
+		Private: gotwi.Bool(*private),
+	}
+	if *description != "" {
+		p.Description = gotwi.String(*description)
+	}
+
+	res, err := lists.ManageListsPost(context.Background(), c, p)
+	if err != nil {
+		return err
+	}
+
+	return fs.print(res, nil)
+}
+
+func listsUpdate(e *env, args []string) error {
+	fs := newFlagSet(e, "lists", "update")
+	id := fs.String("id", "", "list ID (required)")
+	name := fs.String("name", "", "new list name")
+	description := fs.String("description", "", "new list description")
+	private := fs.String("private", "", "true or false to change the visibility")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("id"); err != nil {
+		return err
+	}
+
+	p := &types.ManageListsPutParams{ID: *id}
+	if *name != "" {
+		p.Name = gotwi.String(*name)
+	}
+	if *description != "" {
+		p.Description = gotwi.String(*description)
+	}
+	switch *private {
+	case "":
+	case "true":
+		p.Private = gotwi.Bool(true)
+	case "false":
+		p.Private = gotwi.Bool(false)
+	default:
+		return fmt.Errorf("private '%s' is invalid. It must be true or false.", *private)
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	res, err := lists.ManageListsPut(context.Background(), c, p)
+	if err != nil {
+		return err
+	}
+
+	return fs.print(res, nil)
+}
+
+func listsDelete(e *env, args []string) error {
+	fs := newFlagSet(e, "lists", "delete")
+	id := fs.String("id", "", "list ID (required)")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("id"); err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	res, err := lists.ManageListsDelete(context.Background(), c, &types.ManageListsDeleteParams{ID: *id})
+	if err != nil {
+		return err
+	}
+
+	return fs.print(res, nil)
+}
+
+func listsMembers(e *env, args []string) error {
+	fs := newFlagSet(e, "lists", "members")
+	id := fs.String("id", "", "list ID (required)")
+	maxResults := fs.Int("max-results", 0, "max results per page")
+	token := fs.String("pagination-token", "", "pagination token of the previous page")
+	fs.fieldFlags("user", "list")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("id"); err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	res, err := lists.ListMembersGet(context.Background(), c, &types.ListMembersGetParams{
+		ID:              *id,
+		MaxResults:      types.ListMembersGetMaxResults(*maxResults),
+		PaginationToken: *token,
+		Expansions:      fs.expansions(),
+		ListFields:      fs.listFields(),
+		UserFields:      fs.userFields(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return fs.print(res, export.Users(res.Data, fs.userFields()))
+}
+
+func listsAddMember(e *env, args []string) error {
+	fs := newFlagSet(e, "lists", "add-member")
+	id := fs.String("id", "", "list ID (required)")
+	userID := fs.String("user-id", "", "user ID to add (required)")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("id", "user-id"); err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	res, err := lists.ListMembersPost(context.Background(), c, &types.ListMembersPostParams{
+		ID:     *id,
+		UserID: gotwi.String(*userID),
+	})
+	if err != nil {
+		return err
+	}
+
+	return fs.print(res, nil)
+}
+
+func listsRemoveMember(e *env, args []string) error {
+	fs := newFlagSet(e, "lists", "remove-member")
+	id := fs.String("id", "", "list ID (required)")
+	userID := fs.String("user-id", "", "user ID to remove (required)")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("id", "user-id"); err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	res, err := lists.ListMembersDelete(context.Background(), c, &types.ListMembersDeleteParams{
+		ID:     *id,
+		UserID: *userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return fs.print(res, nil)
+}
@@ -1,4 +1,110 @@
+// Command gotwi calls the Twitter API v2 with the gotwi library.
+//
+//	gotwi <group> <command> [flags]
+//
+// Run `gotwi help` for the commands, and `gotwi <group> <command> -h` for the flags of a command.
 package main
 
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// env is the environment of a command run.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	// httpClient is used by the GotwiClient if not nil.
+	httpClient *http.Client
+}
+
+type command struct {
+	name    string
+	summary string
+	run     func(e *env, args []string) error
+}
+
+// groups is the commands by group. The groups are registered by init of each file.
+var groups = map[string][]command{}
+
+func register(group string, cmds ...command) {
+	groups[group] = append(groups[group], cmds...)
+}
+
 func main() {
+	e := &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	os.Exit(e.run(os.Args[1:]))
+}
+
+// run runs the command of the args and returns the exit code.
+func (e *env) run(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		e.usage()
+		return 0
+	}
+
+	cmds, ok := groups[args[0]]
+	if !ok {
+		fmt.Fprintf(e.stderr, "unknown command '%s'.\n\n", args[0])
+		e.usage()
+		return 2
+	}
+
+	if len(args) < 2 {
+		e.groupUsage(args[0])
+		return 2
+	}
+
+	for _, c := range cmds {
+		if c.name != args[1] {
+			continue
+		}
+
+		if err := c.run(e, args[2:]); err != nil {
+			if err == errUsage {
+				return 2
+			}
+			fmt.Fprintln(e.stderr, "error:", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(e.stderr, "unknown command '%s %s'.\n\n", args[0], args[1])
+	e.groupUsage(args[0])
+	return 2
+}
+
+func (e *env) usage() {
+	fmt.Fprintln(e.stderr, "Usage: gotwi <group> <command> [flags]")
+	fmt.Fprintln(e.stderr)
+	fmt.Fprintln(e.stderr, "Groups:")
+
+	names := []string{}
+	for g := range groups {
+		names = append(names, g)
+	}
+	sort.Strings(names)
+	for _, g := range names {
+		cs := []string{}
+		for _, c := range groups[g] {
+			cs = append(cs, c.name)
+		}
+		fmt.Fprintf(e.stderr, "  %-8s %s\n", g, strings.Join(cs, ", "))
+	}
+
+	fmt.Fprintln(e.stderr)
+	fmt.Fprintln(e.stderr, "Run 'gotwi <group> <command> -h' for the flags of a command.")
+}
+
+func (e *env) groupUsage(group string) {
+	fmt.Fprintf(e.stderr, "Usage: gotwi %s <command> [flags]\n\nCommands:\n", group)
+	for _, c := range groups[group] {
+		fmt.Fprintf(e.stderr, "  %-14s %s\n", c.name, c.summary)
+	}
 }
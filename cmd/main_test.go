@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/internal/testutil"
+	"github.com/michimani/gotwi/profile"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_run(t *testing.T) {
+	var lastRequest *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastRequest = r
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/2/tweets":
+			fmt.Fprint(w, `{"data":[{"id":"1","text":"hello","author_id":"10","public_metrics":{"retweet_count":1,"reply_count":0,"like_count":2,"quote_count":0}}],"includes":{"users":[{"id":"10","name":"Gopher","username":"gopher"}]}}`)
+		case "/2/users/1/blocking":
+			fmt.Fprint(w, `{"data":{"blocking":true}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"title":"Not Found Error"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cases := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantOut    string
+		wantErr    string
+		wantPath   string
+		wantMethod string
+	}{
+		{
+			name:     "tweets lookup as table",
+			args:     []string{"tweets", "lookup", "-ids", "1", "-output", "table", "-tweet-fields", "author_id,public_metrics", "-expansions", "author_id"},
+			wantCode: 0,
+			wantOut: "id  text   author_id  public_metrics.retweet_count  public_metrics.reply_count  public_metrics.like_count  public_metrics.quote_count  author.name  author.username\n" +
+				"1   hello  10         1                             0                           2                          0                           Gopher       gopher\n",
+			wantPath:   "/2/tweets",
+			wantMethod: http.MethodGet,
+		},
+		{
+			name:       "users block as table",
+			args:       []string{"users", "block", "-user-id", "1", "-target-id", "2", "-output", "table"},
+			wantCode:   0,
+			wantOut:    "blocking\ntrue\n",
+			wantPath:   "/2/users/1/blocking",
+			wantMethod: http.MethodPost,
+		},
+		{
+			name:     "required flag",
+			args:     []string{"tweets", "lookup"},
+			wantCode: 2,
+			wantErr:  "flag -ids is required",
+		},
+		{
+			name:     "unknown command",
+			args:     []string{"tweets", "unknown"},
+			wantCode: 2,
+			wantErr:  "unknown command 'tweets unknown'",
+		},
+		{
+			name:     "API error",
+			args:     []string{"spaces", "lookup", "-ids", "1"},
+			wantCode: 1,
+			wantErr:  "Not Found Error",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			lastRequest = nil
+			e, stdout, stderr := newTestEnv(tt, srv)
+
+			code := e.run(c.args)
+
+			assert.Equal(tt, c.wantCode, code, stderr.String())
+			if c.wantOut != "" {
+				assert.Equal(tt, c.wantOut, stdout.String())
+			}
+			assert.Contains(tt, stderr.String(), c.wantErr)
+			if c.wantPath != "" {
+				assert.Equal(tt, c.wantPath, lastRequest.URL.Path)
+				assert.Equal(tt, c.wantMethod, lastRequest.Method)
+			}
+		})
+	}
+}
+
+func Test_run_JSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data":[{"id":"1","name":"Gopher","username":"gopher"}]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	e, stdout, _ := newTestEnv(t, srv)
+	assert.Equal(t, 0, e.run([]string{"users", "lookup", "-usernames", "gopher"}))
+	assert.True(t, strings.HasPrefix(stdout.String(), "{\n  \"data\": [\n"))
+	assert.Contains(t, stdout.String(), `"username": "gopher"`)
+}
+
+func Test_flagSet_client(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.json")
+	err := ioutil.WriteFile(config, []byte(`{"current":"work","profiles":{
+		"work":{"api_key":"work-key","api_key_secret":"work-secret","oauth_token":"work-token","oauth_token_secret":"work-token-secret"},
+		"other":{"api_key":"other-key","api_key_secret":"other-secret","oauth_token":"other-token","oauth_token_secret":"other-token-secret"}
+	}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name      string
+		args      []string
+		envToken  string
+		envName   string
+		wantToken string
+		wantKey   string
+		wantErr   bool
+	}{
+		{name: "current profile", wantToken: "work-token", wantKey: "work-key"},
+		{name: "profile flag", args: []string{"-profile", "other"}, wantToken: "other-token", wantKey: "other-key"},
+		{name: "env overrides profile", envToken: "env-token", wantToken: "env-token", wantKey: "work-key"},
+		{name: "flag overrides env", args: []string{"-access-token", "flag-token"}, envToken: "env-token", wantToken: "flag-token", wantKey: "work-key"},
+		{name: "env is not used with profile flag", args: []string{"-profile", "other"}, envToken: "env-token", wantToken: "other-token", wantKey: "other-key"},
+		{name: "env is not used with profile env", envName: "other", envToken: "env-token", wantToken: "other-token", wantKey: "other-key"},
+		{name: "flag overrides profile flag", args: []string{"-profile", "other", "-access-token", "flag-token"}, envToken: "env-token", wantToken: "flag-token", wantKey: "other-key"},
+		{name: "unknown profile", args: []string{"-profile", "unknown"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			tt.Setenv(profile.PathEnvName, config)
+			tt.Setenv(profile.NameEnvName, c.envName)
+			tt.Setenv(gotwi.APIKeyEnvName, "")
+			tt.Setenv(gotwi.APIKeySecretEnvName, "")
+			tt.Setenv(OAuthTokenEnvName, c.envToken)
+			tt.Setenv(OAuthTokenSecretEnvName, "")
+
+			fs := newFlagSet(&env{stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}}, "test", "test")
+			assert.NoError(tt, fs.parse(c.args))
+
+			client, err := fs.client()
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+			assert.NoError(tt, err)
+			assert.Equal(tt, gotwi.AuthenticationMethod(gotwi.AuthenMethodOAuth1UserContext), client.AuthenticationMethod)
+			assert.Equal(tt, c.wantToken, client.OAuthToken)
+			assert.Equal(tt, c.wantKey, client.OAuthConsumerKey)
+		})
+	}
+}
+
+func Test_flagSet_client_refresh(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	cases := []struct {
+		name       string
+		expiresAt  time.Time
+		status     int
+		wantToken  string
+		wantCalled bool
+		wantErr    bool
+	}{
+		{name: "valid", expiresAt: time.Now().Add(time.Hour), wantToken: "old-token"},
+		{name: "expired", expiresAt: expired, status: http.StatusOK, wantToken: "new-token", wantCalled: true},
+		{name: "refresh failed", expiresAt: expired, status: http.StatusBadRequest, wantCalled: true, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			called := false
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				assert.Equal(tt, "/2/oauth2/token", r.URL.Path)
+				assert.Equal(tt, "refresh_token", r.FormValue("grant_type"))
+				assert.Equal(tt, "old-refresh", r.FormValue("refresh_token"))
+				assert.Equal(tt, "client", r.FormValue("client_id"))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(c.status)
+				if c.status != http.StatusOK {
+					fmt.Fprint(w, `{"error":"invalid_request","error_description":"Value passed for the token was invalid."}`)
+					return
+				}
+				fmt.Fprint(w, `{"token_type":"bearer","expires_in":7200,"access_token":"new-token","refresh_token":"new-refresh"}`)
+			}))
+			tt.Cleanup(srv.Close)
+			e, _, _ := newTestEnv(tt, srv)
+
+			path := os.Getenv(profile.PathEnvName)
+			config := &profile.Config{}
+			config.Set("work", &profile.Profile{
+				AuthenticationMethod: gotwi.AuthenMethodOAuth2BearerToken,
+				ClientID:             "client",
+				AccessToken:          "old-token",
+				RefreshToken:         "old-refresh",
+				ExpiresAt:            &c.expiresAt,
+			})
+			assert.NoError(tt, config.Save(path))
+
+			fs := newFlagSet(e, "test", "test")
+			assert.NoError(tt, fs.parse([]string{"-profile", "work"}))
+			client, err := fs.client()
+			assert.Equal(tt, c.wantCalled, called)
+			if c.wantErr {
+				assert.Error(tt, err)
+				return
+			}
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.wantToken, client.AccessToken)
+
+			saved, err := profile.Load(path)
+			assert.NoError(tt, err)
+			p := saved.Profile("work")
+			assert.Equal(tt, c.wantToken, p.AccessToken)
+			if c.wantCalled {
+				assert.Equal(tt, "new-refresh", p.RefreshToken)
+				assert.False(tt, p.NeedsRefresh(time.Now()))
+			}
+		})
+	}
+}
+
+func newTestEnv(t *testing.T, srv *httptest.Server) (*env, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv(profile.PathEnvName, filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv(gotwi.APIKeyEnvName, "api-key")
+	t.Setenv(gotwi.APIKeySecretEnvName, "api-key-secret")
+	t.Setenv(OAuthTokenEnvName, "token")
+	t.Setenv(OAuthTokenSecretEnvName, "token-secret")
+
+	u, _ := url.Parse(srv.URL)
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	e := &env{
+		stdout:     stdout,
+		stderr:     stderr,
+		httpClient: &http.Client{Transport: testutil.RewriteTransport{Host: u.Host}},
+	}
+	return e, stdout, stderr
+}
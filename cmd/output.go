@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/michimani/gotwi/export"
+)
+
+// print writes the response as indented JSON, or as a table with -output table.
+// If t is nil, the table is made from the data of the response.
+func (fs *flagSet) print(res interface{}, t *export.Table) error {
+	if fs.output == "json" {
+		b, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(fs.e.stdout, string(b))
+		return err
+	}
+
+	if t == nil {
+		dt, err := dataTable(res)
+		if err != nil {
+			return err
+		}
+		t = dt
+	}
+
+	return t.WriteText(fs.e.stdout)
+}
+
+// dataTable returns a table of the "data" of the response. An object is a row, and an array of objects is rows.
+// The columns are the sorted keys, and nested values are written as JSON.
+func dataTable(res interface{}) (*export.Table, error) {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+
+	body := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return nil, err
+	}
+
+	objects := []map[string]json.RawMessage{}
+	if len(body.Data) > 0 && body.Data[0] == '[' {
+		if err := json.Unmarshal(body.Data, &objects); err != nil {
+			return nil, err
+		}
+	} else if len(body.Data) > 0 && body.Data[0] == '{' {
+		o := map[string]json.RawMessage{}
+		if err := json.Unmarshal(body.Data, &o); err != nil {
+			return nil, err
+		}
+		objects = append(objects, o)
+	}
+
+	keys := []string{}
+	seen := map[string]bool{}
+	for _, o := range objects {
+		for k := range o {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	t := &export.Table{Columns: []export.Column{}, Rows: [][]interface{}{}}
+	for _, k := range keys {
+		t.Columns = append(t.Columns, export.Column{Name: k, Type: export.ColumnTypeString})
+	}
+	for _, o := range objects {
+		row := []interface{}{}
+		for _, k := range keys {
+			row = append(row, rawString(o[k]))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+
+	return t, nil
+}
+
+// rawString returns the string value as is, and other values as JSON.
+func rawString(raw json.RawMessage) interface{} {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	s := ""
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// rotateWriter appends lines to a file, and renames the file with the time it was opened
+// when it exceeds the size or the interval. Zero disables the condition.
+type rotateWriter struct {
+	path     string
+	maxSize  int64
+	interval time.Duration
+	now      func() time.Time
+
+	f      *os.File
+	size   int64
+	opened time.Time
+}
+
+func newRotateWriter(path string, maxSize int64, interval time.Duration) *rotateWriter {
+	return &rotateWriter{path: path, maxSize: maxSize, interval: interval, now: time.Now}
+}
+
+// writeLine writes the line with a new line. A line is never split across files.
+func (w *rotateWriter) writeLine(line []byte) error {
+	if w.f != nil && w.shouldRotate(int64(len(line)+1)) {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if w.f == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+
+	b := make([]byte, 0, len(line)+1)
+	n, err := w.f.Write(append(append(b, line...), '\n'))
+	w.size += int64(n)
+	return err
+}
+
+func (w *rotateWriter) shouldRotate(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.maxSize > 0 && w.size+n > w.maxSize {
+		return true
+	}
+	if w.interval > 0 && w.now().Sub(w.opened) >= w.interval {
+		return true
+	}
+	return false
+}
+
+func (w *rotateWriter) open() error {
+	if dir := filepath.Dir(w.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	w.f = f
+	w.size = info.Size()
+	w.opened = w.now()
+	return nil
+}
+
+// rotate closes the file and renames it to such as tweets-20211018T120000.jsonl.
+func (w *rotateWriter) rotate() error {
+	if err := w.f.Close(); err != nil {
+		return err
+	}
+	w.f = nil
+
+	ext := filepath.Ext(w.path)
+	base := strings.TrimSuffix(w.path, ext) + "-" + w.opened.UTC().Format("20060102T150405")
+	dst := base + ext
+	for i := 1; ; i++ {
+		if _, err := os.Stat(dst); os.IsNotExist(err) {
+			break
+		}
+		dst = fmt.Sprintf("%s.%d%s", base, i, ext)
+	}
+
+	return os.Rename(w.path, dst)
+}
+
+func (w *rotateWriter) Close() error {
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/michimani/gotwi/export"
+	"github.com/michimani/gotwi/fields"
+	"github.com/michimani/gotwi/spaces"
+	"github.com/michimani/gotwi/spaces/types"
+)
+
+func init() {
+	register("spaces",
+		command{"search", "Search spaces by title.", spacesSearch},
+		command{"lookup", "Look up spaces by IDs.", spacesLookup},
+	)
+}
+
+func spacesSearch(e *env, args []string) error {
+	fs := newFlagSet(e, "spaces", "search")
+	query := fs.String("query", "", "search query (required)")
+	state := fs.String("state", "", "live, scheduled or all")
+	maxResults := fs.Int("max-results", 0, "max results")
+	fs.fieldFlags("space", "user")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("query"); err != nil {
+		return err
+	}
+	s := fields.State(*state)
+	if s != "" && !s.Valid() {
+		return fmt.Errorf("state '%s' is invalid.", *state)
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	res, err := spaces.SearchSpaces(context.Background(), c, &types.SearchSpacesParams{
+		Query:       *query,
+		State:       s,
+		MaxResults:  types.SearchSpacesMaxResults(*maxResults),
+		Expansions:  fs.expansions(),
+		SpaceFields: fs.spaceFields(),
+		UserFields:  fs.userFields(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return fs.print(res, export.Spaces(res.Data, fs.spaceFields()))
+}
+
+func spacesLookup(e *env, args []string) error {
+	fs := newFlagSet(e, "spaces", "lookup")
+	ids := fs.String("ids", "", "comma separated space IDs (required)")
+	fs.fieldFlags("space", "user")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("ids"); err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	res, err := spaces.SpacesLookup(context.Background(), c, &types.SpacesLookupParams{
+		IDs:         splitList(*ids),
+		Expansions:  fs.expansions(),
+		SpaceFields: fs.spaceFields(),
+		UserFields:  fs.userFields(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return fs.print(res, export.Spaces(res.Data, fs.spaceFields()))
+}
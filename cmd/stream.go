@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/resources"
+	"github.com/michimani/gotwi/tweets"
+	"github.com/michimani/gotwi/tweets/types"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	register("stream",
+		command{"rules", "List the rules of the filtered stream.", streamRules},
+		command{"sync", "Sync the rules of the filtered stream with a YAML file.", streamSync},
+		command{"tail", "Print the tweets of the filtered stream with the tags of the matched rules.", streamTail},
+	)
+}
+
+// streamRuleFile is the YAML file of the rules, such as
+//
+//	rules:
+//	  - value: "cat has:images"
+//	    tag: cats with images
+type streamRuleFile struct {
+	Rules []streamRule `yaml:"rules"`
+}
+
+type streamRule struct {
+	Value string `yaml:"value"`
+	Tag   string `yaml:"tag"`
+}
+
+func (r streamRule) key() string {
+	return r.Value + "\x00" + r.Tag
+}
+
+func loadStreamRules(path string) ([]streamRule, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := streamRuleFile{}
+	if err := yaml.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("rules file '%s' is invalid: %w", path, err)
+	}
+	for i, r := range f.Rules {
+		if strings.TrimSpace(r.Value) == "" {
+			return nil, fmt.Errorf("value of rules[%d] in '%s' is empty.", i, path)
+		}
+	}
+
+	return f.Rules, nil
+}
+
+// ruleSync is the result of syncing the rules.
+type ruleSync struct {
+	Added     []streamRule
+	Deleted   []resources.FilterdStreamRule
+	Unchanged int
+}
+
+func (s *ruleSync) String() string {
+	return fmt.Sprintf("%d added, %d deleted, %d unchanged", len(s.Added), len(s.Deleted), s.Unchanged)
+}
+
+// syncRules makes the live rules the same as the rules. The rules are matched by the value and the tag,
+// so that a rule whose tag is changed is deleted and added again.
+func syncRules(ctx context.Context, c *gotwi.GotwiClient, rules []streamRule, dryRun bool) (*ruleSync, error) {
+	live, err := tweets.FilteredStreamRulesGet(ctx, c, &types.FilteredStreamRulesGetParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	want := map[string]struct{}{}
+	for _, r := range rules {
+		want[r.key()] = struct{}{}
+	}
+
+	s := &ruleSync{}
+	have := map[string]struct{}{}
+	ids := []string{}
+	for _, r := range live.Data {
+		k := streamRule{Value: gotwi.StringValue(r.Value), Tag: gotwi.StringValue(r.Tag)}.key()
+		if _, ok := want[k]; ok {
+			if _, dup := have[k]; !dup {
+				have[k] = struct{}{}
+				s.Unchanged++
+				continue
+			}
+		}
+		s.Deleted = append(s.Deleted, r)
+		ids = append(ids, gotwi.StringValue(r.ID))
+	}
+
+	add := []types.FilteredStreamRulesPostParamsAdd{}
+	for _, r := range rules {
+		if _, ok := have[r.key()]; ok {
+			continue
+		}
+		have[r.key()] = struct{}{}
+		s.Added = append(s.Added, r)
+
+		a := types.FilteredStreamRulesPostParamsAdd{Value: gotwi.String(r.Value)}
+		if r.Tag != "" {
+			a.Tag = gotwi.String(r.Tag)
+		}
+		add = append(add, a)
+	}
+
+	// delete first not to exceed the limit of the number of rules
+	if len(ids) > 0 {
+		res, err := tweets.FilteredStreamRulesPost(ctx, c, &types.FilteredStreamRulesPostParams{
+			DryRun: dryRun,
+			Delete: &types.FilteredStreamRulesPostParamsDelete{IDs: ids},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if res.HasPartialError() {
+			return nil, fmt.Errorf("failed to delete the rules: %s", partialErrorsString(res.Errors))
+		}
+	}
+
+	if len(add) > 0 {
+		res, err := tweets.FilteredStreamRulesPost(ctx, c, &types.FilteredStreamRulesPostParams{
+			DryRun: dryRun,
+			Add:    add,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if res.HasPartialError() {
+			return nil, fmt.Errorf("failed to add the rules: %s", partialErrorsString(res.Errors))
+		}
+	}
+
+	return s, nil
+}
+
+func partialErrorsString(errs []resources.PartialError) string {
+	l := []string{}
+	for _, e := range errs {
+		s := gotwi.StringValue(e.Title)
+		if e.Value != nil {
+			s += fmt.Sprintf(" (%s)", gotwi.StringValue(e.Value))
+		}
+		if e.Detail != nil {
+			s += ": " + gotwi.StringValue(e.Detail)
+		}
+		l = append(l, s)
+	}
+	return strings.Join(l, ", ")
+}
+
+type streamRulesResponse struct {
+	Data []resources.FilterdStreamRule `json:"data"`
+}
+
+func streamRules(e *env, args []string) error {
+	fs := newFlagSet(e, "stream", "rules")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	res, err := tweets.FilteredStreamRulesGet(context.Background(), c, &types.FilteredStreamRulesGetParams{})
+	if err != nil {
+		return err
+	}
+
+	return fs.print(&streamRulesResponse{Data: res.Data}, nil)
+}
+
+func streamSync(e *env, args []string) error {
+	fs := newFlagSet(e, "stream", "sync")
+	rulesPath := fs.String("rules", "", "YAML file of the rules (required)")
+	dryRun := fs.Bool("dry-run", false, "validate the changes without applying them")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("rules"); err != nil {
+		return err
+	}
+
+	rules, err := loadStreamRules(*rulesPath)
+	if err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	s, err := syncRules(context.Background(), c, rules, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range s.Added {
+		fmt.Fprintf(e.stderr, "+ %s [%s]\n", r.Value, r.Tag)
+	}
+	for _, r := range s.Deleted {
+		fmt.Fprintf(e.stderr, "- %s [%s]\n", gotwi.StringValue(r.Value), gotwi.StringValue(r.Tag))
+	}
+	fmt.Fprintf(e.stderr, "rules: %s\n", s)
+	return nil
+}
+
+func streamTail(e *env, args []string) error {
+	fs := newFlagSet(e, "stream", "tail")
+	rulesPath := fs.String("rules", "", "YAML file of the rules to sync before tailing")
+	out := fs.String("out", "", "file to append the raw JSONL of the stream")
+	rotateSize := fs.Int64("rotate-size", 0, "rotate the -out file when it exceeds the bytes")
+	rotateInterval := fs.Duration("rotate-interval", 0, "rotate the -out file at the interval, such as 1h")
+	max := fs.Int("max", 0, "stop after the number of tweets")
+	backfill := fs.Int("backfill-minutes", 0, "recover the tweets of the minutes missed by a disconnection (Academic Research access)")
+	fs.fieldFlags("tweet", "user", "media", "place", "poll")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *rulesPath != "" {
+		rules, err := loadStreamRules(*rulesPath)
+		if err != nil {
+			return err
+		}
+		s, err := syncRules(ctx, c, rules, false)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stderr, "rules: %s\n", s)
+	}
+
+	var w *rotateWriter
+	if *out != "" {
+		w = newRotateWriter(*out, *rotateSize, *rotateInterval)
+		defer w.Close()
+	}
+
+	p := &types.FilteredStreamSearchParams{
+		BackfillMinutes: *backfill,
+		Expansions:      fs.expansions(),
+		MediaFields:     fs.mediaFields(),
+		PlaceFields:     fs.placeFields(),
+		PollFields:      fs.pollFields(),
+		TweetFields:     fs.tweetFields(),
+		UserFields:      fs.userFields(),
+	}
+
+	count := 0
+	backoff := time.Second
+	for {
+		err := fs.tail(ctx, c, p, w, func() bool {
+			count++
+			backoff = time.Second
+			return *max > 0 && count >= *max
+		})
+		if errors.Is(err, errStreamDone) || ctx.Err() != nil {
+			return nil
+		}
+
+		// reconnect unless the request itself is rejected
+		var non2xx *resources.Non2XXError
+		if errors.As(err, &non2xx) && gotwi.IntValue(non2xx.StatusCode) != 429 && gotwi.IntValue(non2xx.StatusCode) < 500 {
+			return err
+		}
+		fmt.Fprintf(e.stderr, "stream is disconnected: %v. Reconnecting in %s.\n", err, backoff)
+		c.Debug("gotwi: stream reconnect", "backoff", backoff, "error", err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil
+		}
+		if backoff *= 2; backoff > time.Minute {
+			backoff = time.Minute
+		}
+	}
+}
+
+// errStreamDone is returned by tail when the received callback reports done.
+var errStreamDone = errors.New("done")
+
+// tail connects to the stream and prints the tweets until the stream is disconnected.
+func (fs *flagSet) tail(ctx context.Context, c *gotwi.GotwiClient, p *types.FilteredStreamSearchParams, w *rotateWriter, received func() (done bool)) error {
+	s, err := tweets.FilteredStreamSearch(ctx, c, p)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	for s.Next() {
+		if w != nil {
+			if err := w.writeLine(s.Raw()); err != nil {
+				return err
+			}
+		}
+
+		res, err := s.Response()
+		if err != nil {
+			return err
+		}
+		if res.Data.ID == nil {
+			// such as an operational disconnect message
+			if res.HasPartialError() {
+				fmt.Fprintf(fs.e.stderr, "stream error: %s\n", partialErrorsString(res.Errors))
+			}
+			continue
+		}
+
+		if fs.output == "json" {
+			fmt.Fprintf(fs.e.stdout, "%s\n", s.Raw())
+		} else {
+			fmt.Fprintln(fs.e.stdout, streamLine(res))
+		}
+
+		if received() {
+			return errStreamDone
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("stream is closed by the server")
+}
+
+// streamLine formats the tweet as such as "[cats,dogs] 1234 @gopher: text".
+func streamLine(res *types.FilteredStreamSearchResponse) string {
+	tags := []string{}
+	for _, r := range res.MatchingRules {
+		tags = append(tags, gotwi.StringValue(r.Tag))
+	}
+
+	author := ""
+	if u := resources.NewIncludesIndex(&res.Includes).User(res.Data.AuthorID); u != nil {
+		author = " @" + gotwi.StringValue(u.Username)
+	}
+
+	text := strings.Join(strings.Fields(gotwi.StringValue(res.Data.Text)), " ")
+	return fmt.Sprintf("[%s] %s%s: %s", strings.Join(tags, ","), gotwi.StringValue(res.Data.ID), author, text)
+}
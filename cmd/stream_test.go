@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeRules(t *testing.T, rules string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := ioutil.WriteFile(path, []byte(rules), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func Test_streamSync(t *testing.T) {
+	posted := []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"data":[
+				{"id":"1","value":"cat","tag":"cats"},
+				{"id":"2","value":"dog","tag":"old"},
+				{"id":"3","value":"cat","tag":"cats"}
+			],"meta":{"sent":"2021-10-18T00:00:00.000Z"}}`)
+		case "POST":
+			b, _ := ioutil.ReadAll(r.Body)
+			posted = append(posted, r.URL.RawQuery+" "+string(b))
+			fmt.Fprint(w, `{"meta":{"sent":"2021-10-18T00:00:00.000Z","summary":{}}}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	path := writeRules(t, `
+rules:
+  - value: cat
+    tag: cats
+  - value: dog
+    tag: dogs
+  - value: bird has:images
+`)
+
+	e, _, stderr := newTestEnv(t, srv)
+	code := e.run([]string{"stream", "sync", "-rules", path, "-dry-run"})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, []string{
+		`dry_run=true {"delete":{"ids":["2","3"]}}`,
+		`dry_run=true {"add":[{"value":"dog","tag":"dogs"},{"value":"bird has:images"}]}`,
+	}, posted)
+	assert.Contains(t, stderr.String(), "rules: 2 added, 2 deleted, 1 unchanged")
+}
+
+func Test_streamSync_InvalidRule(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"meta":{"sent":"2021-10-18T00:00:00.000Z"}}`)
+		case "POST":
+			fmt.Fprint(w, `{"meta":{"summary":{"invalid":1}},"errors":[{"value":"(","title":"UnprocessableEntity","detail":"syntax error"}]}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	e, _, stderr := newTestEnv(t, srv)
+	code := e.run([]string{"stream", "sync", "-rules", writeRules(t, "rules:\n  - value: (\n")})
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), "failed to add the rules: UnprocessableEntity ((): syntax error")
+
+	code = e.run([]string{"stream", "sync", "-rules", writeRules(t, "rules:\n  - tag: empty\n")})
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr.String(), "value of rules[0]")
+}
+
+func Test_streamTail(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/2/tweets/search/stream/rules":
+			fmt.Fprint(w, `{"data":[{"id":"1","value":"cat","tag":"cats"}]}`)
+		case "/2/tweets/search/stream":
+			assert.Equal(t, "expansions=author_id", r.URL.RawQuery)
+			fmt.Fprint(w, "\r\n")
+			fmt.Fprint(w, `{"data":{"id":"10","author_id":"100","text":"a\ncat"},"includes":{"users":[{"id":"100","username":"gopher"}]},"matching_rules":[{"id":"1","tag":"cats"}]}`+"\r\n")
+			fmt.Fprint(w, "\r\n")
+			fmt.Fprint(w, `{"data":{"id":"11","text":"another cat"},"matching_rules":[{"id":"1","tag":"cats"},{"id":"2","tag":"pets"}]}`+"\r\n")
+			fmt.Fprint(w, `{"data":{"id":"12","text":"not read"},"matching_rules":[{"id":"1","tag":"cats"}]}`+"\r\n")
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	out := filepath.Join(t.TempDir(), "out", "tweets.jsonl")
+	e, stdout, stderr := newTestEnv(t, srv)
+	code := e.run([]string{"stream", "tail",
+		"-rules", writeRules(t, "rules:\n  - value: cat\n    tag: cats\n"),
+		"-expansions", "author_id",
+		"-output", "table",
+		"-out", out,
+		"-rotate-size", "100",
+		"-max", "2",
+	})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stderr.String(), "rules: 0 added, 0 deleted, 1 unchanged")
+	assert.Equal(t, "[cats] 10 @gopher: a cat\n[cats,pets] 11: another cat\n", stdout.String())
+
+	// the first line exceeds the size, so the file is rotated before the second line
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(out), "*"))
+	sort.Strings(files)
+	assert.Len(t, files, 2)
+	assert.True(t, strings.HasPrefix(filepath.Base(files[0]), "tweets-"), files[0])
+	rotated, _ := ioutil.ReadFile(files[0])
+	assert.Contains(t, string(rotated), `"id":"10"`)
+	current, _ := ioutil.ReadFile(out)
+	assert.Contains(t, string(current), `"id":"11"`)
+	assert.Equal(t, 1, strings.Count(string(current), "\n"))
+}
+
+func Test_streamTail_reconnect(t *testing.T) {
+	connections := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/2/tweets/search/stream/rules":
+			fmt.Fprint(w, `{"data":[{"id":"1","value":"cat","tag":"cats"}]}`)
+		case "/2/tweets/search/stream":
+			connections++
+			if connections == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprint(w, `{"title":"Service Unavailable"}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":{"id":"10","text":"a cat"},"matching_rules":[{"id":"1","tag":"cats"}]}`+"\r\n")
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	e, stdout, stderr := newTestEnv(t, srv)
+	code := e.run([]string{"stream", "tail",
+		"-rules", writeRules(t, "rules:\n  - value: cat\n    tag: cats\n"),
+		"-output", "table",
+		"-max", "1",
+		"-debug",
+	})
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, 2, connections)
+	assert.Equal(t, "[cats] 10: a cat\n", stdout.String())
+	assert.Contains(t, stderr.String(), "stream is disconnected")
+	assert.Contains(t, stderr.String(), "gotwi: stream reconnect backoff=1s")
+}
+
+func Test_rotateWriter_Interval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tweets.jsonl")
+	now := time.Date(2021, 10, 18, 12, 0, 0, 0, time.UTC)
+	w := newRotateWriter(path, 0, time.Hour)
+	w.now = func() time.Time { return now }
+	defer w.Close()
+
+	assert.NoError(t, w.writeLine([]byte(`{"n":1}`)))
+	now = now.Add(30 * time.Minute)
+	assert.NoError(t, w.writeLine([]byte(`{"n":2}`)))
+	now = now.Add(30 * time.Minute)
+	assert.NoError(t, w.writeLine([]byte(`{"n":3}`)))
+
+	b, err := ioutil.ReadFile(filepath.Join(filepath.Dir(path), "tweets-20211018T120000.jsonl"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"n\":1}\n{\"n\":2}\n", string(b))
+
+	b, err = ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"n\":3}\n", string(b))
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/export"
+	"github.com/michimani/gotwi/tweets"
+	"github.com/michimani/gotwi/tweets/types"
+)
+
+func init() {
+	register("tweets",
+		command{"lookup", "Look up tweets by IDs.", tweetsLookup},
+		command{"post", "Post a tweet.", tweetsPost},
+		command{"delete", "Delete a tweet.", tweetsDelete},
+		command{"search", "Search tweets of the last 7 days, or the full archive with -all.", tweetsSearch},
+		command{"counts", "Count tweets of the last 7 days, or the full archive with -all.", tweetsCounts},
+	)
+}
+
+func tweetsLookup(e *env, args []string) error {
+	fs := newFlagSet(e, "tweets", "lookup")
+	ids := fs.String("ids", "", "comma separated tweet IDs (required)")
+	fs.fieldFlags("tweet", "user", "media", "place", "poll")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("ids"); err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	res, err := tweets.TweetLookup(context.Background(), c, &types.TweetLookupParams{
+		IDs:         splitList(*ids),
+		Expansions:  fs.expansions(),
+		MediaFields: fs.mediaFields(),
+		PlaceFields: fs.placeFields(),
+		PollFields:  fs.pollFields(),
+		TweetFields: fs.tweetFields(),
+		UserFields:  fs.userFields(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return fs.print(res, export.Tweets(res.Data, &res.Includes, fs.tweetFields(), fs.userFields()))
+}
+
+func tweetsPost(e *env, args []string) error {
+	fs := newFlagSet(e, "tweets", "post")
+	text := fs.String("text", "", "tweet text (required)")
+	replyTo := fs.String("reply-to", "", "tweet ID to reply to")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("text"); err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	p := &types.ManageTweetsPostParams{Text: gotwi.String(*text)}
+	if *replyTo != "" {
+		p.Reply = &types.ManageTweetsPostParamsReply{InReplyToTweetID: *replyTo}
+	}
+
+	res, err := tweets.ManageTweetsPost(context.Background(), c, p)
+	if err != nil {
+		return err
+	}
+
+	return fs.print(res, nil)
+}
+
+func tweetsDelete(e *env, args []string) error {
+	fs := newFlagSet(e, "tweets", "delete")
+	id := fs.String("id", "", "tweet ID (required)")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("id"); err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	res, err := tweets.ManageTweetsDelete(context.Background(), c, &types.ManageTweetsDeleteParams{ID: *id})
+	if err != nil {
+		return err
+	}
+
+	return fs.print(res, nil)
+}
+
+func tweetsSearch(e *env, args []string) error {
+	fs := newFlagSet(e, "tweets", "search")
+	query := fs.String("query", "", "search query (required)")
+	all := fs.Bool("all", false, "search the full archive (Academic Research access)")
+	maxResults := fs.Int("max-results", 0, "max results per page")
+	startTime := fs.String("start-time", "", "RFC3339 start time")
+	endTime := fs.String("end-time", "", "RFC3339 end time")
+	nextToken := fs.String("next-token", "", "next token of the previous page")
+	fs.fieldFlags("tweet", "user", "media", "place", "poll")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("query"); err != nil {
+		return err
+	}
+	start, err := parseTime("start-time", *startTime)
+	if err != nil {
+		return err
+	}
+	end, err := parseTime("end-time", *endTime)
+	if err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	if *all {
+		res, err := tweets.SearchTweetsAll(context.Background(), c, &types.SearchTweetsAllParams{
+			Query:       *query,
+			StartTime:   start,
+			EndTime:     end,
+			NextToken:   *nextToken,
+			MaxResults:  types.SearchTweetsMaxResults(*maxResults),
+			Expansions:  fs.expansions(),
+			MediaFields: fs.mediaFields(),
+			PlaceFields: fs.placeFields(),
+			PollFields:  fs.pollFields(),
+			TweetFields: fs.tweetFields(),
+			UserFields:  fs.userFields(),
+		})
+		if err != nil {
+			return err
+		}
+		return fs.print(res, export.Tweets(res.Data, &res.Includes, fs.tweetFields(), fs.userFields()))
+	}
+
+	res, err := tweets.SearchTweetsRecent(context.Background(), c, &types.SearchTweetsRecentParams{
+		Query:       *query,
+		StartTime:   start,
+		EndTime:     end,
+		NextToken:   *nextToken,
+		MaxResults:  types.SearchTweetsMaxResults(*maxResults),
+		Expansions:  fs.expansions(),
+		MediaFields: fs.mediaFields(),
+		PlaceFields: fs.placeFields(),
+		PollFields:  fs.pollFields(),
+		TweetFields: fs.tweetFields(),
+		UserFields:  fs.userFields(),
+	})
+	if err != nil {
+		return err
+	}
+	return fs.print(res, export.Tweets(res.Data, &res.Includes, fs.tweetFields(), fs.userFields()))
+}
+
+func tweetsCounts(e *env, args []string) error {
+	fs := newFlagSet(e, "tweets", "counts")
+	query := fs.String("query", "", "search query (required)")
+	all := fs.Bool("all", false, "count the full archive (Academic Research access)")
+	granularity := fs.String("granularity", "", "minute, hour or day")
+	startTime := fs.String("start-time", "", "RFC3339 start time")
+	endTime := fs.String("end-time", "", "RFC3339 end time")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if err := fs.required("query"); err != nil {
+		return err
+	}
+	g := types.TweetCountsGranularity(*granularity)
+	if g != "" && g != types.TweetCountsGranularityMinute && g != types.TweetCountsGranularityHour && g != types.TweetCountsGranularityDay {
+		return fmt.Errorf("granularity '%s' is invalid.", *granularity)
+	}
+	start, err := parseTime("start-time", *startTime)
+	if err != nil {
+		return err
+	}
+	end, err := parseTime("end-time", *endTime)
+	if err != nil {
+		return err
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	if *all {
+		res, err := tweets.TweetCountsAll(context.Background(), c, &types.TweetCountsAllParams{
+			Query:       *query,
+			StartTime:   start,
+			EndTime:     end,
+			Granularity: g,
+		})
+		if err != nil {
+			return err
+		}
+		return fs.print(res, nil)
+	}
+
+	res, err := tweets.TweetCountsRecent(context.Background(), c, &types.TweetCountsRecentParams{
+		Query:       *query,
+		StartTime:   start,
+		EndTime:     end,
+		Granularity: g,
+	})
+	if err != nil {
+		return err
+	}
+	return fs.print(res, nil)
+}
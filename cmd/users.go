@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/export"
+	"github.com/michimani/gotwi/users"
+	"github.com/michimani/gotwi/users/types"
+)
+
+func init() {
+	register("users",
+		command{"lookup", "Look up users by IDs or usernames.", usersLookup},
+		command{"follow", "Follow a user, or unfollow with -undo.", usersFollow},
+		command{"block", "Block a user, or unblock with -undo.", usersBlock},
+		command{"mute", "Mute a user, or unmute with -undo.", usersMute},
+	)
+}
+
+func usersLookup(e *env, args []string) error {
+	fs := newFlagSet(e, "users", "lookup")
+	ids := fs.String("ids", "", "comma separated user IDs")
+	usernames := fs.String("usernames", "", "comma separated usernames")
+	fs.fieldFlags("user", "tweet")
+	if err := fs.parse(args); err != nil {
+		return err
+	}
+	if (*ids == "") == (*usernames == "") {
+		fmt.Fprintln(e.stderr, "either -ids or -usernames is required")
+		fs.Usage()
+		return errUsage
+	}
+
+	c, err := fs.client()
+	if err != nil {
+		return err
+	}
+
+	if *ids != "" {
+		res, err := users.UserLookup(context.Background(), c, &types.UserLookupParams{
+			IDs:         splitList(*ids),
+			Expansions:  fs.expansions(),
+			TweetFields: fs.tweetFields(),
+			UserFields:  fs.userFields(),
+		})
+		if err != nil {
+			return err
+		}
+		return fs.print(res, export.Users(res.Data, fs.userFields()))
+	}
+
+	res, err := users.UserLookupBy(context.Background(), c, &types.UserLookupByParams{
+		Usernames:   splitList(*usernames),
+		Expansions:  fs.expansions(),
+		TweetFields: fs.tweetFields(),
+		UserFields:  fs.userFields(),
+	})
+	if err != nil {
+		return err
+	}
+	return fs.print(res, export.Users(res.Data, fs.userFields()))
+}
+
+// relationFlags are the flags of the commands that change the relation of the authenticated user to the target user.
+type relationFlags struct {
+	*flagSet
+	userID   *string
+	targetID *string
+	undo     *bool
+}
+
+func newRelationFlags(e *env, name string, args []string) (*relationFlags, error) {
+	fs := newFlagSet(e, "users", name)
+	rf := &relationFlags{
+		flagSet:  fs,
+		userID:   fs.String("user-id", "", "authenticated user ID (default: the user of the profile)"),
+		targetID: fs.String("target-id", "", "target user ID (required)"),
+		undo:     fs.Bool("undo", false, "undo the "+name),
+	}
+	if err := fs.parse(args); err != nil {
+		return nil, err
+	}
+	if *rf.userID == "" {
+		p, err := fs.loadProfile()
+		if err != nil {
+			return nil, err
+		}
+		*rf.userID = p.UserID
+	}
+	if err := fs.required("user-id", "target-id"); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func usersFollow(e *env, args []string) error {
+	rf, err := newRelationFlags(e, "follow", args)
+	if err != nil {
+		return err
+	}
+
+	c, err := rf.client()
+	if err != nil {
+		return err
+	}
+
+	if *rf.undo {
+		res, err := users.FollowsFollowingDelete(context.Background(), c, &types.FollowsFollowingDeleteParams{
+			SourceUserID: *rf.userID,
+			TargetUserID: *rf.targetID,
+		})
+		if err != nil {
+			return err
+		}
+		return rf.print(res, nil)
+	}
+
+	res, err := users.FollowsFollowingPost(context.Background(), c, &types.FollowsFollowingPostParams{
+		ID:           *rf.userID,
+		TargetUserID: gotwi.String(*rf.targetID),
+	})
+	if err != nil {
+		return err
+	}
+	return rf.print(res, nil)
+}
+
+func usersBlock(e *env, args []string) error {
+	rf, err := newRelationFlags(e, "block", args)
+	if err != nil {
+		return err
+	}
+
+	c, err := rf.client()
+	if err != nil {
+		return err
+	}
+
+	if *rf.undo {
+		res, err := users.BlocksBlockingDelete(context.Background(), c, &types.BlocksBlockingDeleteParams{
+			SourceUserID: *rf.userID,
+			TargetUserID: *rf.targetID,
+		})
+		if err != nil {
+			return err
+		}
+		return rf.print(res, nil)
+	}
+
+	res, err := users.BlocksBlockingPost(context.Background(), c, &types.BlocksBlockingPostParams{
+		ID:           *rf.userID,
+		TargetUserID: gotwi.String(*rf.targetID),
+	})
+	if err != nil {
+		return err
+	}
+	return rf.print(res, nil)
+}
+
+func usersMute(e *env, args []string) error {
+	rf, err := newRelationFlags(e, "mute", args)
+	if err != nil {
+		return err
+	}
+
+	c, err := rf.client()
+	if err != nil {
+		return err
+	}
+
+	if *rf.undo {
+		res, err := users.MutesMutingDelete(context.Background(), c, &types.MutesMutingDeleteParams{
+			SourceUserID: *rf.userID,
+			TargetUserID: *rf.targetID,
+		})
+		if err != nil {
+			return err
+		}
+		return rf.print(res, nil)
+	}
+
+	res, err := users.MutesMutingPost(context.Background(), c, &types.MutesMutingPostParams{
+		ID:           *rf.userID,
+		TargetUserID: gotwi.String(*rf.targetID),
+	})
+	if err != nil {
+		return err
+	}
+	return rf.print(res, nil)
+}
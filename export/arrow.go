@@ -0,0 +1,52 @@
+package export
+
+// ArrowSchema describes the columns of a table in the JSON format of the Arrow schema,
+// so that the JSONL output can be loaded into Arrow or Parquet with the types.
+type ArrowSchema struct {
+	Fields []ArrowField `json:"fields"`
+}
+
+type ArrowField struct {
+	Name     string       `json:"name"`
+	Nullable bool         `json:"nullable"`
+	Type     ArrowType    `json:"type"`
+	Children []ArrowField `json:"children"`
+}
+
+type ArrowType struct {
+	Name      string `json:"name"`
+	BitWidth  int    `json:"bitWidth,omitempty"`
+	IsSigned  bool   `json:"isSigned,omitempty"`
+	Precision string `json:"precision,omitempty"`
+	Unit      string `json:"unit,omitempty"`
+	Timezone  string `json:"timezone,omitempty"`
+}
+
+// ArrowSchema returns the Arrow schema of the columns. All fields are nullable.
+func (t *Table) ArrowSchema() *ArrowSchema {
+	s := &ArrowSchema{Fields: []ArrowField{}}
+	for _, c := range t.Columns {
+		s.Fields = append(s.Fields, arrowField(c.Name, c.Type))
+	}
+	return s
+}
+
+func arrowField(name string, t ColumnType) ArrowField {
+	f := ArrowField{Name: name, Nullable: true, Children: []ArrowField{}}
+	switch t {
+	case ColumnTypeInt64:
+		f.Type = ArrowType{Name: "int", BitWidth: 64, IsSigned: true}
+	case ColumnTypeFloat64:
+		f.Type = ArrowType{Name: "floatingpoint", Precision: "DOUBLE"}
+	case ColumnTypeBool:
+		f.Type = ArrowType{Name: "bool"}
+	case ColumnTypeTimestamp:
+		f.Type = ArrowType{Name: "timestamp", Unit: "SECOND", Timezone: "UTC"}
+	case ColumnTypeStringList:
+		f.Type = ArrowType{Name: "list"}
+		f.Children = []ArrowField{arrowField("item", ColumnTypeString)}
+	default:
+		f.Type = ArrowType{Name: "utf8"}
+	}
+	return f
+}
@@ -0,0 +1,146 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/michimani/gotwi/export"
+	"github.com/michimani/gotwi/fields"
+	"github.com/michimani/gotwi/resources"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTweetsJSON = `[
+	{
+		"id": "1", "text": "hello #go $TWTR", "author_id": "10", "created_at": "2021-01-02T03:04:05.000Z",
+		"public_metrics": {"retweet_count": 1, "reply_count": 2, "like_count": 3, "quote_count": 4},
+		"entities": {"hashtags": [{"start": 6, "end": 9, "tag": "go"}], "cashtags": [{"start": 10, "end": 15, "tag": "TWTR"}]},
+		"referenced_tweets": [{"type": "quoted", "id": "100"}, {"type": "replied_to", "id": "200"}]
+	},
+	{"id": "2", "text": "no author", "author_id": "99"}
+]`
+
+const testIncludesJSON = `{"users": [{"id": "10", "name": "Gopher", "username": "gopher", "verified": true}]}`
+
+func Test_Tweets(t *testing.T) {
+	ts := []resources.Tweet{}
+	if err := json.Unmarshal([]byte(testTweetsJSON), &ts); err != nil {
+		t.Fatal(err)
+	}
+	inc := &resources.Includes{}
+	if err := json.Unmarshal([]byte(testIncludesJSON), inc); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		includes *resources.Includes
+		tf       fields.TweetFieldList
+		uf       fields.UserFieldList
+		wantCSV  string
+		wantJSON string
+	}{
+		{
+			name:     "default columns",
+			includes: inc,
+			wantCSV:  "id,text\n1,hello #go $TWTR\n2,no author\n",
+			wantJSON: `{"id":"1","text":"hello #go $TWTR"}` + "\n" + `{"id":"2","text":"no author"}` + "\n",
+		},
+		{
+			name:     "metrics, entities, referenced tweets and author",
+			includes: inc,
+			tf: fields.TweetFieldList{
+				fields.TweetFieldReferencedTweets,
+				fields.TweetFieldAuthorID,
+				fields.TweetFieldPublicMetrics,
+				fields.TweetFieldEntities,
+			},
+			uf: fields.UserFieldList{fields.UserFieldVerified},
+			wantCSV: "id,text,author_id,entities.hashtags,entities.cashtags,entities.mentions,entities.urls," +
+				"public_metrics.retweet_count,public_metrics.reply_count,public_metrics.like_count,public_metrics.quote_count," +
+				"referenced_tweets.retweeted_id,referenced_tweets.quoted_id,referenced_tweets.replied_to_id," +
+				"author.name,author.username,author.verified\n" +
+				"1,hello #go $TWTR,10,go,TWTR,,,1,2,3,4,,100,200,Gopher,gopher,true\n" +
+				"2,no author,99,,,,,,,,,,,,,,\n",
+		},
+		{
+			name: "nil includes",
+			tf:   fields.TweetFieldList{fields.TweetFieldAuthorID},
+			wantCSV: "id,text,author_id,author.name,author.username\n" +
+				"1,hello #go $TWTR,10,,\n" +
+				"2,no author,99,,\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			tb := export.Tweets(ts, c.includes, c.tf, c.uf)
+
+			b := &bytes.Buffer{}
+			err := tb.WriteCSV(b)
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.wantCSV, b.String())
+
+			if c.wantJSON != "" {
+				b := &bytes.Buffer{}
+				err := tb.WriteJSONL(b)
+				assert.NoError(tt, err)
+				assert.Equal(tt, c.wantJSON, b.String())
+			}
+		})
+	}
+}
+
+func Test_Table_WriteJSONL(t *testing.T) {
+	ts := []resources.Tweet{}
+	if err := json.Unmarshal([]byte(testTweetsJSON), &ts); err != nil {
+		t.Fatal(err)
+	}
+
+	tb := export.Tweets(ts[:1], nil, fields.TweetFieldList{fields.TweetFieldCreatedAt, fields.TweetFieldEntities, fields.TweetFieldLang}, nil)
+	b := &bytes.Buffer{}
+	err := tb.WriteJSONL(b)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		`{"id":"1","text":"hello #go $TWTR","created_at":"2021-01-02T03:04:05Z","entities.hashtags":["go"],"entities.cashtags":["TWTR"],"entities.mentions":[],"entities.urls":[],"lang":null}`+"\n",
+		b.String())
+}
+
+func Test_Table_ArrowSchema(t *testing.T) {
+	tb := &export.Table{Columns: []export.Column{
+		{Name: "id", Type: export.ColumnTypeString},
+		{Name: "count", Type: export.ColumnTypeInt64},
+		{Name: "tags", Type: export.ColumnTypeStringList},
+		{Name: "at", Type: export.ColumnTypeTimestamp},
+	}}
+
+	b, err := json.Marshal(tb.ArrowSchema())
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"fields":[
+		{"name":"id","nullable":true,"type":{"name":"utf8"},"children":[]},
+		{"name":"count","nullable":true,"type":{"name":"int","bitWidth":64,"isSigned":true},"children":[]},
+		{"name":"tags","nullable":true,"type":{"name":"list"},"children":[
+			{"name":"item","nullable":true,"type":{"name":"utf8"},"children":[]}
+		]},
+		{"name":"at","nullable":true,"type":{"name":"timestamp","unit":"SECOND","timezone":"UTC"},"children":[]}
+	]}`, string(b))
+}
+
+func Test_Lists_Spaces(t *testing.T) {
+	ls := []resources.List{}
+	if err := json.Unmarshal([]byte(`[{"id":"1","name":"list","member_count":3,"private":false}]`), &ls); err != nil {
+		t.Fatal(err)
+	}
+	b := &bytes.Buffer{}
+	assert.NoError(t, export.Lists(ls, fields.ListFieldList{fields.ListFieldPrivate, fields.ListFieldMemberCount}).WriteCSV(b))
+	assert.Equal(t, "id,name,member_count,private\n1,list,3,false\n", b.String())
+
+	ss := []resources.Space{}
+	if err := json.Unmarshal([]byte(`[{"id":"s","state":"live","host_ids":["1","2"]}]`), &ss); err != nil {
+		t.Fatal(err)
+	}
+	b = &bytes.Buffer{}
+	assert.NoError(t, export.Spaces(ss, fields.SpaceFieldList{fields.SpaceFieldHostIDs}).WriteCSV(b))
+	assert.Equal(t, "id,state,host_ids\ns,live,1 2\n", b.String())
+}
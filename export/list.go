@@ -0,0 +1,48 @@
+package export
+
+import (
+	"github.com/michimani/gotwi/fields"
+	"github.com/michimani/gotwi/resources"
+)
+
+type listColumn struct {
+	Column
+	field string
+	value func(l *resources.List) interface{}
+}
+
+var listColumns = []listColumn{
+	{Column{"id", ColumnTypeString}, "", func(l *resources.List) interface{} { return stringValue(l.ID) }},
+	{Column{"name", ColumnTypeString}, "", func(l *resources.List) interface{} { return stringValue(l.Name) }},
+	{Column{"created_at", ColumnTypeTimestamp}, fields.ListFieldCreatedAt.String(), func(l *resources.List) interface{} { return timeValue(l.CreatedAt) }},
+	{Column{"description", ColumnTypeString}, fields.ListFieldDescription.String(), func(l *resources.List) interface{} { return stringValue(l.Description) }},
+	{Column{"follower_count", ColumnTypeInt64}, fields.ListFieldFollowerCount.String(), func(l *resources.List) interface{} { return intValue(l.FollowerCount) }},
+	{Column{"member_count", ColumnTypeInt64}, fields.ListFieldMemberCount.String(), func(l *resources.List) interface{} { return intValue(l.MemberCount) }},
+	{Column{"owner_id", ColumnTypeString}, fields.ListFieldOwnerID.String(), func(l *resources.List) interface{} { return stringValue(l.OwnerID) }},
+	{Column{"private", ColumnTypeBool}, fields.ListFieldPrivate.String(), func(l *resources.List) interface{} { return boolValue(l.Private) }},
+}
+
+// Lists flattens the lists. The columns are id and name, and the columns of the requested list fields.
+func Lists(ls []resources.List, lf fields.ListFieldList) *Table {
+	fs := newFieldSet(lf.Values())
+	cols := []listColumn{}
+	for _, c := range listColumns {
+		if fs.has(c.field) {
+			cols = append(cols, c)
+		}
+	}
+
+	t := &Table{Columns: []Column{}, Rows: [][]interface{}{}}
+	for _, c := range cols {
+		t.Columns = append(t.Columns, c.Column)
+	}
+	for i := range ls {
+		row := []interface{}{}
+		for _, c := range cols {
+			row = append(row, c.value(&ls[i]))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+
+	return t
+}
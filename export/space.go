@@ -0,0 +1,55 @@
+package export
+
+import (
+	"github.com/michimani/gotwi/fields"
+	"github.com/michimani/gotwi/resources"
+)
+
+type spaceColumn struct {
+	Column
+	field string
+	value func(s *resources.Space) interface{}
+}
+
+var spaceColumns = []spaceColumn{
+	{Column{"id", ColumnTypeString}, "", func(s *resources.Space) interface{} { return stringValue(s.ID) }},
+	{Column{"state", ColumnTypeString}, "", func(s *resources.Space) interface{} { return stringValue(s.State) }},
+	{Column{"created_at", ColumnTypeTimestamp}, fields.SpaceFieldCreatedAt.String(), func(s *resources.Space) interface{} { return timeValue(s.CreatedAt) }},
+	{Column{"creator_id", ColumnTypeString}, fields.SpaceFieldCreatorID.String(), func(s *resources.Space) interface{} { return stringValue(s.CreatorID) }},
+	{Column{"host_ids", ColumnTypeStringList}, fields.SpaceFieldHostIDs.String(), func(s *resources.Space) interface{} { return stringListValue(s.HostIDs) }},
+	{Column{"invited_user_ids", ColumnTypeStringList}, fields.SpaceFieldInvitedUserIDs.String(), func(s *resources.Space) interface{} { return stringListValue(s.InvitedUserIDs) }},
+	{Column{"is_ticketed", ColumnTypeBool}, fields.SpaceFieldIsTicketed.String(), func(s *resources.Space) interface{} { return boolValue(s.IsTicketed) }},
+	{Column{"lang", ColumnTypeString}, fields.SpaceFieldLang.String(), func(s *resources.Space) interface{} { return stringValue(s.Lang) }},
+	{Column{"participant_count", ColumnTypeInt64}, fields.SpaceFieldParticipantCount.String(), func(s *resources.Space) interface{} { return intValue(s.ParticipantCount) }},
+	{Column{"scheduled_start", ColumnTypeTimestamp}, fields.SpaceFieldScheduledStart.String(), func(s *resources.Space) interface{} { return timeValue(s.ScheduledStart) }},
+	{Column{"speaker_ids", ColumnTypeStringList}, fields.SpaceFieldSpeakerIDs.String(), func(s *resources.Space) interface{} { return stringListValue(s.SpeakerIDs) }},
+	{Column{"started_at", ColumnTypeTimestamp}, fields.SpaceFieldStartedAt.String(), func(s *resources.Space) interface{} { return timeValue(s.StartedAt) }},
+	{Column{"title", ColumnTypeString}, fields.SpaceFieldTitle.String(), func(s *resources.Space) interface{} { return stringValue(s.Title) }},
+	{Column{"topic_ids", ColumnTypeStringList}, fields.SpaceFieldTopicIDs.String(), func(s *resources.Space) interface{} { return stringListValue(s.TopicIDs) }},
+	{Column{"updated_at", ColumnTypeTimestamp}, fields.SpaceFieldUpdatedAt.String(), func(s *resources.Space) interface{} { return timeValue(s.UpdatedAt) }},
+}
+
+// Spaces flattens the spaces. The columns are id and state, and the columns of the requested space fields.
+func Spaces(ss []resources.Space, sf fields.SpaceFieldList) *Table {
+	fs := newFieldSet(sf.Values())
+	cols := []spaceColumn{}
+	for _, c := range spaceColumns {
+		if fs.has(c.field) {
+			cols = append(cols, c)
+		}
+	}
+
+	t := &Table{Columns: []Column{}, Rows: [][]interface{}{}}
+	for _, c := range cols {
+		t.Columns = append(t.Columns, c.Column)
+	}
+	for i := range ss {
+		row := []interface{}{}
+		for _, c := range cols {
+			row = append(row, c.value(&ss[i]))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+
+	return t
+}
@@ -0,0 +1,156 @@
+// Package export flattens resources into tables and writes them as JSONL, CSV or aligned text.
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type ColumnType string
+
+const (
+	ColumnTypeString     ColumnType = "string"
+	ColumnTypeInt64      ColumnType = "int64"
+	ColumnTypeFloat64    ColumnType = "float64"
+	ColumnTypeBool       ColumnType = "bool"
+	ColumnTypeTimestamp  ColumnType = "timestamp"
+	ColumnTypeStringList ColumnType = "list<string>"
+)
+
+func (t ColumnType) String() string {
+	return string(t)
+}
+
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Table is flattened resources. A value of a row is nil, string, int64, float64, bool, time.Time or []string,
+// according to the type of the column.
+type Table struct {
+	Columns []Column
+	Rows    [][]interface{}
+}
+
+// ListSeparator joins the values of a list column in CSV.
+const ListSeparator = " "
+
+// WriteJSONL writes a JSON object per row. The keys are in the order of the columns, and nil values are written as null.
+func (t *Table) WriteJSONL(w io.Writer) error {
+	for _, row := range t.Rows {
+		b := bytes.Buffer{}
+		b.WriteByte('{')
+		for i, c := range t.Columns {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			name, err := json.Marshal(c.Name)
+			if err != nil {
+				return err
+			}
+			value, err := json.Marshal(jsonValue(row[i]))
+			if err != nil {
+				return err
+			}
+			b.Write(name)
+			b.WriteByte(':')
+			b.Write(value)
+		}
+		b.WriteString("}\n")
+
+		if _, err := w.Write(b.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteCSV writes the header of the column names and the rows. Lists are joined by ListSeparator, and nil values are empty.
+func (t *Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{}
+	for _, c := range t.Columns {
+		header = append(header, c.Name)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range t.Rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			record[i] = csvValue(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteText writes the columns aligned for terminals. Values are formatted as in CSV, and newlines are replaced by spaces.
+func (t *Table) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	header := []string{}
+	for _, c := range t.Columns {
+		header = append(header, c.Name)
+	}
+	if _, err := fmt.Fprintln(tw, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+
+	for _, row := range t.Rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			record[i] = textReplacer.Replace(csvValue(v))
+		}
+		if _, err := fmt.Fprintln(tw, strings.Join(record, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+var textReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ")
+
+func jsonValue(v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		return t.UTC().Format(time.RFC3339)
+	}
+	return v
+}
+
+func csvValue(v interface{}) string {
+	switch vv := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return vv
+	case int64:
+		return strconv.FormatInt(vv, 10)
+	case float64:
+		return strconv.FormatFloat(vv, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(vv)
+	case time.Time:
+		return vv.UTC().Format(time.RFC3339)
+	case []string:
+		return strings.Join(vv, ListSeparator)
+	default:
+		return ""
+	}
+}
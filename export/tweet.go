@@ -0,0 +1,270 @@
+package export
+
+import (
+	"github.com/michimani/gotwi/fields"
+	"github.com/michimani/gotwi/resources"
+)
+
+type tweetColumn struct {
+	Column
+	field string
+	value func(t *resources.Tweet) interface{}
+}
+
+var tweetColumns = []tweetColumn{
+	{Column{"id", ColumnTypeString}, "", func(t *resources.Tweet) interface{} { return stringValue(t.ID) }},
+	{Column{"text", ColumnTypeString}, "", func(t *resources.Tweet) interface{} { return stringValue(t.Text) }},
+	{Column{"attachments.media_keys", ColumnTypeStringList}, fields.TweetFieldAttachments.String(), func(t *resources.Tweet) interface{} {
+		if t.Attachments == nil {
+			return nil
+		}
+		return stringListValue(t.Attachments.MediaKeys)
+	}},
+	{Column{"attachments.poll_ids", ColumnTypeStringList}, fields.TweetFieldAttachments.String(), func(t *resources.Tweet) interface{} {
+		if t.Attachments == nil {
+			return nil
+		}
+		return stringListValue(t.Attachments.PollIDs)
+	}},
+	{Column{"author_id", ColumnTypeString}, fields.TweetFieldAuthorID.String(), func(t *resources.Tweet) interface{} { return stringValue(t.AuthorID) }},
+	{Column{"context_annotations.entity_names", ColumnTypeStringList}, fields.TweetFieldContextAnnotations.String(), func(t *resources.Tweet) interface{} {
+		if t.ContextAnnotations == nil {
+			return nil
+		}
+		names := []*string{}
+		for _, a := range t.ContextAnnotations {
+			names = append(names, a.Entity.Name)
+		}
+		return stringListValue(names)
+	}},
+	{Column{"conversation_id", ColumnTypeString}, fields.TweetFieldConversationID.String(), func(t *resources.Tweet) interface{} { return stringValue(t.ConversationId) }},
+	{Column{"created_at", ColumnTypeTimestamp}, fields.TweetFieldCreatedAt.String(), func(t *resources.Tweet) interface{} { return timeValue(t.CreatedAt) }},
+	{Column{"entities.hashtags", ColumnTypeStringList}, fields.TweetFieldEntities.String(), func(t *resources.Tweet) interface{} {
+		if t.Entities == nil {
+			return nil
+		}
+		tags := []*string{}
+		for _, h := range t.Entities.HashTags {
+			tags = append(tags, h.Tag)
+		}
+		return stringListValue(tags)
+	}},
+	{Column{"entities.cashtags", ColumnTypeStringList}, fields.TweetFieldEntities.String(), func(t *resources.Tweet) interface{} {
+		if t.Entities == nil {
+			return nil
+		}
+		tags := []*string{}
+		for _, c := range t.Entities.CashTags {
+			tags = append(tags, c.Tag)
+		}
+		return stringListValue(tags)
+	}},
+	{Column{"entities.mentions", ColumnTypeStringList}, fields.TweetFieldEntities.String(), func(t *resources.Tweet) interface{} {
+		if t.Entities == nil {
+			return nil
+		}
+		usernames := []*string{}
+		for _, m := range t.Entities.Mentions {
+			usernames = append(usernames, m.Username)
+		}
+		return stringListValue(usernames)
+	}},
+	{Column{"entities.urls", ColumnTypeStringList}, fields.TweetFieldEntities.String(), func(t *resources.Tweet) interface{} {
+		if t.Entities == nil {
+			return nil
+		}
+		urls := []*string{}
+		for _, u := range t.Entities.URLs {
+			if u.ExpandedURL != nil {
+				urls = append(urls, u.ExpandedURL)
+			} else {
+				urls = append(urls, u.URL)
+			}
+		}
+		return stringListValue(urls)
+	}},
+	{Column{"geo.place_id", ColumnTypeString}, fields.TweetFieldGeo.String(), func(t *resources.Tweet) interface{} {
+		if t.Geo == nil {
+			return nil
+		}
+		return stringValue(t.Geo.PlaceID)
+	}},
+	{Column{"geo.longitude", ColumnTypeFloat64}, fields.TweetFieldGeo.String(), func(t *resources.Tweet) interface{} { return coordinate(t, 0) }},
+	{Column{"geo.latitude", ColumnTypeFloat64}, fields.TweetFieldGeo.String(), func(t *resources.Tweet) interface{} { return coordinate(t, 1) }},
+	{Column{"in_reply_to_user_id", ColumnTypeString}, fields.TweetFieldInReplyToUserID.String(), func(t *resources.Tweet) interface{} { return stringValue(t.InReplyToUserID) }},
+	{Column{"lang", ColumnTypeString}, fields.TweetFieldLang.String(), func(t *resources.Tweet) interface{} { return stringValue(t.Lang) }},
+	{Column{"non_public_metrics.impression_count", ColumnTypeInt64}, fields.TweetFieldNonPublicMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.NonPublicMetrics == nil {
+			return nil
+		}
+		return intValue(t.NonPublicMetrics.ImpressionCount)
+	}},
+	{Column{"non_public_metrics.url_link_clicks", ColumnTypeInt64}, fields.TweetFieldNonPublicMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.NonPublicMetrics == nil {
+			return nil
+		}
+		return intValue(t.NonPublicMetrics.UrlLinkClicks)
+	}},
+	{Column{"non_public_metrics.user_profile_clicks", ColumnTypeInt64}, fields.TweetFieldNonPublicMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.NonPublicMetrics == nil {
+			return nil
+		}
+		return intValue(t.NonPublicMetrics.UserProfileClicks)
+	}},
+	{Column{"organic_metrics.impression_count", ColumnTypeInt64}, fields.TweetFieldOrganicMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.OrganicMetrics == nil {
+			return nil
+		}
+		return intValue(t.OrganicMetrics.ImpressionCount)
+	}},
+	{Column{"organic_metrics.like_count", ColumnTypeInt64}, fields.TweetFieldOrganicMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.OrganicMetrics == nil {
+			return nil
+		}
+		return intValue(t.OrganicMetrics.LikeCount)
+	}},
+	{Column{"organic_metrics.reply_count", ColumnTypeInt64}, fields.TweetFieldOrganicMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.OrganicMetrics == nil {
+			return nil
+		}
+		return intValue(t.OrganicMetrics.ReplyCount)
+	}},
+	{Column{"organic_metrics.retweet_count", ColumnTypeInt64}, fields.TweetFieldOrganicMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.OrganicMetrics == nil {
+			return nil
+		}
+		return intValue(t.OrganicMetrics.RetweetCount)
+	}},
+	{Column{"possibly_sensitive", ColumnTypeBool}, fields.TweetFieldPossiblySensitive.String(), func(t *resources.Tweet) interface{} { return boolValue(t.PossiblySensitive) }},
+	{Column{"promoted_metrics.impression_count", ColumnTypeInt64}, fields.TweetFieldPromotedMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.PromotedMetrics == nil {
+			return nil
+		}
+		return intValue(t.PromotedMetrics.ImpressionCount)
+	}},
+	{Column{"promoted_metrics.like_count", ColumnTypeInt64}, fields.TweetFieldPromotedMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.PromotedMetrics == nil {
+			return nil
+		}
+		return intValue(t.PromotedMetrics.LikeCount)
+	}},
+	{Column{"promoted_metrics.reply_count", ColumnTypeInt64}, fields.TweetFieldPromotedMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.PromotedMetrics == nil {
+			return nil
+		}
+		return intValue(t.PromotedMetrics.ReplyCount)
+	}},
+	{Column{"promoted_metrics.retweet_count", ColumnTypeInt64}, fields.TweetFieldPromotedMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.PromotedMetrics == nil {
+			return nil
+		}
+		return intValue(t.PromotedMetrics.RetweetCount)
+	}},
+	{Column{"public_metrics.retweet_count", ColumnTypeInt64}, fields.TweetFieldPublicMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.PublicMetrics == nil {
+			return nil
+		}
+		return intValue(t.PublicMetrics.RetweetCount)
+	}},
+	{Column{"public_metrics.reply_count", ColumnTypeInt64}, fields.TweetFieldPublicMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.PublicMetrics == nil {
+			return nil
+		}
+		return intValue(t.PublicMetrics.ReplyCount)
+	}},
+	{Column{"public_metrics.like_count", ColumnTypeInt64}, fields.TweetFieldPublicMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.PublicMetrics == nil {
+			return nil
+		}
+		return intValue(t.PublicMetrics.LikeCount)
+	}},
+	{Column{"public_metrics.quote_count", ColumnTypeInt64}, fields.TweetFieldPublicMetrics.String(), func(t *resources.Tweet) interface{} {
+		if t.PublicMetrics == nil {
+			return nil
+		}
+		return intValue(t.PublicMetrics.QuoteCount)
+	}},
+	{Column{"referenced_tweets.retweeted_id", ColumnTypeString}, fields.TweetFieldReferencedTweets.String(), func(t *resources.Tweet) interface{} { return stringValue(t.RetweetedTweetID()) }},
+	{Column{"referenced_tweets.quoted_id", ColumnTypeString}, fields.TweetFieldReferencedTweets.String(), func(t *resources.Tweet) interface{} { return stringValue(t.QuotedTweetID()) }},
+	{Column{"referenced_tweets.replied_to_id", ColumnTypeString}, fields.TweetFieldReferencedTweets.String(), func(t *resources.Tweet) interface{} { return stringValue(t.RepliedToTweetID()) }},
+	{Column{"reply_settings", ColumnTypeString}, fields.TweetFieldReplySettings.String(), func(t *resources.Tweet) interface{} {
+		if t.ReplySettings == nil {
+			return nil
+		}
+		return t.ReplySettings.String()
+	}},
+	{Column{"source", ColumnTypeString}, fields.TweetFieldSource.String(), func(t *resources.Tweet) interface{} { return stringValue(t.Source) }},
+	{Column{"withheld.copyright", ColumnTypeBool}, fields.TweetFieldWithheld.String(), func(t *resources.Tweet) interface{} {
+		if t.Withheld == nil {
+			return nil
+		}
+		return boolValue(t.Withheld.Copyright)
+	}},
+	{Column{"withheld.country_codes", ColumnTypeStringList}, fields.TweetFieldWithheld.String(), func(t *resources.Tweet) interface{} {
+		if t.Withheld == nil {
+			return nil
+		}
+		return stringListValue(t.Withheld.CountryCodes)
+	}},
+}
+
+// authorColumnPrefix is the prefix of the columns of the author in the tweets table.
+const authorColumnPrefix = "author."
+
+// Tweets flattens the tweets. The columns are id and text, and the columns of the requested tweet fields.
+// If the author_id field is requested, the columns of the requested user fields of the author in the includes are added
+// with the "author." prefix. The includes may be nil.
+func Tweets(ts []resources.Tweet, includes *resources.Includes, tf fields.TweetFieldList, uf fields.UserFieldList) *Table {
+	fs := newFieldSet(tf.Values())
+	cols := []tweetColumn{}
+	for _, c := range tweetColumns {
+		if fs.has(c.field) {
+			cols = append(cols, c)
+		}
+	}
+
+	authorCols := []userColumn{}
+	if fs.has(fields.TweetFieldAuthorID.String()) {
+		for _, c := range selectUserColumns(uf) {
+			if c.Name != "id" {
+				authorCols = append(authorCols, c)
+			}
+		}
+	}
+
+	t := &Table{Columns: []Column{}, Rows: [][]interface{}{}}
+	for _, c := range cols {
+		t.Columns = append(t.Columns, c.Column)
+	}
+	for _, c := range authorCols {
+		t.Columns = append(t.Columns, Column{Name: authorColumnPrefix + c.Name, Type: c.Type})
+	}
+
+	index := resources.NewIncludesIndex(includes)
+	for i := range ts {
+		row := []interface{}{}
+		for _, c := range cols {
+			row = append(row, c.value(&ts[i]))
+		}
+
+		author := index.User(ts[i].AuthorID)
+		for _, c := range authorCols {
+			if author == nil {
+				row = append(row, nil)
+				continue
+			}
+			row = append(row, c.value(author))
+		}
+
+		t.Rows = append(t.Rows, row)
+	}
+
+	return t
+}
+
+func coordinate(t *resources.Tweet, i int) interface{} {
+	if t.Geo == nil || len(t.Geo.Coordinates.Coordinates) <= i || t.Geo.Coordinates.Coordinates[i] == nil {
+		return nil
+	}
+	return *t.Geo.Coordinates.Coordinates[i]
+}
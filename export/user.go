@@ -0,0 +1,102 @@
+package export
+
+import (
+	"github.com/michimani/gotwi/fields"
+	"github.com/michimani/gotwi/resources"
+)
+
+type userColumn struct {
+	Column
+	field string
+	value func(u *resources.User) interface{}
+}
+
+var userColumns = []userColumn{
+	{Column{"id", ColumnTypeString}, "", func(u *resources.User) interface{} { return stringValue(u.ID) }},
+	{Column{"name", ColumnTypeString}, "", func(u *resources.User) interface{} { return stringValue(u.Name) }},
+	{Column{"username", ColumnTypeString}, "", func(u *resources.User) interface{} { return stringValue(u.Username) }},
+	{Column{"created_at", ColumnTypeTimestamp}, fields.UserFieldCreatedAt.String(), func(u *resources.User) interface{} { return timeValue(u.CreatedAt) }},
+	{Column{"description", ColumnTypeString}, fields.UserFieldDescription.String(), func(u *resources.User) interface{} { return stringValue(u.Description) }},
+	{Column{"entities.description.hashtags", ColumnTypeStringList}, fields.UserFieldEntities.String(), func(u *resources.User) interface{} {
+		if u.Entities == nil || u.Entities.Description == nil {
+			return nil
+		}
+		tags := []*string{}
+		for _, h := range u.Entities.Description.HashTags {
+			tags = append(tags, h.Tag)
+		}
+		return stringListValue(tags)
+	}},
+	{Column{"entities.url.expanded_url", ColumnTypeString}, fields.UserFieldEntities.String(), func(u *resources.User) interface{} {
+		if u.Entities == nil || u.Entities.URL == nil || len(u.Entities.URL.URLs) == 0 {
+			return nil
+		}
+		return stringValue(u.Entities.URL.URLs[0].ExpandedURL)
+	}},
+	{Column{"location", ColumnTypeString}, fields.UserFieldLocation.String(), func(u *resources.User) interface{} { return stringValue(u.Location) }},
+	{Column{"pinned_tweet_id", ColumnTypeString}, fields.UserFieldPinnedTweetID.String(), func(u *resources.User) interface{} { return stringValue(u.PinnedTweetID) }},
+	{Column{"profile_image_url", ColumnTypeString}, fields.UserFieldProfileImageUrl.String(), func(u *resources.User) interface{} { return stringValue(u.ProfileImageURL) }},
+	{Column{"protected", ColumnTypeBool}, fields.UserFieldProtected.String(), func(u *resources.User) interface{} { return boolValue(u.Protected) }},
+	{Column{"public_metrics.followers_count", ColumnTypeInt64}, fields.UserFieldPublicMetrics.String(), func(u *resources.User) interface{} {
+		if u.PublicMetrics == nil {
+			return nil
+		}
+		return intValue(u.PublicMetrics.FollowersCount)
+	}},
+	{Column{"public_metrics.following_count", ColumnTypeInt64}, fields.UserFieldPublicMetrics.String(), func(u *resources.User) interface{} {
+		if u.PublicMetrics == nil {
+			return nil
+		}
+		return intValue(u.PublicMetrics.FollowingCount)
+	}},
+	{Column{"public_metrics.tweet_count", ColumnTypeInt64}, fields.UserFieldPublicMetrics.String(), func(u *resources.User) interface{} {
+		if u.PublicMetrics == nil {
+			return nil
+		}
+		return intValue(u.PublicMetrics.TweetCount)
+	}},
+	{Column{"public_metrics.listed_count", ColumnTypeInt64}, fields.UserFieldPublicMetrics.String(), func(u *resources.User) interface{} {
+		if u.PublicMetrics == nil {
+			return nil
+		}
+		return intValue(u.PublicMetrics.ListedCount)
+	}},
+	{Column{"url", ColumnTypeString}, fields.UserFieldUrl.String(), func(u *resources.User) interface{} { return stringValue(u.URL) }},
+	{Column{"verified", ColumnTypeBool}, fields.UserFieldVerified.String(), func(u *resources.User) interface{} { return boolValue(u.Verified) }},
+	{Column{"withheld.country_codes", ColumnTypeStringList}, fields.UserFieldWithheld.String(), func(u *resources.User) interface{} {
+		if u.Withheld == nil {
+			return nil
+		}
+		return stringListValue(u.Withheld.CountryCodes)
+	}},
+}
+
+// Users flattens the users. The columns are id, name and username, and the columns of the requested user fields.
+func Users(us []resources.User, uf fields.UserFieldList) *Table {
+	cols := selectUserColumns(uf)
+
+	t := &Table{Columns: []Column{}, Rows: [][]interface{}{}}
+	for _, c := range cols {
+		t.Columns = append(t.Columns, c.Column)
+	}
+	for i := range us {
+		row := []interface{}{}
+		for _, c := range cols {
+			row = append(row, c.value(&us[i]))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+
+	return t
+}
+
+func selectUserColumns(uf fields.UserFieldList) []userColumn {
+	fs := newFieldSet(uf.Values())
+	cols := []userColumn{}
+	for _, c := range userColumns {
+		if fs.has(c.field) {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"time"
+)
+
+// fieldSet is the set of the requested field names. The empty name is for the columns that are always exported.
+type fieldSet map[string]struct{}
+
+func newFieldSet(values []string) fieldSet {
+	s := fieldSet{"": {}}
+	for _, v := range values {
+		s[v] = struct{}{}
+	}
+	return s
+}
+
+func (s fieldSet) has(field string) bool {
+	_, ok := s[field]
+	return ok
+}
+
+func stringValue(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func intValue(i *int) interface{} {
+	if i == nil {
+		return nil
+	}
+	return int64(*i)
+}
+
+func boolValue(b *bool) interface{} {
+	if b == nil {
+		return nil
+	}
+	return *b
+}
+
+func timeValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+func stringListValue(ss []*string) interface{} {
+	if ss == nil {
+		return nil
+	}
+	l := []string{}
+	for _, s := range ss {
+		if s != nil {
+			l = append(l, *s)
+		}
+	}
+	return l
+}
@@ -16,6 +16,7 @@ const (
 	ExpansionSpeakerIDs                 Expansion = "speaker_ids"
 	ExpansionCreatorID                  Expansion = "creator_id"
 	ExpansionHostIDs                    Expansion = "host_ids"
+	ExpansionTopicIDs                   Expansion = "topic_ids"
 )
 
 func (e Expansion) String() string {
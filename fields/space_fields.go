@@ -17,6 +17,7 @@ const (
 	SpaceFieldUpdatedAt        SpaceField = "updated_at"
 	SpaceFieldScheduledStart   SpaceField = "scheduled_start"
 	SpaceFieldIsTicketed       SpaceField = "is_ticketed"
+	SpaceFieldTopicIDs         SpaceField = "topic_ids"
 )
 
 func (f SpaceField) String() string {
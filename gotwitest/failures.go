@@ -0,0 +1,101 @@
+package gotwitest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Failure makes the matching requests fail.
+type Failure struct {
+	// Method and Endpoint match the requests, such as "POST" and "/2/users/:id/following".
+	// An empty value matches any.
+	Method   string
+	Endpoint string
+	// StatusCode is the status of the response.
+	StatusCode int
+	// Body is encoded as the JSON body. If nil, the error body of the API for the status is used.
+	Body interface{}
+	// Times is the number of the requests to fail. If 0, the requests fail until ClearFailures.
+	Times int
+}
+
+// InjectFailure makes the matching requests fail. The failures are matched in the order of the injection.
+// They are checked after the authentication, and before the rate limits.
+func (s *Server) InjectFailure(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &f)
+}
+
+// ClearFailures removes the injected failures.
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = nil
+}
+
+// writeFailure writes the response of the first failure matching the request, and reports whether it is written.
+func (s *Server) writeFailure(w http.ResponseWriter, method, endpoint string) bool {
+	for i, f := range s.failures {
+		if (f.Method != "" && f.Method != method) || (f.Endpoint != "" && f.Endpoint != endpoint) {
+			continue
+		}
+
+		if f.Times > 0 {
+			if f.Times--; f.Times == 0 {
+				s.failures = append(s.failures[:i:i], s.failures[i+1:]...)
+			}
+		}
+
+		if f.Body != nil {
+			writeJSON(w, f.StatusCode, f.Body)
+		} else {
+			writeProblem(w, f.StatusCode, http.StatusText(f.StatusCode), http.StatusText(f.StatusCode), "about:blank")
+		}
+		return true
+	}
+	return false
+}
+
+type rateLimit struct {
+	limit     int
+	window    time.Duration
+	remaining int
+	resetAt   time.Time
+}
+
+// SetRateLimit limits the requests of the endpoint, such as "GET" and "/2/users/:id/following",
+// to the limit per the window, such as 15 minutes. The responses of the endpoint have the x-rate-limit headers,
+// and the requests over the limit get 429 Too Many Requests until the window is reset.
+func (s *Server) SetRateLimit(method, endpoint string, limit int, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimits[method+" "+endpoint] = &rateLimit{limit: limit, window: window, remaining: limit, resetAt: s.now().Add(window)}
+}
+
+// takeRateLimit consumes the rate limit of the endpoint, and writes 429 and returns false if it is exceeded.
+func (s *Server) takeRateLimit(w http.ResponseWriter, method, endpoint string) bool {
+	rl, ok := s.rateLimits[method+" "+endpoint]
+	if !ok {
+		return true
+	}
+
+	if now := s.now(); !now.Before(rl.resetAt) {
+		rl.remaining = rl.limit
+		rl.resetAt = now.Add(rl.window)
+	}
+
+	h := w.Header()
+	h.Set("x-rate-limit-limit", strconv.Itoa(rl.limit))
+	h.Set("x-rate-limit-reset", strconv.FormatInt(rl.resetAt.Unix(), 10))
+	if rl.remaining == 0 {
+		h.Set("x-rate-limit-remaining", "0")
+		writeProblem(w, http.StatusTooManyRequests, "Too Many Requests", "Too Many Requests", "about:blank")
+		return false
+	}
+
+	rl.remaining--
+	h.Set("x-rate-limit-remaining", strconv.Itoa(rl.remaining))
+	return true
+}
@@ -0,0 +1,260 @@
+package gotwitest
+
+import (
+	"net/http"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/resources"
+)
+
+// listData returns the list with the fields of the query, and adds its expansions to the response.
+func (s *Server) listData(c *call, l *resources.List, res *response) map[string]interface{} {
+	if c.hasExpansion("owner_id") {
+		s.includeUser(c, l.OwnerID, res)
+	}
+	return project(s.listObject(l), listDefaults, c.query.Get("list.fields"))
+}
+
+// visibleList returns the list if it exists, and it is public or owned by the authenticated user.
+func (s *Server) visibleList(c *call, id string) *resources.List {
+	l, ok := s.lists[id]
+	if !ok || (gotwi.BoolValue(l.Private) && gotwi.StringValue(l.OwnerID) != c.auth.userID) {
+		return nil
+	}
+	return l
+}
+
+// ownList returns the list owned by the authenticated user, or writes the error and returns nil.
+func (s *Server) ownList(w http.ResponseWriter, c *call, id string) *resources.List {
+	l, ok := s.lists[id]
+	if !ok {
+		writeInvalidRequest(w, "id", id)
+		return nil
+	}
+	if gotwi.StringValue(l.OwnerID) != c.auth.userID {
+		writeForbidden(w, "You are not allowed to manage this List.")
+		return nil
+	}
+	return l
+}
+
+func (s *Server) writeListPage(w http.ResponseWriter, c *call, ids []string) {
+	page, meta, ok := paginate(w, c, ids, 100, 1, 100)
+	if !ok {
+		return
+	}
+
+	res := &response{Meta: meta}
+	data := []interface{}{}
+	for _, id := range page {
+		if l := s.visibleList(c, id); l != nil {
+			data = append(data, s.listData(c, l, res))
+		}
+	}
+	if len(data) > 0 {
+		res.Data = data
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+type listBody struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Private     *bool   `json:"private"`
+}
+
+func (s *Server) listsPost(w http.ResponseWriter, c *call) {
+	body := listBody{}
+	if !decodeBody(w, c, &body) {
+		return
+	}
+	if gotwi.StringValue(body.Name) == "" {
+		writeInvalidRequest(w, "name", "")
+		return
+	}
+
+	l := &resources.List{
+		ID:          gotwi.String(s.newID()),
+		Name:        body.Name,
+		Description: body.Description,
+		Private:     gotwi.Bool(gotwi.BoolValue(body.Private)),
+		OwnerID:     gotwi.String(c.auth.userID),
+		CreatedAt:   s.nowPtr(),
+	}
+	s.lists[*l.ID] = l
+	writeJSON(w, http.StatusOK, &response{Data: map[string]interface{}{"id": l.ID, "name": l.Name}})
+}
+
+func (s *Server) listsLookupID(w http.ResponseWriter, c *call) {
+	res := &response{}
+	if l := s.visibleList(c, c.param("id")); l != nil {
+		res.Data = s.listData(c, l, res)
+	} else {
+		res.Errors = append(res.Errors, notFound("list", "id", c.param("id")))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) listsPut(w http.ResponseWriter, c *call) {
+	body := listBody{}
+	if !decodeBody(w, c, &body) {
+		return
+	}
+	l := s.ownList(w, c, c.param("id"))
+	if l == nil {
+		return
+	}
+
+	if body.Name != nil {
+		l.Name = body.Name
+	}
+	if body.Description != nil {
+		l.Description = body.Description
+	}
+	if body.Private != nil {
+		l.Private = body.Private
+	}
+	writeJSON(w, http.StatusOK, &response{Data: map[string]interface{}{"updated": true}})
+}
+
+func (s *Server) listsDelete(w http.ResponseWriter, c *call) {
+	id := c.param("id")
+	if s.ownList(w, c, id) == nil {
+		return
+	}
+
+	delete(s.lists, id)
+	for _, r := range []Relation{RelationListMember, RelationListFollowing, RelationListPinning} {
+		s.removeRelationsOf(r, id)
+	}
+	writeJSON(w, http.StatusOK, &response{Data: map[string]interface{}{"deleted": true}})
+}
+
+func (s *Server) ownedLists(w http.ResponseWriter, c *call) {
+	id := c.param("id")
+	if _, ok := s.users[id]; !ok {
+		s.writeUser(w, c, nil, "id", id)
+		return
+	}
+
+	ids := []string{}
+	for lid, l := range s.lists {
+		if gotwi.StringValue(l.OwnerID) == id {
+			ids = append(ids, lid)
+		}
+	}
+	sortNewest(ids)
+	s.writeListPage(w, c, ids)
+}
+
+// listTweets serves the tweets of the members of the list.
+func (s *Server) listTweets(w http.ResponseWriter, c *call) {
+	id := c.param("id")
+	if s.visibleList(c, id) == nil {
+		writeJSON(w, http.StatusOK, &response{Errors: []map[string]interface{}{notFound("list", "id", id)}})
+		return
+	}
+
+	members := map[string]bool{}
+	for _, u := range s.relatedTo(RelationListMember, id) {
+		members[u] = true
+	}
+	ids := s.filterTweets(func(t *tweet) bool { return members[gotwi.StringValue(t.AuthorID)] })
+	s.writeTweetPage(w, c, ids, 100, 1, 100)
+}
+
+// listUsers serves the users related to the list, such as GET /2/lists/:id/members.
+func (s *Server) listUsers(r Relation) func(w http.ResponseWriter, c *call) {
+	return func(w http.ResponseWriter, c *call) {
+		id := c.param("id")
+		if s.visibleList(c, id) == nil {
+			writeJSON(w, http.StatusOK, &response{Errors: []map[string]interface{}{notFound("list", "id", id)}})
+			return
+		}
+		s.writeUserPage(w, c, s.relatedTo(r, id), 100, 100)
+	}
+}
+
+// userLists serves the lists related from the user, such as GET /2/users/:id/followed_lists.
+func (s *Server) userLists(r Relation) func(w http.ResponseWriter, c *call) {
+	return func(w http.ResponseWriter, c *call) {
+		id := c.param("id")
+		if r == RelationListPinning && !requireUser(w, c, id) {
+			return
+		}
+		if _, ok := s.users[id]; !ok {
+			s.writeUser(w, c, nil, "id", id)
+			return
+		}
+		s.writeListPage(w, c, s.related(r, id))
+	}
+}
+
+func (s *Server) listMembersPost(w http.ResponseWriter, c *call) {
+	body := struct {
+		UserID string `json:"user_id"`
+	}{}
+	if !decodeBody(w, c, &body) {
+		return
+	}
+	l := s.ownList(w, c, c.param("id"))
+	if l == nil {
+		return
+	}
+	if _, ok := s.users[body.UserID]; !ok {
+		writeInvalidRequest(w, "user_id", body.UserID)
+		return
+	}
+
+	s.relate(RelationListMember, body.UserID, *l.ID)
+	writeJSON(w, http.StatusOK, &response{Data: map[string]interface{}{"is_member": true}})
+}
+
+func (s *Server) listMembersDelete(w http.ResponseWriter, c *call) {
+	l := s.ownList(w, c, c.param("id"))
+	if l == nil {
+		return
+	}
+
+	s.unrelate(RelationListMember, c.param("user_id"), *l.ID)
+	writeJSON(w, http.StatusOK, &response{Data: map[string]interface{}{"is_member": false}})
+}
+
+// listRelationResult is the member of the data of the responses, such as "pinned" of POST /2/users/:id/pinned_lists.
+var listRelationResult = map[Relation]string{RelationListFollowing: "following", RelationListPinning: "pinned"}
+
+// listRelationPost serves such as POST /2/users/:id/followed_lists.
+func (s *Server) listRelationPost(r Relation) func(w http.ResponseWriter, c *call) {
+	return func(w http.ResponseWriter, c *call) {
+		id := c.param("id")
+		if !requireUser(w, c, id) {
+			return
+		}
+		body := struct {
+			ListID string `json:"list_id"`
+		}{}
+		if !decodeBody(w, c, &body) {
+			return
+		}
+		if s.visibleList(c, body.ListID) == nil {
+			writeInvalidRequest(w, "list_id", body.ListID)
+			return
+		}
+
+		s.relate(r, id, body.ListID)
+		writeJSON(w, http.StatusOK, &response{Data: map[string]interface{}{listRelationResult[r]: true}})
+	}
+}
+
+// listRelationDelete serves such as DELETE /2/users/:id/followed_lists/:list_id.
+func (s *Server) listRelationDelete(r Relation) func(w http.ResponseWriter, c *call) {
+	return func(w http.ResponseWriter, c *call) {
+		id := c.param("id")
+		if !requireUser(w, c, id) {
+			return
+		}
+
+		s.unrelate(r, id, c.param("list_id"))
+		writeJSON(w, http.StatusOK, &response{Data: map[string]interface{}{listRelationResult[r]: false}})
+	}
+}
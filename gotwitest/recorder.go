@@ -0,0 +1,419 @@
+package gotwitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay replays the interactions of the cassette, and sends no request.
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests, and records the interactions to the cassette.
+	ModeRecord
+)
+
+// RecordEnv is the environment variable to record the cassettes. If it is "1" or "true", ModeFromEnv returns ModeRecord.
+const RecordEnv = "GOTWITEST_RECORD"
+
+// ModeFromEnv returns ModeRecord if RecordEnv is set, otherwise ModeReplay.
+func ModeFromEnv() Mode {
+	if v := os.Getenv(RecordEnv); v == "1" || v == "true" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Redacted replaces the secrets in the cassettes.
+const Redacted = "REDACTED"
+
+// Cassette is the recorded interactions, which is saved as a JSON file.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a pair of a request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Query is the canonicalized query, which is matched to the requests.
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records the requests and the responses to a cassette file,
+// and replays them later. It is set as NewGotwiClientInput.HTTPClient by Client.
+//
+// The requests are matched on the method, the path and the canonicalized query, in the order of the recording,
+// and each interaction is replayed once. A request that does not match returns *UnmatchedRequestError.
+// The Authorization header, the oauth_* values and the tokens are replaced with Redacted in the cassette.
+// The responses are read to the end, so the filtered stream cannot be recorded.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	mu        sync.Mutex
+	cassette  *Cassette
+	used      []bool
+	unmatched []string
+}
+
+// NewRecorder returns a Recorder of the cassette file. In ModeReplay, the file is loaded and must exist.
+// In ModeRecord, the requests are sent by the transport, or http.DefaultTransport if nil,
+// and the file is written by Save.
+func NewRecorder(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	r := &Recorder{path: path, mode: mode, transport: transport, cassette: &Cassette{}}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the cassette. Record it with %s=1: %w", RecordEnv, err)
+	}
+	if err := json.Unmarshal(b, r.cassette); err != nil {
+		return nil, fmt.Errorf("failed to decode the cassette %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// NewTestRecorder returns a Recorder of testdata/cassettes/<name>.json in the mode of ModeFromEnv.
+// The test fails if the cassette cannot be loaded, or there are unmatched requests. The cassette is saved
+// when the test finishes, in ModeRecord.
+func NewTestRecorder(t testing.TB, name string) *Recorder {
+	t.Helper()
+
+	r, err := NewRecorder(filepath.Join("testdata", "cassettes", name+".json"), ModeFromEnv(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		for _, u := range r.Unmatched() {
+			t.Errorf("unmatched request: %s", u)
+		}
+		if err := r.Save(); err != nil {
+			t.Error(err)
+		}
+	})
+	return r
+}
+
+// Client returns an http.Client with the Recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Mode returns the mode of the Recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Save writes the cassette in ModeRecord. It is noop in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := marshalJSON(r.cassette, "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, b, 0o644)
+}
+
+// Unmatched returns the requests that did not match the cassette, such as "GET /2/users/me?user.fields=created_at".
+func (r *Recorder) Unmatched() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.unmatched...)
+}
+
+// Unused returns the interactions of the cassette that are not replayed.
+func (r *Recorder) Unused() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	l := []*Interaction{}
+	for i, used := range r.used {
+		if !used {
+			l = append(l, r.cassette.Interactions[i])
+		}
+	}
+	return l
+}
+
+// UnmatchedRequestError is the error of a request that does not match the cassette in ModeReplay.
+type UnmatchedRequestError struct {
+	Request  string
+	Cassette string
+	// Recorded is the unused interactions of the cassette, such as "GET /2/users/me".
+	Recorded []string
+}
+
+func (e *UnmatchedRequestError) Error() string {
+	return fmt.Sprintf("gotwitest: no recorded interaction in %s matches %s. The unused interactions are [%s]. Record the cassette again with %s=1.",
+		e.Cassette, e.Request, strings.Join(e.Recorded, ", "), RecordEnv)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	rr := recordRequest(req, body)
+
+	if r.mode == ModeRecord {
+		return r.record(req, rr)
+	}
+	return r.replay(req, rr)
+}
+
+func (r *Recorder) record(req *http.Request, rr RecordedRequest) (*http.Response, error) {
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request: rr,
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     scrubHeader(res.Header),
+			Body:       scrubBody(res.Header.Get("Content-Type"), b),
+		},
+	})
+	r.mu.Unlock()
+
+	res.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, rr RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || !in.Request.matches(rr) {
+			continue
+		}
+		r.used[i] = true
+
+		header := in.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	e := &UnmatchedRequestError{Request: rr.String(), Cassette: r.path}
+	for i, in := range r.cassette.Interactions {
+		if !r.used[i] {
+			e.Recorded = append(e.Recorded, in.Request.String())
+		}
+	}
+	r.unmatched = append(r.unmatched, e.Request)
+	return nil, e
+}
+
+func (rr RecordedRequest) matches(o RecordedRequest) bool {
+	return rr.Method == o.Method && rr.Path == o.Path && rr.Query == o.Query
+}
+
+func (rr RecordedRequest) String() string {
+	if rr.Query == "" {
+		return rr.Method + " " + rr.Path
+	}
+	return rr.Method + " " + rr.Path + "?" + rr.Query
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+func recordRequest(req *http.Request, body []byte) RecordedRequest {
+	rr := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  canonicalQuery(req.URL.Query()),
+		Body:   scrubBody(req.Header.Get("Content-Type"), body),
+	}
+	h := http.Header{}
+	for _, name := range []string{"Authorization", "Content-Type"} {
+		if v := req.Header.Get(name); v != "" {
+			h.Set(name, v)
+		}
+	}
+	if len(h) > 0 {
+		rr.Header = scrubHeader(h)
+	}
+	return rr
+}
+
+// canonicalQuery returns the query with the sorted keys and the sorted comma separated values,
+// such as "tweet.fields=author_id,created_at", where the secrets are redacted.
+func canonicalQuery(q url.Values) string {
+	c := url.Values{}
+	for k, vs := range q {
+		for _, v := range vs {
+			if isSecret(k) {
+				v = Redacted
+			} else {
+				items := strings.Split(v, ",")
+				sort.Strings(items)
+				v = strings.Join(items, ",")
+			}
+			c.Add(k, v)
+		}
+	}
+	return c.Encode()
+}
+
+// secretNames are the names of the parameters and the JSON members that are redacted,
+// in addition to the names starting with oauth_.
+var secretNames = map[string]bool{
+	"access_token":  true,
+	"refresh_token": true,
+	"token":         true,
+	"code":          true,
+	"code_verifier": true,
+	"client_secret": true,
+}
+
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	return secretNames[name] || strings.HasPrefix(name, "oauth_")
+}
+
+var secretHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+func scrubHeader(h http.Header) http.Header {
+	c := h.Clone()
+	for _, name := range secretHeaders {
+		if c.Get(name) != "" {
+			c.Set(name, Redacted)
+		}
+	}
+	return c
+}
+
+// scrubBody redacts the secrets of the JSON or the form encoded body.
+func scrubBody(contentType string, b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+
+	if strings.Contains(contentType, "json") || json.Valid(b) {
+		var v interface{}
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.UseNumber()
+		if err := d.Decode(&v); err == nil {
+			scrubJSON(v)
+			sb, _ := marshalJSON(v, "")
+			return strings.TrimSuffix(string(sb), "\n")
+		}
+	}
+
+	if strings.Contains(contentType, "x-www-form-urlencoded") || strings.Contains(contentType, "text/html") {
+		if q, err := url.ParseQuery(string(b)); err == nil {
+			for k, vs := range q {
+				if isSecret(k) {
+					for i := range vs {
+						vs[i] = Redacted
+					}
+				}
+			}
+			return q.Encode()
+		}
+	}
+	return string(b)
+}
+
+func scrubJSON(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, m := range v {
+			if _, ok := m.(string); ok && isSecret(k) {
+				v[k] = Redacted
+				continue
+			}
+			scrubJSON(m)
+		}
+	case []interface{}:
+		for _, m := range v {
+			scrubJSON(m)
+		}
+	}
+}
+
+// marshalJSON encodes v without escaping HTML characters such as &, to keep the cassettes readable.
+func marshalJSON(v interface{}, indent string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	e := json.NewEncoder(buf)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", indent)
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,184 @@
+package gotwitest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/fields"
+	"github.com/michimani/gotwi/gotwitest"
+	"github.com/michimani/gotwi/users"
+	"github.com/michimani/gotwi/users/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRecorderClient(t *testing.T, r *gotwitest.Recorder) *gotwi.GotwiClient {
+	c, err := gotwi.NewGotwiClient(&gotwi.NewGotwiClientInput{
+		HTTPClient:           r.Client(),
+		AuthenticationMethod: gotwi.AuthenMethodOAuth2BearerToken,
+		APIKey:               gotwitest.APIKey,
+		APIKeySecret:         gotwitest.APIKeySecret,
+	})
+	require.NoError(t, err)
+	return c
+}
+
+func Test_Recorder(t *testing.T) {
+	s, alice, _ := newServer(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	ctx := context.Background()
+
+	// record
+	rec, err := gotwitest.NewRecorder(path, gotwitest.ModeRecord, s.Client().Transport)
+	require.NoError(t, err)
+	c := newRecorderClient(t, rec)
+	recorded, err := users.UserLookupID(ctx, c, &types.UserLookupIDParams{
+		ID:         *alice.ID,
+		UserFields: fields.UserFieldList{fields.UserFieldCreatedAt, fields.UserFieldDescription},
+	})
+	require.NoError(t, err)
+	require.NoError(t, rec.Save())
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "gotwitest-bearer-token")
+	assert.NotContains(t, string(b), gotwitest.APIKey)
+	assert.Contains(t, string(b), gotwitest.Redacted)
+
+	// replay without the server
+	s.Close()
+	rep, err := gotwitest.NewRecorder(path, gotwitest.ModeReplay, nil)
+	require.NoError(t, err)
+	c = newRecorderClient(t, rep)
+
+	// the order of the fields does not matter
+	replayed, err := users.UserLookupID(ctx, c, &types.UserLookupIDParams{
+		ID:         *alice.ID,
+		UserFields: fields.UserFieldList{fields.UserFieldDescription, fields.UserFieldCreatedAt},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, recorded.Data, replayed.Data)
+	assert.Empty(t, rep.Unused())
+
+	// each interaction is replayed once
+	_, err = users.UserLookupID(ctx, c, &types.UserLookupIDParams{ID: *alice.ID})
+	var ue *gotwitest.UnmatchedRequestError
+	require.True(t, errors.As(err, &ue))
+	assert.True(t, strings.HasPrefix(ue.Request, "GET /2/users/"+*alice.ID))
+	assert.Len(t, rep.Unmatched(), 1)
+}
+
+func Test_Recorder_Scrub(t *testing.T) {
+	upstream := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/x-www-form-urlencoded"}, "Set-Cookie": {"session=secret"}},
+			Body:       ioutil.NopCloser(strings.NewReader("oauth_token=token-value&oauth_token_secret=token-secret-value&user_id=123")),
+			Request:    r,
+		}, nil
+	})
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec, err := gotwitest.NewRecorder(path, gotwitest.ModeRecord, upstream)
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest("POST", "https://api.twitter.com/oauth/access_token?oauth_verifier=1234&oauth_token=token-value", nil)
+	req.Header.Set("Authorization", `OAuth oauth_consumer_key="key"`)
+	res, err := rec.Client().Do(req)
+	require.NoError(t, err)
+	body, _ := ioutil.ReadAll(res.Body)
+	// the caller gets the real response
+	assert.Contains(t, string(body), "token-value")
+	require.NoError(t, rec.Save())
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "token-value")
+	assert.NotContains(t, string(b), "token-secret-value")
+	assert.NotContains(t, string(b), "1234")
+	assert.NotContains(t, string(b), "session")
+	assert.Contains(t, string(b), "user_id=123")
+}
+
+func Test_Recorder_ScrubBody(t *testing.T) {
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		expect      string
+	}{
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        `{"token_type":"bearer","access_token":"secret","data":[{"refresh_token":"secret","url":"https://t.co/a?b&c"}]}`,
+			expect:      `{"access_token":"REDACTED","data":[{"refresh_token":"REDACTED","url":"https://t.co/a?b&c"}],"token_type":"bearer"}`,
+		},
+		{
+			name:        "json without the content type",
+			contentType: "text/plain",
+			body:        `{"access_token":"secret"}`,
+			expect:      `{"access_token":"REDACTED"}`,
+		},
+		{
+			name:        "form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "user_id=123&oauth_token=secret&screen_name=alice",
+			expect:      "oauth_token=REDACTED&screen_name=alice&user_id=123",
+		},
+		{
+			name:        "form without a secret is canonicalized",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "user_id=123&screen_name=alice",
+			expect:      "screen_name=alice&user_id=123",
+		},
+		{
+			name:        "text is not parsed as a form",
+			contentType: "text/plain",
+			body:        "token=value",
+			expect:      "token=value",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			upstream := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": {c.contentType}},
+					Body:       ioutil.NopCloser(strings.NewReader(c.body)),
+					Request:    r,
+				}, nil
+			})
+			path := filepath.Join(tt.TempDir(), "cassette.json")
+			rec, err := gotwitest.NewRecorder(path, gotwitest.ModeRecord, upstream)
+			require.NoError(tt, err)
+
+			req, _ := http.NewRequest("POST", "https://api.twitter.com/2/oauth2/token", strings.NewReader(c.body))
+			req.Header.Set("Content-Type", c.contentType)
+			res, err := rec.Client().Do(req)
+			require.NoError(tt, err)
+			res.Body.Close()
+			require.NoError(tt, rec.Save())
+
+			b, err := ioutil.ReadFile(path)
+			require.NoError(tt, err)
+			cassette := &gotwitest.Cassette{}
+			require.NoError(tt, json.Unmarshal(b, cassette))
+			require.Len(tt, cassette.Interactions, 1)
+			assert.Equal(tt, c.expect, cassette.Interactions[0].Request.Body)
+			assert.Equal(tt, c.expect, cassette.Interactions[0].Response.Body)
+		})
+	}
+}
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
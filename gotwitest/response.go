@@ -0,0 +1,173 @@
+package gotwitest
+
+import (
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeProblem writes the error body of the API, which is decoded into resources.Non2XXError.
+func writeProblem(w http.ResponseWriter, status int, title, detail, typ string) {
+	writeJSON(w, status, map[string]interface{}{
+		"title":  title,
+		"detail": detail,
+		"type":   typ,
+		"status": status,
+	})
+}
+
+func writeInvalidRequest(w http.ResponseWriter, name, value string) {
+	writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"errors": []map[string]interface{}{{
+			"parameters": map[string][]string{name: {value}},
+			"message":    fmt.Sprintf("The `%s` query parameter value [%s] is not valid", name, value),
+		}},
+		"title":  "Invalid Request",
+		"detail": "One or more parameters to your request was invalid.",
+		"type":   "https://api.twitter.com/2/problems/invalid-request",
+	})
+}
+
+func writeForbidden(w http.ResponseWriter, detail string) {
+	writeProblem(w, http.StatusForbidden, "Forbidden", detail, "about:blank")
+}
+
+// notFound is the partial error of a resource that does not exist.
+func notFound(resourceType, parameter, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"value":         value,
+		"detail":        fmt.Sprintf("Could not find %s with %s: [%s].", resourceType, parameter, value),
+		"title":         "Not Found Error",
+		"resource_type": resourceType,
+		"parameter":     parameter,
+		"resource_id":   value,
+		"type":          "https://api.twitter.com/2/problems/resource-not-found",
+	}
+}
+
+// response is the body of a response. The empty members are omitted like the API.
+type response struct {
+	Data     interface{}              `json:"data,omitempty"`
+	Includes map[string][]interface{} `json:"includes,omitempty"`
+	Meta     map[string]interface{}   `json:"meta,omitempty"`
+	Errors   []map[string]interface{} `json:"errors,omitempty"`
+
+	included map[string]bool
+}
+
+// include adds the object of the ID to the includes unless it is already included.
+func (res *response) include(kind, id string, v interface{}) {
+	if res.Includes == nil {
+		res.Includes = map[string][]interface{}{}
+		res.included = map[string]bool{}
+	}
+	if res.included[kind+":"+id] {
+		return
+	}
+	res.included[kind+":"+id] = true
+	res.Includes[kind] = append(res.Includes[kind], v)
+}
+
+// project returns the object with its default fields and the fields requested by the query parameter,
+// such as tweet.fields, like the API.
+func project(v interface{}, defaults []string, requested string) map[string]interface{} {
+	b, _ := json.Marshal(v)
+	all := map[string]interface{}{}
+	json.Unmarshal(b, &all)
+
+	m := map[string]interface{}{}
+	for _, f := range append(defaults, splitComma(requested)...) {
+		if v, ok := all[f]; ok {
+			m[f] = v
+		}
+	}
+	return m
+}
+
+func splitComma(s string) []string {
+	l := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+// paginate returns the page of the items for max_results and pagination_token (or next_token) of the query,
+// and the meta of the page. The token is the opaque offset of the page.
+// It writes the error and returns false if the parameters are invalid.
+func paginate(w http.ResponseWriter, c *call, items []string, defaultMax, min, max int) ([]string, map[string]interface{}, bool) {
+	size := defaultMax
+	if v := c.query.Get("max_results"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < min || n > max {
+			writeInvalidRequest(w, "max_results", v)
+			return nil, nil, false
+		}
+		size = n
+	}
+
+	tokenName := "pagination_token"
+	token := c.query.Get(tokenName)
+	if token == "" {
+		tokenName = "next_token"
+		token = c.query.Get(tokenName)
+	}
+	offset := 0
+	if token != "" {
+		n, ok := decodeToken(token)
+		if !ok || n > len(items) {
+			writeInvalidRequest(w, tokenName, token)
+			return nil, nil, false
+		}
+		offset = n
+	}
+
+	end := offset + size
+	if end > len(items) {
+		end = len(items)
+	}
+	page := items[offset:end]
+
+	meta := map[string]interface{}{"result_count": len(page)}
+	if end < len(items) {
+		meta["next_token"] = encodeToken(end)
+	}
+	if offset > 0 {
+		prev := offset - size
+		if prev < 0 {
+			prev = 0
+		}
+		meta["previous_token"] = encodeToken(prev)
+	}
+	return page, meta, true
+}
+
+var tokenEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// encodeToken returns the token of the offset, which is lowercased like the tokens of the API.
+func encodeToken(offset int) string {
+	return strings.ToLower(tokenEncoding.EncodeToString([]byte(fmt.Sprintf("gotwitest:%d", offset))))
+}
+
+func decodeToken(token string) (int, bool) {
+	b, err := tokenEncoding.DecodeString(strings.ToUpper(token))
+	if err != nil || !strings.HasPrefix(string(b), "gotwitest:") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(string(b), "gotwitest:"))
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,218 @@
+package gotwitest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// authContext is the authentication that an endpoint supports.
+type authContext int
+
+const (
+	authAny  authContext = iota
+	authUser             // OAuth 1.0a user context or OAuth 2.0 user context
+	authApp              // OAuth 2.0 app-only
+)
+
+type route struct {
+	method  string
+	pattern string // such as /2/users/:id/following
+	auth    authContext
+	handle  func(w http.ResponseWriter, c *call)
+}
+
+// call is a request matched to a route.
+type call struct {
+	r      *http.Request
+	auth   *auth
+	params map[string]string
+	query  url.Values
+	body   []byte
+}
+
+func (c *call) param(name string) string {
+	return c.params[name]
+}
+
+// match returns the path parameters if the path matches the pattern.
+func (rt *route) match(method, path string) (map[string]string, bool) {
+	if rt.method != method {
+		return nil, false
+	}
+
+	ps := strings.Split(strings.Trim(rt.pattern, "/"), "/")
+	ss := strings.Split(strings.Trim(path, "/"), "/")
+	if len(ps) != len(ss) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+	for i, p := range ps {
+		if strings.HasPrefix(p, ":") {
+			if ss[i] == "" {
+				return nil, false
+			}
+			params[p[1:]] = ss[i]
+			continue
+		}
+		if p != ss[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// newRoutes returns the routes. The literal paths such as /2/users/me come before /2/users/:id.
+func (s *Server) newRoutes() []route {
+	return []route{
+		// users
+		{"GET", "/2/users", authAny, s.usersLookup},
+		{"GET", "/2/users/me", authUser, s.usersMe},
+		{"GET", "/2/users/by", authAny, s.usersLookupBy},
+		{"GET", "/2/users/by/username/:username", authAny, s.usersLookupByUsername},
+		{"GET", "/2/users/:id", authAny, s.usersLookupID},
+		{"GET", "/2/users/:id/following", authAny, s.relationList(RelationFollowing)},
+		{"GET", "/2/users/:id/followers", authAny, s.followers},
+		{"POST", "/2/users/:id/following", authUser, s.relationPost(RelationFollowing)},
+		{"DELETE", "/2/users/:source_user_id/following/:target_user_id", authUser, s.relationDelete(RelationFollowing)},
+		{"GET", "/2/users/:id/blocking", authUser, s.relationList(RelationBlocking)},
+		{"POST", "/2/users/:id/blocking", authUser, s.relationPost(RelationBlocking)},
+		{"DELETE", "/2/users/:source_user_id/blocking/:target_user_id", authUser, s.relationDelete(RelationBlocking)},
+		{"GET", "/2/users/:id/muting", authUser, s.relationList(RelationMuting)},
+		{"POST", "/2/users/:id/muting", authUser, s.relationPost(RelationMuting)},
+		{"DELETE", "/2/users/:source_user_id/muting/:target_user_id", authUser, s.relationDelete(RelationMuting)},
+
+		// tweets
+		{"GET", "/2/tweets", authAny, s.tweetsLookup},
+		{"POST", "/2/tweets", authUser, s.tweetsPost},
+		{"GET", "/2/tweets/search/recent", authAny, s.tweetsSearch},
+		{"GET", "/2/tweets/search/all", authApp, s.tweetsSearch},
+		{"GET", "/2/tweets/counts/recent", authApp, s.tweetsCounts},
+		{"GET", "/2/tweets/counts/all", authApp, s.tweetsCounts},
+		{"GET", "/2/tweets/search/stream/rules", authApp, s.streamRulesGet},
+		{"POST", "/2/tweets/search/stream/rules", authApp, s.streamRulesPost},
+		{"GET", "/2/tweets/search/stream", authApp, s.stream},
+		{"GET", "/2/tweets/:id", authAny, s.tweetsLookupID},
+		{"DELETE", "/2/tweets/:id", authUser, s.tweetsDelete},
+		{"PUT", "/2/tweets/:id/hidden", authUser, s.tweetsHide},
+		{"GET", "/2/users/:id/tweets", authAny, s.timeline(false)},
+		{"GET", "/2/users/:id/mentions", authAny, s.timeline(true)},
+		{"GET", "/2/tweets/:id/liking_users", authAny, s.tweetUsers(RelationLiking)},
+		{"GET", "/2/users/:id/liked_tweets", authAny, s.userTweets(RelationLiking)},
+		{"POST", "/2/users/:id/likes", authUser, s.tweetRelationPost(RelationLiking)},
+		{"DELETE", "/2/users/:id/likes/:tweet_id", authUser, s.tweetRelationDelete(RelationLiking)},
+		{"GET", "/2/tweets/:id/retweeted_by", authAny, s.tweetUsers(RelationRetweeting)},
+		{"POST", "/2/users/:id/retweets", authUser, s.tweetRelationPost(RelationRetweeting)},
+		{"DELETE", "/2/users/:id/retweets/:source_tweet_id", authUser, s.tweetRelationDelete(RelationRetweeting)},
+
+		// lists
+		{"POST", "/2/lists", authUser, s.listsPost},
+		{"GET", "/2/lists/:id", authAny, s.listsLookupID},
+		{"PUT", "/2/lists/:id", authUser, s.listsPut},
+		{"DELETE", "/2/lists/:id", authUser, s.listsDelete},
+		{"GET", "/2/users/:id/owned_lists", authAny, s.ownedLists},
+		{"GET", "/2/lists/:id/tweets", authAny, s.listTweets},
+		{"GET", "/2/lists/:id/members", authAny, s.listUsers(RelationListMember)},
+		{"GET", "/2/users/:id/list_memberships", authAny, s.userLists(RelationListMember)},
+		{"POST", "/2/lists/:id/members", authUser, s.listMembersPost},
+		{"DELETE", "/2/lists/:id/members/:user_id", authUser, s.listMembersDelete},
+		{"GET", "/2/lists/:id/followers", authAny, s.listUsers(RelationListFollowing)},
+		{"GET", "/2/users/:id/followed_lists", authAny, s.userLists(RelationListFollowing)},
+		{"POST", "/2/users/:id/followed_lists", authUser, s.listRelationPost(RelationListFollowing)},
+		{"DELETE", "/2/users/:id/followed_lists/:list_id", authUser, s.listRelationDelete(RelationListFollowing)},
+		{"GET", "/2/users/:id/pinned_lists", authUser, s.userLists(RelationListPinning)},
+		{"POST", "/2/users/:id/pinned_lists", authUser, s.listRelationPost(RelationListPinning)},
+		{"DELETE", "/2/users/:id/pinned_lists/:list_id", authUser, s.listRelationDelete(RelationListPinning)},
+
+		// spaces
+		{"GET", "/2/spaces", authAny, s.spacesLookup},
+		{"GET", "/2/spaces/search", authAny, s.spacesSearch},
+		{"GET", "/2/spaces/by/creator_ids", authAny, s.spacesByCreators},
+		{"GET", "/2/spaces/:id", authAny, s.spacesLookupID},
+	}
+}
+
+// idParams are the path parameters that must be numeric IDs.
+var idParams = []string{"id", "source_user_id", "target_user_id", "tweet_id", "source_tweet_id", "list_id", "user_id"}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == "POST" && r.URL.Path == "/oauth2/token" {
+		s.issueAppToken(w, r)
+		return
+	}
+
+	var rt *route
+	var params map[string]string
+	for i := range s.routes {
+		if p, ok := s.routes[i].match(r.Method, r.URL.Path); ok {
+			rt, params = &s.routes[i], p
+			break
+		}
+	}
+	if rt == nil {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{
+			"errors": []map[string]interface{}{{"message": "Sorry, that page does not exist", "code": 34}},
+		})
+		return
+	}
+
+	a := s.authenticate(r)
+	req := Request{Method: r.Method, Path: r.URL.Path, Endpoint: rt.pattern, Query: r.URL.Query(), Body: body}
+	if a != nil {
+		req.UserID = a.userID
+	}
+	s.requests = append(s.requests, req)
+
+	if a == nil {
+		writeProblem(w, http.StatusUnauthorized, "Unauthorized", "Unauthorized", "about:blank")
+		return
+	}
+	switch {
+	case rt.auth == authUser && a.userID == "":
+		writeProblem(w, http.StatusForbidden, "Unsupported Authentication",
+			"Authenticating with OAuth 2.0 Application-Only is forbidden for this endpoint.  Supported authentication types are [OAuth 1.0a User Context, OAuth 2.0 User Context].",
+			"https://api.twitter.com/2/problems/unsupported-authentication")
+		return
+	case rt.auth == authApp && a.userID != "":
+		writeProblem(w, http.StatusForbidden, "Unsupported Authentication",
+			"Authenticating with OAuth 1.0a User Context is forbidden for this endpoint.  Supported authentication types are [OAuth 2.0 Application-Only].",
+			"https://api.twitter.com/2/problems/unsupported-authentication")
+		return
+	}
+
+	if s.writeFailure(w, r.Method, rt.pattern) {
+		return
+	}
+	if !s.takeRateLimit(w, r.Method, rt.pattern) {
+		return
+	}
+
+	for _, name := range idParams {
+		// the IDs of spaces are not numeric
+		if v, ok := params[name]; ok && !strings.HasPrefix(rt.pattern, "/2/spaces/") && !isID(v) {
+			writeInvalidRequest(w, name, v)
+			return
+		}
+	}
+
+	rt.handle(w, &call{r: r, auth: a, params: params, query: r.URL.Query(), body: body})
+}
+
+func isID(s string) bool {
+	if s == "" || len(s) > 19 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,251 @@
+// Package gotwitest provides an in-process fake of the Twitter API v2 for tests of the code that uses gotwi.
+//
+// The Server keeps tweets, users, follows, blocks, mutes, likes, retweets, lists, list members, list follows,
+// pinned lists, spaces and filtered stream rules in memory, and serves the endpoints of gotwi on them.
+// It checks the Authorization header of every request, paginates the collections like the API,
+// and returns the error bodies of the API, so they are decoded into resources.Non2XXError or the partial errors.
+//
+//	s := gotwitest.NewServer()
+//	defer s.Close()
+//
+//	alice := s.AddUser(resources.User{Username: gotwi.String("alice")})
+//	c, _ := s.NewUserClient(gotwi.StringValue(alice.ID))
+//	res, _ := users.UserLookupMe(ctx, c, &types.UserLookupMeParams{})
+//
+// Failures and rate limits are injected with InjectFailure and SetRateLimit.
+package gotwitest
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/michimani/gotwi"
+)
+
+const (
+	// APIKey and APIKeySecret are the credentials of the app. The server accepts only them.
+	APIKey       = "gotwitest-api-key"
+	APIKeySecret = "gotwitest-api-key-secret"
+)
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	// Path is such as /2/users/123/following, and Endpoint is its template such as /2/users/:id/following.
+	Path     string
+	Endpoint string
+	Query    url.Values
+	Body     []byte
+	// UserID is the authenticated user, or empty for the app-only authentication.
+	UserID string
+}
+
+// Server is the fake Twitter API server. Its methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// KeepAlive is the interval of the keep-alive signals of the filtered stream.
+	KeepAlive time.Duration
+
+	mu       sync.Mutex
+	now      func() time.Time
+	nextID   int64
+	requests []Request
+	routes   []route
+
+	// credentials
+	appTokens  map[string]bool
+	userTokens map[string]*userToken // by OAuth 1.0a token or OAuth 2.0 user access token
+
+	state
+	failures   []*Failure
+	rateLimits map[string]*rateLimit
+	streams    map[chan streamMessage]struct{}
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+type userToken struct {
+	userID string
+	secret string
+}
+
+// NewServer starts a server. It must be closed by Close.
+func NewServer() *Server {
+	s := &Server{
+		KeepAlive:  20 * time.Second,
+		now:        time.Now,
+		nextID:     1460000000000000000,
+		appTokens:  map[string]bool{},
+		userTokens: map[string]*userToken{},
+		state:      newState(),
+		rateLimits: map[string]*rateLimit{},
+		streams:    map[chan streamMessage]struct{}{},
+		done:       make(chan struct{}),
+	}
+	s.routes = s.newRoutes()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close ends the filtered streams, and shuts down the server. It can be called more than once.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.Server.Close()
+	})
+}
+
+// Client returns an http.Client that sends the requests for the Twitter API to the server,
+// to be set as NewGotwiClientInput.HTTPClient.
+func (s *Server) Client() *http.Client {
+	u, _ := url.Parse(s.URL)
+	return &http.Client{Transport: &rewriteTransport{host: u.Host}}
+}
+
+type rewriteTransport struct {
+	host string
+}
+
+func (t *rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = "http"
+	r.URL.Host = t.host
+	r.Host = t.host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// NewAppClient returns a GotwiClient with the OAuth 2.0 app-only authentication.
+func (s *Server) NewAppClient() (*gotwi.GotwiClient, error) {
+	return gotwi.NewGotwiClient(&gotwi.NewGotwiClientInput{
+		HTTPClient:           s.Client(),
+		AuthenticationMethod: gotwi.AuthenMethodOAuth2BearerToken,
+		APIKey:               APIKey,
+		APIKeySecret:         APIKeySecret,
+	})
+}
+
+// NewUserClient returns a GotwiClient with the OAuth 1.0a user context of the user.
+func (s *Server) NewUserClient(userID string) (*gotwi.GotwiClient, error) {
+	token, secret := s.UserToken(userID)
+	return gotwi.NewGotwiClient(&gotwi.NewGotwiClientInput{
+		HTTPClient:           s.Client(),
+		AuthenticationMethod: gotwi.AuthenMethodOAuth1UserContext,
+		APIKey:               APIKey,
+		APIKeySecret:         APIKeySecret,
+		OAuthToken:           token,
+		OAuthTokenSecret:     secret,
+	})
+}
+
+// UserToken returns the OAuth 1.0a access token and secret of the user.
+func (s *Server) UserToken(userID string) (token, secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token = userID + "-gotwitest-token"
+	secret = userID + "-gotwitest-token-secret"
+	s.userTokens[token] = &userToken{userID: userID, secret: secret}
+	return token, secret
+}
+
+// UserAccessToken returns the OAuth 2.0 user access token of the user, to be set as NewGotwiClientInput.AccessToken.
+func (s *Server) UserAccessToken(userID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token := base64.RawURLEncoding.EncodeToString([]byte(userID + ":gotwitest-user-access-token"))
+	s.userTokens[token] = &userToken{userID: userID}
+	return token
+}
+
+// SetNow sets the clock of the server, which is used for the created_at of the new objects and the rate limits.
+func (s *Server) SetNow(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// Requests returns the requests received by the server.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// newID returns an ID like a snowflake ID, which increases in the order of creation. It must be called with the lock.
+func (s *Server) newID() string {
+	s.nextID++
+	return strconv.FormatInt(s.nextID, 10)
+}
+
+// auth is the authentication of a request.
+type auth struct {
+	// userID is empty for the app-only authentication.
+	userID string
+}
+
+// authenticate returns the authentication of the Authorization header, or nil if it is invalid.
+// It must be called with the lock.
+func (s *Server) authenticate(r *http.Request) *auth {
+	h := r.Header.Get("Authorization")
+	switch {
+	case strings.HasPrefix(h, "Bearer "):
+		token := strings.TrimPrefix(h, "Bearer ")
+		if s.appTokens[token] {
+			return &auth{}
+		}
+		if ut, ok := s.userTokens[token]; ok && ut.secret == "" {
+			return &auth{userID: ut.userID}
+		}
+	case strings.HasPrefix(h, "OAuth "):
+		params := parseOAuthHeader(strings.TrimPrefix(h, "OAuth "))
+		if params["oauth_consumer_key"] != APIKey ||
+			params["oauth_signature_method"] != gotwi.OAuthSignatureMethodHMACSHA1 ||
+			params["oauth_signature"] == "" || params["oauth_nonce"] == "" || params["oauth_timestamp"] == "" {
+			return nil
+		}
+		if ut, ok := s.userTokens[params["oauth_token"]]; ok && ut.secret != "" {
+			return &auth{userID: ut.userID}
+		}
+	}
+	return nil
+}
+
+// parseOAuthHeader parses such as `oauth_consumer_key="key",oauth_token="token"`.
+func parseOAuthHeader(h string) map[string]string {
+	m := map[string]string{}
+	for _, kv := range strings.Split(h, ",") {
+		i := strings.Index(kv, "=")
+		if i < 0 {
+			continue
+		}
+		v, err := url.QueryUnescape(strings.Trim(strings.TrimSpace(kv[i+1:]), `"`))
+		if err != nil {
+			continue
+		}
+		m[strings.TrimSpace(kv[:i])] = v
+	}
+	return m
+}
+
+// issueAppToken serves POST /oauth2/token of the app-only authentication.
+func (s *Server) issueAppToken(w http.ResponseWriter, r *http.Request) {
+	key, secret, ok := r.BasicAuth()
+	if !ok || key != APIKey || secret != APIKeySecret {
+		writeJSON(w, http.StatusForbidden, map[string]interface{}{
+			"errors": []map[string]interface{}{{"code": 99, "label": "authenticity_token_error", "message": "Unable to verify your credentials"}},
+		})
+		return
+	}
+
+	token := "gotwitest-bearer-token"
+	s.appTokens[token] = true
+	writeJSON(w, http.StatusOK, map[string]string{"token_type": "bearer", "access_token": token})
+}
@@ -0,0 +1,211 @@
+package gotwitest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/fields"
+	"github.com/michimani/gotwi/gotwitest"
+	"github.com/michimani/gotwi/resources"
+	"github.com/michimani/gotwi/tweets"
+	ttypes "github.com/michimani/gotwi/tweets/types"
+	"github.com/michimani/gotwi/users"
+	utypes "github.com/michimani/gotwi/users/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T) (*gotwitest.Server, *resources.User, *resources.User) {
+	s := gotwitest.NewServer()
+	t.Cleanup(s.Close)
+
+	alice := s.AddUser(resources.User{Username: gotwi.String("alice")})
+	bob := s.AddUser(resources.User{Username: gotwi.String("bob")})
+	return s, alice, bob
+}
+
+func Test_Server_Users(t *testing.T) {
+	s, alice, bob := newServer(t)
+	c, err := s.NewUserClient(*alice.ID)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	me, err := users.UserLookupMe(ctx, c, &utypes.UserLookupMeParams{})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", gotwi.StringValue(me.Data.Username))
+
+	lookup, err := users.UserLookup(ctx, c, &utypes.UserLookupParams{IDs: []string{*bob.ID, "1"}})
+	require.NoError(t, err)
+	require.Len(t, lookup.Data, 1)
+	assert.Equal(t, "bob", gotwi.StringValue(lookup.Data[0].Username))
+	require.Len(t, lookup.Errors, 1)
+	assert.Equal(t, "1", gotwi.StringValue(lookup.Errors[0].ResourceId))
+
+	post, err := users.FollowsFollowingPost(ctx, c, &utypes.FollowsFollowingPostParams{ID: *alice.ID, TargetUserID: bob.ID})
+	require.NoError(t, err)
+	assert.True(t, post.Data.Following)
+	assert.Equal(t, []string{*bob.ID}, s.Related(gotwitest.RelationFollowing, *alice.ID))
+
+	// alice cannot follow on behalf of bob
+	_, err = users.FollowsFollowingPost(ctx, c, &utypes.FollowsFollowingPostParams{ID: *bob.ID, TargetUserID: alice.ID})
+	var e *resources.Non2XXError
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, http.StatusForbidden, gotwi.IntValue(e.StatusCode))
+}
+
+func Test_Server_Pagination(t *testing.T) {
+	s, alice, _ := newServer(t)
+	for i := 0; i < 5; i++ {
+		u := s.AddUser(resources.User{Username: gotwi.String("follower")})
+		s.Relate(gotwitest.RelationFollowing, *u.ID, *alice.ID)
+	}
+	c, err := s.NewAppClient()
+	require.NoError(t, err)
+
+	ids := []string{}
+	token := ""
+	for {
+		res, err := users.FollowsFollowers(context.Background(), c, &utypes.FollowsFollowersParams{ID: *alice.ID, MaxResults: 2, PaginationToken: token})
+		require.NoError(t, err)
+		for _, u := range res.Data {
+			ids = append(ids, *u.ID)
+		}
+		if res.Meta.NextToken == nil {
+			break
+		}
+		token = *res.Meta.NextToken
+	}
+	assert.Equal(t, s.RelatedTo(gotwitest.RelationFollowing, *alice.ID), ids)
+	assert.Len(t, ids, 5)
+}
+
+func Test_Server_Authentication(t *testing.T) {
+	cases := []struct {
+		name   string
+		client func(s *gotwitest.Server) (*gotwi.GotwiClient, error)
+		expect int
+	}{
+		{
+			name: "unknown token",
+			client: func(s *gotwitest.Server) (*gotwi.GotwiClient, error) {
+				return gotwi.NewGotwiClient(&gotwi.NewGotwiClientInput{
+					HTTPClient:           s.Client(),
+					AuthenticationMethod: gotwi.AuthenMethodOAuth1UserContext,
+					APIKey:               gotwitest.APIKey,
+					APIKeySecret:         gotwitest.APIKeySecret,
+					OAuthToken:           "unknown",
+					OAuthTokenSecret:     "unknown",
+				})
+			},
+			expect: http.StatusUnauthorized,
+		},
+		{
+			name:   "app-only for user context endpoint",
+			client: func(s *gotwitest.Server) (*gotwi.GotwiClient, error) { return s.NewAppClient() },
+			expect: http.StatusForbidden,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			s, _, _ := newServer(tt)
+			client, err := c.client(s)
+			require.NoError(tt, err)
+
+			_, err = users.UserLookupMe(context.Background(), client, &utypes.UserLookupMeParams{})
+			var e *resources.Non2XXError
+			require.True(tt, errors.As(err, &e))
+			assert.Equal(tt, c.expect, gotwi.IntValue(e.StatusCode))
+		})
+	}
+}
+
+func Test_Server_Failures(t *testing.T) {
+	s, alice, _ := newServer(t)
+	c, err := s.NewUserClient(*alice.ID)
+	require.NoError(t, err)
+	ctx := context.Background()
+	now := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+	s.SetNow(func() time.Time { return now })
+
+	s.InjectFailure(gotwitest.Failure{Method: "GET", Endpoint: "/2/users/me", StatusCode: http.StatusServiceUnavailable, Times: 1})
+	_, err = users.UserLookupMe(ctx, c, &utypes.UserLookupMeParams{})
+	var e *resources.Non2XXError
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, http.StatusServiceUnavailable, gotwi.IntValue(e.StatusCode))
+
+	s.SetRateLimit("GET", "/2/users/me", 1, 15*time.Minute)
+	_, err = users.UserLookupMe(ctx, c, &utypes.UserLookupMeParams{})
+	require.NoError(t, err)
+	_, err = users.UserLookupMe(ctx, c, &utypes.UserLookupMeParams{})
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, http.StatusTooManyRequests, gotwi.IntValue(e.StatusCode))
+	require.NotNil(t, e.RateLimitInfo)
+	assert.Equal(t, 0, e.RateLimitInfo.Remaining)
+	assert.True(t, now.Add(15*time.Minute).Equal(*e.RateLimitInfo.ResetAt))
+
+	// the window is reset
+	now = now.Add(15 * time.Minute)
+	_, err = users.UserLookupMe(ctx, c, &utypes.UserLookupMeParams{})
+	assert.NoError(t, err)
+}
+
+func Test_Server_Tweets(t *testing.T) {
+	s, alice, bob := newServer(t)
+	c, err := s.NewUserClient(*alice.ID)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	s.AddTweet(resources.Tweet{Text: gotwi.String("hello gopher"), AuthorID: bob.ID})
+	posted, err := tweets.ManageTweetsPost(ctx, c, &ttypes.ManageTweetsPostParams{Text: gotwi.String("hello world")})
+	require.NoError(t, err)
+
+	res, err := tweets.SearchTweetsRecent(ctx, c, &ttypes.SearchTweetsRecentParams{
+		Query:      "hello -gopher",
+		Expansions: fields.ExpansionList{fields.ExpansionAuthorID},
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Data, 1)
+	assert.Equal(t, gotwi.StringValue(posted.Data.ID), gotwi.StringValue(res.Data[0].ID))
+	require.Len(t, res.Includes.Users, 1)
+	assert.Equal(t, "alice", gotwi.StringValue(res.Includes.Users[0].Username))
+
+	// bob cannot delete the tweet of alice
+	bc, err := s.NewUserClient(*bob.ID)
+	require.NoError(t, err)
+	_, err = tweets.ManageTweetsDelete(ctx, bc, &ttypes.ManageTweetsDeleteParams{ID: gotwi.StringValue(posted.Data.ID)})
+	assert.Error(t, err)
+	assert.NotNil(t, s.Tweet(gotwi.StringValue(posted.Data.ID)))
+}
+
+func Test_Server_FilteredStream(t *testing.T) {
+	s, alice, _ := newServer(t)
+	s.KeepAlive = 10 * time.Millisecond
+	c, err := s.NewAppClient()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = tweets.FilteredStreamRulesPost(ctx, c, &ttypes.FilteredStreamRulesPostParams{
+		Add: []ttypes.FilteredStreamRulesPostParamsAdd{{Value: gotwi.String("gopher"), Tag: gotwi.String("go")}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gopher"}, s.StreamRules())
+
+	stream, err := tweets.FilteredStreamSearch(ctx, c, &ttypes.FilteredStreamSearchParams{})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	s.AddTweet(resources.Tweet{Text: gotwi.String("hello world"), AuthorID: alice.ID})
+	tw := s.AddTweet(resources.Tweet{Text: gotwi.String("hello gopher"), AuthorID: alice.ID})
+
+	require.True(t, stream.Next())
+	res, err := stream.Response()
+	require.NoError(t, err)
+	assert.Equal(t, *tw.ID, gotwi.StringValue(res.Data.ID))
+	require.Len(t, res.MatchingRules, 1)
+	assert.Equal(t, "go", gotwi.StringValue(res.MatchingRules[0].Tag))
+}
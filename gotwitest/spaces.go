@@ -0,0 +1,128 @@
+package gotwitest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/resources"
+)
+
+// spaceData returns the space with the fields of the query, and adds its expansions to the response.
+func (s *Server) spaceData(c *call, sp *resources.Space, res *response) map[string]interface{} {
+	if c.hasExpansion("creator_id") {
+		s.includeUser(c, sp.CreatorID, res)
+	}
+	for name, ids := range map[string][]*string{"host_ids": sp.HostIDs, "invited_user_ids": sp.InvitedUserIDs, "speaker_ids": sp.SpeakerIDs} {
+		if c.hasExpansion(name) {
+			for _, id := range ids {
+				s.includeUser(c, id, res)
+			}
+		}
+	}
+	return project(sp, spaceDefaults, c.query.Get("space.fields"))
+}
+
+// writeSpaces writes the spaces. The meta has result_count if withMeta.
+func (s *Server) writeSpaces(w http.ResponseWriter, c *call, spaces []*resources.Space, withMeta bool) {
+	res := &response{}
+	data := []interface{}{}
+	for _, sp := range spaces {
+		data = append(data, s.spaceData(c, sp, res))
+	}
+	if len(data) > 0 {
+		res.Data = data
+	}
+	if withMeta {
+		res.Meta = map[string]interface{}{"result_count": len(data)}
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// sortedSpaces returns the spaces matching the function, in the order of the newest first.
+func (s *Server) sortedSpaces(match func(sp *resources.Space) bool) []*resources.Space {
+	l := []*resources.Space{}
+	for _, sp := range s.spaces {
+		if match(sp) {
+			l = append(l, sp)
+		}
+	}
+	sort.Slice(l, func(i, j int) bool {
+		if !l[i].CreatedAt.Equal(*l[j].CreatedAt) {
+			return l[i].CreatedAt.After(*l[j].CreatedAt)
+		}
+		return *l[i].ID > *l[j].ID
+	})
+	return l
+}
+
+func (s *Server) spacesLookup(w http.ResponseWriter, c *call) {
+	ids := splitComma(c.query.Get("ids"))
+	if len(ids) == 0 || len(ids) > 100 {
+		writeInvalidRequest(w, "ids", strings.Join(ids, ","))
+		return
+	}
+
+	res := &response{}
+	data := []interface{}{}
+	for _, id := range ids {
+		if sp, ok := s.spaces[id]; ok {
+			data = append(data, s.spaceData(c, sp, res))
+		} else {
+			res.Errors = append(res.Errors, notFound("space", "ids", id))
+		}
+	}
+	if len(data) > 0 {
+		res.Data = data
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) spacesLookupID(w http.ResponseWriter, c *call) {
+	res := &response{}
+	if sp, ok := s.spaces[c.param("id")]; ok {
+		res.Data = s.spaceData(c, sp, res)
+	} else {
+		res.Errors = append(res.Errors, notFound("space", "id", c.param("id")))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// spacesSearch serves the spaces whose titles contain the query.
+func (s *Server) spacesSearch(w http.ResponseWriter, c *call) {
+	q := strings.ToLower(strings.TrimSpace(c.query.Get("query")))
+	if q == "" {
+		writeInvalidRequest(w, "query", c.query.Get("query"))
+		return
+	}
+	state := c.query.Get("state")
+	if state == "" {
+		state = "all"
+	}
+	if state != "all" && state != "live" && state != "scheduled" {
+		writeInvalidRequest(w, "state", state)
+		return
+	}
+
+	spaces := s.sortedSpaces(func(sp *resources.Space) bool {
+		return (state == "all" || gotwi.StringValue(sp.State) == state) &&
+			strings.Contains(strings.ToLower(gotwi.StringValue(sp.Title)), q)
+	})
+	s.writeSpaces(w, c, spaces, true)
+}
+
+func (s *Server) spacesByCreators(w http.ResponseWriter, c *call) {
+	ids := splitComma(c.query.Get("user_ids"))
+	if len(ids) == 0 || len(ids) > 100 {
+		writeInvalidRequest(w, "user_ids", strings.Join(ids, ","))
+		return
+	}
+	creators := map[string]bool{}
+	for _, id := range ids {
+		creators[id] = true
+	}
+
+	spaces := s.sortedSpaces(func(sp *resources.Space) bool { return creators[gotwi.StringValue(sp.CreatorID)] })
+	s.writeSpaces(w, c, spaces, true)
+}
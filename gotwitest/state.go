@@ -0,0 +1,325 @@
+package gotwitest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/resources"
+)
+
+// Relation is a relation from a user to a user, a tweet or a list.
+type Relation string
+
+const (
+	RelationFollowing     Relation = "following"      // user to user
+	RelationBlocking      Relation = "blocking"       // user to user
+	RelationMuting        Relation = "muting"         // user to user
+	RelationLiking        Relation = "liking"         // user to tweet
+	RelationRetweeting    Relation = "retweeting"     // user to tweet
+	RelationListMember    Relation = "list_member"    // user to list, the user is a member of the list
+	RelationListFollowing Relation = "list_following" // user to list
+	RelationListPinning   Relation = "list_pinning"   // user to list
+)
+
+type edge struct {
+	from string
+	to   string
+}
+
+type tweet struct {
+	resources.Tweet
+	hidden bool
+}
+
+type streamRule struct {
+	id    string
+	value string
+	tag   string
+}
+
+type state struct {
+	users  map[string]*resources.User
+	tweets map[string]*tweet
+	lists  map[string]*resources.List
+	spaces map[string]*resources.Space
+	// edges are the relations in the order of creation.
+	edges map[Relation][]edge
+	rules []streamRule
+}
+
+func newState() state {
+	return state{
+		users:  map[string]*resources.User{},
+		tweets: map[string]*tweet{},
+		lists:  map[string]*resources.List{},
+		spaces: map[string]*resources.Space{},
+		edges:  map[Relation][]edge{},
+	}
+}
+
+// AddUser adds the user and returns it. The ID is generated if nil, and Name is Username if nil.
+func (s *Server) AddUser(u resources.User) *resources.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u.ID == nil {
+		u.ID = gotwi.String(s.newID())
+	}
+	if u.Name == nil {
+		u.Name = u.Username
+	}
+	if u.CreatedAt == nil {
+		u.CreatedAt = s.nowPtr()
+	}
+	s.users[*u.ID] = &u
+
+	c := u
+	return &c
+}
+
+// User returns the user, or nil if it does not exist.
+func (s *Server) User(id string) *resources.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return nil
+	}
+	c := *u
+	return &c
+}
+
+// AddTweet adds the tweet and returns it. The ID is generated if nil.
+// The tweet is sent to the filtered streams if it matches the rules.
+func (s *Server) AddTweet(t resources.Tweet) *resources.Tweet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addTweet(t)
+}
+
+func (s *Server) addTweet(t resources.Tweet) *resources.Tweet {
+	if t.ID == nil {
+		t.ID = gotwi.String(s.newID())
+	}
+	if t.Text == nil {
+		t.Text = gotwi.String("")
+	}
+	if t.CreatedAt == nil {
+		t.CreatedAt = s.nowPtr()
+	}
+	if t.ConversationId == nil {
+		t.ConversationId = t.ID
+	}
+	s.tweets[*t.ID] = &tweet{Tweet: t}
+	s.publish(&t)
+
+	c := t
+	return &c
+}
+
+// Tweet returns the tweet, or nil if it does not exist.
+func (s *Server) Tweet(id string) *resources.Tweet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tweets[id]
+	if !ok {
+		return nil
+	}
+	c := t.Tweet
+	return &c
+}
+
+// AddList adds the list and returns it. The ID is generated if nil.
+func (s *Server) AddList(l resources.List) *resources.List {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if l.ID == nil {
+		l.ID = gotwi.String(s.newID())
+	}
+	if l.CreatedAt == nil {
+		l.CreatedAt = s.nowPtr()
+	}
+	if l.Private == nil {
+		l.Private = gotwi.Bool(false)
+	}
+	s.lists[*l.ID] = &l
+
+	c := l
+	return &c
+}
+
+// List returns the list, or nil if it does not exist.
+func (s *Server) List(id string) *resources.List {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.lists[id]
+	if !ok {
+		return nil
+	}
+	c := s.listObject(l)
+	return &c
+}
+
+// AddSpace adds the space and returns it. The ID is generated if nil, and State is "live" if nil.
+func (s *Server) AddSpace(sp resources.Space) *resources.Space {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sp.ID == nil {
+		s.nextID++
+		sp.ID = gotwi.String(fmt.Sprintf("1%012s", strings.ToUpper(strconv.FormatInt(s.nextID, 36))))
+	}
+	if sp.State == nil {
+		sp.State = gotwi.String("live")
+	}
+	if sp.CreatedAt == nil {
+		sp.CreatedAt = s.nowPtr()
+	}
+	s.spaces[*sp.ID] = &sp
+
+	c := sp
+	return &c
+}
+
+// Relate adds the relation from the user to the user, the tweet or the list. It is noop if it already exists.
+func (s *Server) Relate(r Relation, from, to string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.relate(r, from, to)
+}
+
+// Unrelate removes the relation.
+func (s *Server) Unrelate(r Relation, from, to string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unrelate(r, from, to)
+}
+
+// Related returns the IDs related from the user, in the order of the newest first like the API.
+func (s *Server) Related(r Relation, from string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.related(r, from)
+}
+
+// RelatedTo returns the IDs of the users related to the ID, in the order of the newest first like the API.
+func (s *Server) RelatedTo(r Relation, to string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.relatedTo(r, to)
+}
+
+func (s *Server) relate(r Relation, from, to string) bool {
+	if s.hasRelation(r, from, to) {
+		return false
+	}
+	s.edges[r] = append(s.edges[r], edge{from: from, to: to})
+	return true
+}
+
+func (s *Server) unrelate(r Relation, from, to string) bool {
+	for i, e := range s.edges[r] {
+		if e.from == from && e.to == to {
+			s.edges[r] = append(s.edges[r][:i:i], s.edges[r][i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) hasRelation(r Relation, from, to string) bool {
+	for _, e := range s.edges[r] {
+		if e.from == from && e.to == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) related(r Relation, from string) []string {
+	ids := []string{}
+	es := s.edges[r]
+	for i := len(es) - 1; i >= 0; i-- {
+		if es[i].from == from {
+			ids = append(ids, es[i].to)
+		}
+	}
+	return ids
+}
+
+func (s *Server) relatedTo(r Relation, to string) []string {
+	ids := []string{}
+	es := s.edges[r]
+	for i := len(es) - 1; i >= 0; i-- {
+		if es[i].to == to {
+			ids = append(ids, es[i].from)
+		}
+	}
+	return ids
+}
+
+// removeRelationsOf removes the relations to the ID, such as the likes of a deleted tweet.
+func (s *Server) removeRelationsOf(r Relation, id string) {
+	es := []edge{}
+	for _, e := range s.edges[r] {
+		if e.from != id && e.to != id {
+			es = append(es, e)
+		}
+	}
+	s.edges[r] = es
+}
+
+func (s *Server) nowPtr() *time.Time {
+	t := s.now().UTC().Truncate(time.Millisecond)
+	return &t
+}
+
+// userObject returns the user with the public metrics of the state, if the metrics are not set.
+func (s *Server) userObject(u *resources.User) resources.User {
+	c := *u
+	if c.PublicMetrics == nil {
+		tweets := 0
+		for _, t := range s.tweets {
+			if gotwi.StringValue(t.AuthorID) == *u.ID {
+				tweets++
+			}
+		}
+		c.PublicMetrics = &resources.UserPublicMetrics{
+			FollowersCount: gotwi.Int(len(s.relatedTo(RelationFollowing, *u.ID))),
+			FollowingCount: gotwi.Int(len(s.related(RelationFollowing, *u.ID))),
+			TweetCount:     gotwi.Int(tweets),
+			ListedCount:    gotwi.Int(len(s.related(RelationListMember, *u.ID))),
+		}
+	}
+	if c.Protected == nil {
+		c.Protected = gotwi.Bool(false)
+	}
+	return c
+}
+
+// listObject returns the list with the counts of the state.
+func (s *Server) listObject(l *resources.List) resources.List {
+	c := *l
+	c.MemberCount = gotwi.Int(len(s.relatedTo(RelationListMember, *l.ID)))
+	c.FollowerCount = gotwi.Int(len(s.relatedTo(RelationListFollowing, *l.ID)))
+	return c
+}
+
+// sortNewest sorts the tweet IDs in the order of the newest first.
+func sortNewest(ids []string) {
+	sort.Slice(ids, func(i, j int) bool {
+		if len(ids[i]) != len(ids[j]) {
+			return len(ids[i]) > len(ids[j])
+		}
+		return ids[i] > ids[j]
+	})
+}
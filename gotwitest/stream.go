@@ -0,0 +1,262 @@
+package gotwitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/michimani/gotwi/resources"
+)
+
+// streamMessage is a tweet matching the rules, to be sent to the filtered streams.
+type streamMessage struct {
+	tweetID string
+	rules   []streamRule
+}
+
+// streamBufferSize is the number of the messages buffered for a stream. The messages over it are dropped.
+const streamBufferSize = 100
+
+// publish sends the tweet to the filtered streams if it matches the rules. It must be called with the lock.
+func (s *Server) publish(t *resources.Tweet) {
+	if len(s.streams) == 0 {
+		return
+	}
+
+	m := streamMessage{tweetID: *t.ID}
+	for _, r := range s.rules {
+		if s.matchQuery(r.value, s.tweets[*t.ID]) {
+			m.rules = append(m.rules, r)
+		}
+	}
+	if len(m.rules) == 0 {
+		return
+	}
+
+	for ch := range s.streams {
+		select {
+		case ch <- m:
+		default:
+		}
+	}
+}
+
+func ruleData(r streamRule) map[string]interface{} {
+	m := map[string]interface{}{"id": r.id, "value": r.value}
+	if r.tag != "" {
+		m["tag"] = r.tag
+	}
+	return m
+}
+
+func (s *Server) streamRulesGet(w http.ResponseWriter, c *call) {
+	ids := map[string]bool{}
+	for _, id := range splitComma(c.query.Get("ids")) {
+		ids[id] = true
+	}
+
+	data := []interface{}{}
+	for _, r := range s.rules {
+		if len(ids) == 0 || ids[r.id] {
+			data = append(data, ruleData(r))
+		}
+	}
+
+	res := &response{Meta: map[string]interface{}{"sent": s.now().UTC(), "result_count": len(data)}}
+	if len(data) > 0 {
+		res.Data = data
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// streamRulesPost adds or deletes the rules. The rules are not changed if dry_run is true.
+func (s *Server) streamRulesPost(w http.ResponseWriter, c *call) {
+	body := struct {
+		Add []struct {
+			Value string `json:"value"`
+			Tag   string `json:"tag"`
+		} `json:"add"`
+		Delete *struct {
+			IDs []string `json:"ids"`
+		} `json:"delete"`
+	}{}
+	if !decodeBody(w, c, &body) {
+		return
+	}
+	if (len(body.Add) == 0) == (body.Delete == nil) {
+		writeProblem(w, http.StatusBadRequest, "Invalid Request", "One of add or delete is required.", "https://api.twitter.com/2/problems/invalid-request")
+		return
+	}
+	dryRun := c.query.Get("dry_run") == "true"
+	res := &response{}
+
+	if body.Delete != nil {
+		deleted := 0
+		rules := []streamRule{}
+		for _, r := range s.rules {
+			if contains(body.Delete.IDs, r.id) {
+				deleted++
+				continue
+			}
+			rules = append(rules, r)
+		}
+		if !dryRun {
+			s.rules = rules
+		}
+		res.Meta = map[string]interface{}{
+			"sent":    s.now().UTC(),
+			"summary": map[string]int{"deleted": deleted, "not_deleted": len(body.Delete.IDs) - deleted},
+		}
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+
+	data := []interface{}{}
+	rules := s.rules
+	for _, a := range body.Add {
+		if strings.TrimSpace(a.Value) == "" {
+			res.Errors = append(res.Errors, map[string]interface{}{"value": a.Value, "title": "InvalidRule"})
+			continue
+		}
+		duplicated := false
+		for _, r := range rules {
+			if r.value == a.Value {
+				duplicated = true
+				res.Errors = append(res.Errors, map[string]interface{}{"value": a.Value, "id": r.id, "title": "DuplicateRule"})
+			}
+		}
+		if duplicated {
+			continue
+		}
+		r := streamRule{id: s.newID(), value: a.Value, tag: a.Tag}
+		rules = append(rules, r)
+		data = append(data, ruleData(r))
+	}
+	if !dryRun {
+		s.rules = rules
+	}
+
+	if len(data) > 0 {
+		res.Data = data
+	}
+	res.Meta = map[string]interface{}{
+		"sent": s.now().UTC(),
+		"summary": map[string]int{
+			"created":     len(data),
+			"not_created": len(body.Add) - len(data),
+			"valid":       len(data),
+			"invalid":     len(body.Add) - len(data),
+		},
+	}
+	writeJSON(w, http.StatusCreated, res)
+}
+
+func contains(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// stream serves the filtered stream. It sends the tweets added after the connection, such as by AddTweet,
+// which match the rules, and the keep-alive signals, until the client closes the connection or the server is closed.
+// It is called with the lock, and unlocks it while streaming.
+func (s *Server) stream(w http.ResponseWriter, c *call) {
+	flusher, _ := w.(http.Flusher)
+	flush := func() {
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	ch := make(chan streamMessage, streamBufferSize)
+	s.streams[ch] = struct{}{}
+	keepAlive := s.KeepAlive
+	if keepAlive <= 0 {
+		keepAlive = 20 * time.Second
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	flush()
+
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.streams, ch)
+	}()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-c.r.Context().Done():
+			return
+		case <-ticker.C:
+			w.Write([]byte("\r\n"))
+			flush()
+		case m := <-ch:
+			b, ok := s.streamLine(c, m)
+			if !ok {
+				continue
+			}
+			w.Write(append(b, '\r', '\n'))
+			flush()
+		}
+	}
+}
+
+// streamLine returns the JSON of the message, or false if the tweet is already deleted.
+func (s *Server) streamLine(c *call, m streamMessage) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tweets[m.tweetID]
+	if !ok {
+		return nil, false
+	}
+
+	res := struct {
+		*response
+		MatchingRules []map[string]interface{} `json:"matching_rules"`
+	}{response: &response{}}
+	res.Data = s.tweetData(c, t, res.response)
+	for _, r := range m.rules {
+		mr := map[string]interface{}{"id": r.id}
+		if r.tag != "" {
+			mr["tag"] = r.tag
+		}
+		res.MatchingRules = append(res.MatchingRules, mr)
+	}
+
+	b, _ := json.Marshal(res)
+	return b, true
+}
+
+// StreamRules returns the values of the rules of the filtered stream.
+func (s *Server) StreamRules() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := []string{}
+	for _, r := range s.rules {
+		l = append(l, r.value)
+	}
+	return l
+}
+
+// AddStreamRule adds the rule of the filtered stream, and returns its ID.
+func (s *Server) AddStreamRule(value, tag string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := streamRule{id: s.newID(), value: value, tag: tag}
+	s.rules = append(s.rules, r)
+	return r.id
+}
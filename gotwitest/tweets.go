@@ -0,0 +1,465 @@
+package gotwitest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/resources"
+)
+
+// tweetObject returns the tweet with the public metrics of the state, if the metrics are not set.
+func (s *Server) tweetObject(t *tweet) resources.Tweet {
+	c := t.Tweet
+	if c.PublicMetrics == nil {
+		replies := 0
+		for _, o := range s.tweets {
+			if id := o.RepliedToTweetID(); id != nil && *id == *t.ID {
+				replies++
+			}
+		}
+		c.PublicMetrics = &resources.TweetPublicMetrics{
+			RetweetCount: gotwi.Int(len(s.relatedTo(RelationRetweeting, *t.ID))),
+			ReplyCount:   gotwi.Int(replies),
+			LikeCount:    gotwi.Int(len(s.relatedTo(RelationLiking, *t.ID))),
+			QuoteCount:   gotwi.Int(0),
+		}
+	}
+	return c
+}
+
+// tweetData returns the tweet with the fields of the query, and adds its expansions to the response.
+func (s *Server) tweetData(c *call, t *tweet, res *response) map[string]interface{} {
+	if c.hasExpansion("author_id") {
+		s.includeUser(c, t.AuthorID, res)
+	}
+	if c.hasExpansion("in_reply_to_user_id") {
+		s.includeUser(c, t.InReplyToUserID, res)
+	}
+	for _, ref := range t.ReferencedTweets {
+		rt, ok := s.tweets[gotwi.StringValue(ref.ID)]
+		if !ok {
+			continue
+		}
+		if c.hasExpansion("referenced_tweets.id") {
+			res.include("tweets", *rt.ID, project(s.tweetObject(rt), tweetDefaults, c.query.Get("tweet.fields")))
+		}
+		if c.hasExpansion("referenced_tweets.id.author_id") {
+			s.includeUser(c, rt.AuthorID, res)
+		}
+	}
+	return project(s.tweetObject(t), tweetDefaults, c.query.Get("tweet.fields"))
+}
+
+// writeTweetPage writes the page of the tweets.
+func (s *Server) writeTweetPage(w http.ResponseWriter, c *call, ids []string, defaultMax, min, max int) {
+	page, meta, ok := paginate(w, c, ids, defaultMax, min, max)
+	if !ok {
+		return
+	}
+
+	res := &response{Meta: meta}
+	data := []interface{}{}
+	for _, id := range page {
+		if t, ok := s.tweets[id]; ok {
+			data = append(data, s.tweetData(c, t, res))
+		}
+	}
+	if len(data) > 0 {
+		res.Data = data
+		res.Meta["newest_id"] = page[0]
+		res.Meta["oldest_id"] = page[len(page)-1]
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) tweetsLookup(w http.ResponseWriter, c *call) {
+	ids := splitComma(c.query.Get("ids"))
+	if len(ids) == 0 || len(ids) > 100 {
+		writeInvalidRequest(w, "ids", strings.Join(ids, ","))
+		return
+	}
+
+	res := &response{}
+	data := []interface{}{}
+	for _, id := range ids {
+		if t, ok := s.tweets[id]; ok {
+			data = append(data, s.tweetData(c, t, res))
+		} else {
+			res.Errors = append(res.Errors, notFound("tweet", "ids", id))
+		}
+	}
+	if len(data) > 0 {
+		res.Data = data
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) tweetsLookupID(w http.ResponseWriter, c *call) {
+	res := &response{}
+	if t, ok := s.tweets[c.param("id")]; ok {
+		res.Data = s.tweetData(c, t, res)
+	} else {
+		res.Errors = append(res.Errors, notFound("tweet", "id", c.param("id")))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) tweetsPost(w http.ResponseWriter, c *call) {
+	body := struct {
+		Text  string `json:"text"`
+		Reply *struct {
+			InReplyToTweetID string `json:"in_reply_to_tweet_id"`
+		} `json:"reply"`
+	}{}
+	if !decodeBody(w, c, &body) {
+		return
+	}
+	if body.Text == "" {
+		writeInvalidRequest(w, "text", body.Text)
+		return
+	}
+
+	t := resources.Tweet{Text: gotwi.String(body.Text), AuthorID: gotwi.String(c.auth.userID)}
+	if body.Reply != nil {
+		parent, ok := s.tweets[body.Reply.InReplyToTweetID]
+		if !ok {
+			writeInvalidRequest(w, "reply.in_reply_to_tweet_id", body.Reply.InReplyToTweetID)
+			return
+		}
+		t.ReferencedTweets = []resources.ReferencedTweet{{Type: referencedTweetType(resources.ReferencedTweetTypeRepliedTo), ID: parent.ID}}
+		t.InReplyToUserID = parent.AuthorID
+		t.ConversationId = parent.ConversationId
+	}
+
+	created := s.addTweet(t)
+	writeJSON(w, http.StatusCreated, &response{Data: map[string]interface{}{"id": created.ID, "text": created.Text}})
+}
+
+func referencedTweetType(t resources.ReferencedTweetType) *resources.ReferencedTweetType {
+	return &t
+}
+
+func (s *Server) tweetsDelete(w http.ResponseWriter, c *call) {
+	id := c.param("id")
+	t, ok := s.tweets[id]
+	if !ok {
+		writeJSON(w, http.StatusOK, &response{Data: map[string]interface{}{"deleted": false}})
+		return
+	}
+	if gotwi.StringValue(t.AuthorID) != c.auth.userID {
+		writeForbidden(w, "You are not allowed to delete a Tweet you do not own.")
+		return
+	}
+
+	delete(s.tweets, id)
+	s.removeRelationsOf(RelationLiking, id)
+	s.removeRelationsOf(RelationRetweeting, id)
+	writeJSON(w, http.StatusOK, &response{Data: map[string]interface{}{"deleted": true}})
+}
+
+func (s *Server) tweetsHide(w http.ResponseWriter, c *call) {
+	body := struct {
+		Hidden bool `json:"hidden"`
+	}{}
+	if !decodeBody(w, c, &body) {
+		return
+	}
+	t, ok := s.tweets[c.param("id")]
+	if !ok || t.RepliedToTweetID() == nil {
+		writeInvalidRequest(w, "id", c.param("id"))
+		return
+	}
+	// only the author of the conversation can hide the replies
+	root, ok := s.tweets[gotwi.StringValue(t.ConversationId)]
+	if !ok || gotwi.StringValue(root.AuthorID) != c.auth.userID {
+		writeForbidden(w, "You cannot hide replies to a conversation you did not start.")
+		return
+	}
+
+	t.hidden = body.Hidden
+	writeJSON(w, http.StatusOK, &response{Data: map[string]interface{}{"hidden": t.hidden}})
+}
+
+// tweetFilter is the filters of the tweets of the query.
+type tweetFilter struct {
+	sinceID   string
+	untilID   string
+	startTime *time.Time
+	endTime   *time.Time
+	exclude   map[string]bool
+}
+
+// newTweetFilter returns the filter of the query, and writes the error and returns false if it is invalid.
+func newTweetFilter(w http.ResponseWriter, c *call) (*tweetFilter, bool) {
+	f := &tweetFilter{
+		sinceID: c.query.Get("since_id"),
+		untilID: c.query.Get("until_id"),
+		exclude: map[string]bool{},
+	}
+	for _, name := range []string{"since_id", "until_id"} {
+		if v := c.query.Get(name); v != "" && !isID(v) {
+			writeInvalidRequest(w, name, v)
+			return nil, false
+		}
+	}
+	for name, p := range map[string]**time.Time{"start_time": &f.startTime, "end_time": &f.endTime} {
+		v := c.query.Get(name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeInvalidRequest(w, name, v)
+			return nil, false
+		}
+		*p = &t
+	}
+	for _, e := range splitComma(c.query.Get("exclude")) {
+		f.exclude[e] = true
+	}
+	return f, true
+}
+
+func (f *tweetFilter) match(t *tweet) bool {
+	if f.sinceID != "" && compareID(*t.ID, f.sinceID) <= 0 {
+		return false
+	}
+	if f.untilID != "" && compareID(*t.ID, f.untilID) >= 0 {
+		return false
+	}
+	if f.startTime != nil && t.CreatedAt.Before(*f.startTime) {
+		return false
+	}
+	if f.endTime != nil && !t.CreatedAt.Before(*f.endTime) {
+		return false
+	}
+	if f.exclude["retweets"] && t.IsRetweet() {
+		return false
+	}
+	if f.exclude["replies"] && t.IsReply() {
+		return false
+	}
+	return true
+}
+
+// compareID compares the numeric IDs.
+func compareID(a, b string) int {
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// filterTweets returns the IDs of the tweets matching the function, in the order of the newest first.
+func (s *Server) filterTweets(match func(t *tweet) bool) []string {
+	ids := []string{}
+	for id, t := range s.tweets {
+		if match(t) {
+			ids = append(ids, id)
+		}
+	}
+	sortNewest(ids)
+	return ids
+}
+
+// matchQuery reports whether the tweet matches the query of the search or the rule of the filtered stream.
+// The query supports the keywords, from:, to:, is:retweet and is:reply, which are ANDed, and - negates them.
+func (s *Server) matchQuery(query string, t *tweet) bool {
+	for _, term := range strings.Fields(query) {
+		negate := strings.HasPrefix(term, "-")
+		term = strings.TrimPrefix(term, "-")
+
+		var ok bool
+		switch {
+		case strings.HasPrefix(term, "from:"):
+			ok = s.isUser(t.AuthorID, strings.TrimPrefix(term, "from:"))
+		case strings.HasPrefix(term, "to:"):
+			ok = s.isUser(t.InReplyToUserID, strings.TrimPrefix(term, "to:"))
+		case term == "is:retweet":
+			ok = t.IsRetweet()
+		case term == "is:reply":
+			ok = t.IsReply()
+		default:
+			ok = strings.Contains(strings.ToLower(gotwi.StringValue(t.Text)), strings.ToLower(strings.Trim(term, `"`)))
+		}
+		if ok == negate {
+			return false
+		}
+	}
+	return true
+}
+
+// isUser reports whether the user of the ID has the username or the ID.
+func (s *Server) isUser(id *string, name string) bool {
+	if id == nil {
+		return false
+	}
+	if *id == name {
+		return true
+	}
+	u, ok := s.users[*id]
+	return ok && strings.EqualFold(gotwi.StringValue(u.Username), strings.TrimPrefix(name, "@"))
+}
+
+func (s *Server) tweetsSearch(w http.ResponseWriter, c *call) {
+	q := c.query.Get("query")
+	if strings.TrimSpace(q) == "" {
+		writeInvalidRequest(w, "query", q)
+		return
+	}
+	f, ok := newTweetFilter(w, c)
+	if !ok {
+		return
+	}
+
+	ids := s.filterTweets(func(t *tweet) bool { return f.match(t) && s.matchQuery(q, t) })
+	s.writeTweetPage(w, c, ids, 10, 10, 100)
+}
+
+// timeline serves the tweets of the user, or the tweets mentioning the user.
+func (s *Server) timeline(mentions bool) func(w http.ResponseWriter, c *call) {
+	return func(w http.ResponseWriter, c *call) {
+		id := c.param("id")
+		u, ok := s.users[id]
+		if !ok {
+			s.writeUser(w, c, nil, "id", id)
+			return
+		}
+		f, ok := newTweetFilter(w, c)
+		if !ok {
+			return
+		}
+
+		ids := s.filterTweets(func(t *tweet) bool {
+			if !f.match(t) {
+				return false
+			}
+			if mentions {
+				return gotwi.StringValue(t.InReplyToUserID) == id ||
+					strings.Contains(strings.ToLower(gotwi.StringValue(t.Text)), "@"+strings.ToLower(gotwi.StringValue(u.Username)))
+			}
+			return gotwi.StringValue(t.AuthorID) == id
+		})
+		s.writeTweetPage(w, c, ids, 10, 5, 100)
+	}
+}
+
+var granularities = map[string]time.Duration{"minute": time.Minute, "hour": time.Hour, "day": 24 * time.Hour}
+
+func (s *Server) tweetsCounts(w http.ResponseWriter, c *call) {
+	q := c.query.Get("query")
+	if strings.TrimSpace(q) == "" {
+		writeInvalidRequest(w, "query", q)
+		return
+	}
+	g := c.query.Get("granularity")
+	if g == "" {
+		g = "hour"
+	}
+	size, ok := granularities[g]
+	if !ok {
+		writeInvalidRequest(w, "granularity", g)
+		return
+	}
+	f, ok := newTweetFilter(w, c)
+	if !ok {
+		return
+	}
+
+	end := s.now().UTC()
+	if f.endTime != nil {
+		end = *f.endTime
+	}
+	start := end.Add(-7 * 24 * time.Hour)
+	if f.startTime != nil {
+		start = *f.startTime
+	}
+
+	data := []interface{}{}
+	total := 0
+	for from := start.Truncate(size); from.Before(end); from = from.Add(size) {
+		to := from.Add(size)
+		n := 0
+		for _, t := range s.tweets {
+			if f.match(t) && s.matchQuery(q, t) && !t.CreatedAt.Before(from) && t.CreatedAt.Before(to) &&
+				!t.CreatedAt.Before(start) && t.CreatedAt.Before(end) {
+				n++
+			}
+		}
+		total += n
+		data = append(data, map[string]interface{}{"start": from, "end": to, "tweet_count": n})
+	}
+	writeJSON(w, http.StatusOK, &response{Data: data, Meta: map[string]interface{}{"total_tweet_count": total}})
+}
+
+// tweetUsers serves the users related to the tweet, such as GET /2/tweets/:id/liking_users.
+func (s *Server) tweetUsers(r Relation) func(w http.ResponseWriter, c *call) {
+	return func(w http.ResponseWriter, c *call) {
+		id := c.param("id")
+		if _, ok := s.tweets[id]; !ok {
+			writeJSON(w, http.StatusOK, &response{Errors: []map[string]interface{}{notFound("tweet", "id", id)}})
+			return
+		}
+		s.writeUserPage(w, c, s.relatedTo(r, id), 100, 100)
+	}
+}
+
+// userTweets serves the tweets related from the user, such as GET /2/users/:id/liked_tweets.
+func (s *Server) userTweets(r Relation) func(w http.ResponseWriter, c *call) {
+	return func(w http.ResponseWriter, c *call) {
+		id := c.param("id")
+		if _, ok := s.users[id]; !ok {
+			s.writeUser(w, c, nil, "id", id)
+			return
+		}
+		s.writeTweetPage(w, c, s.related(r, id), 100, 10, 100)
+	}
+}
+
+// tweetRelationResult is the member of the data of the responses, such as "liked" of POST /2/users/:id/likes.
+var tweetRelationResult = map[Relation]string{RelationLiking: "liked", RelationRetweeting: "retweeted"}
+
+// tweetRelationPost serves such as POST /2/users/:id/likes.
+func (s *Server) tweetRelationPost(r Relation) func(w http.ResponseWriter, c *call) {
+	return func(w http.ResponseWriter, c *call) {
+		id := c.param("id")
+		if !requireUser(w, c, id) {
+			return
+		}
+		body := struct {
+			TweetID string `json:"tweet_id"`
+		}{}
+		if !decodeBody(w, c, &body) {
+			return
+		}
+		if _, ok := s.tweets[body.TweetID]; !ok {
+			writeInvalidRequest(w, "tweet_id", body.TweetID)
+			return
+		}
+
+		s.relate(r, id, body.TweetID)
+		writeJSON(w, http.StatusOK, &response{Data: map[string]interface{}{tweetRelationResult[r]: true}})
+	}
+}
+
+// tweetRelationDelete serves such as DELETE /2/users/:id/likes/:tweet_id.
+func (s *Server) tweetRelationDelete(r Relation) func(w http.ResponseWriter, c *call) {
+	return func(w http.ResponseWriter, c *call) {
+		id := c.param("id")
+		if !requireUser(w, c, id) {
+			return
+		}
+		tweetID := c.param("tweet_id")
+		if r == RelationRetweeting {
+			tweetID = c.param("source_tweet_id")
+		}
+
+		s.unrelate(r, id, tweetID)
+		writeJSON(w, http.StatusOK, &response{Data: map[string]interface{}{tweetRelationResult[r]: false}})
+	}
+}
@@ -0,0 +1,230 @@
+package gotwitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/resources"
+)
+
+var (
+	userDefaults  = []string{"id", "name", "username"}
+	tweetDefaults = []string{"id", "text"}
+	listDefaults  = []string{"id", "name"}
+	spaceDefaults = []string{"id", "state"}
+)
+
+func (c *call) hasExpansion(name string) bool {
+	for _, e := range splitComma(c.query.Get("expansions")) {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeBody decodes the JSON body, and writes 400 and returns false if it is invalid.
+func decodeBody(w http.ResponseWriter, c *call, v interface{}) bool {
+	if err := json.Unmarshal(c.body, v); err != nil {
+		writeProblem(w, http.StatusBadRequest, "Invalid Request", "The request body is not valid JSON.", "https://api.twitter.com/2/problems/invalid-request")
+		return false
+	}
+	return true
+}
+
+// requireUser writes 403 and returns false unless the ID is the authenticated user.
+func requireUser(w http.ResponseWriter, c *call, id string) bool {
+	if id != c.auth.userID {
+		writeForbidden(w, "You are not permitted to perform this action on behalf of another user.")
+		return false
+	}
+	return true
+}
+
+// userData returns the user with the fields of the query, and adds its expansions to the response.
+func (s *Server) userData(c *call, u *resources.User, res *response) map[string]interface{} {
+	if c.hasExpansion("pinned_tweet_id") && u.PinnedTweetID != nil {
+		if t, ok := s.tweets[*u.PinnedTweetID]; ok {
+			res.include("tweets", *t.ID, project(t.Tweet, tweetDefaults, c.query.Get("tweet.fields")))
+		}
+	}
+	return project(s.userObject(u), userDefaults, c.query.Get("user.fields"))
+}
+
+func (s *Server) includeUser(c *call, id *string, res *response) {
+	if id == nil {
+		return
+	}
+	if u, ok := s.users[*id]; ok {
+		res.include("users", *id, project(s.userObject(u), userDefaults, c.query.Get("user.fields")))
+	}
+}
+
+// writeUsers writes the users of the IDs, where not found users are the partial errors of the parameter.
+func (s *Server) writeUsers(w http.ResponseWriter, c *call, ids []string, find func(string) *resources.User, parameter string) {
+	if len(ids) == 0 || len(ids) > 100 {
+		writeInvalidRequest(w, parameter, strings.Join(ids, ","))
+		return
+	}
+
+	res := &response{}
+	data := []interface{}{}
+	for _, id := range ids {
+		if u := find(id); u != nil {
+			data = append(data, s.userData(c, u, res))
+		} else {
+			res.Errors = append(res.Errors, notFound("user", parameter, id))
+		}
+	}
+	if len(data) > 0 {
+		res.Data = data
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// writeUser writes the user, or the partial error of the parameter if not found.
+func (s *Server) writeUser(w http.ResponseWriter, c *call, u *resources.User, parameter, value string) {
+	res := &response{}
+	if u == nil {
+		res.Errors = append(res.Errors, notFound("user", parameter, value))
+	} else {
+		res.Data = s.userData(c, u, res)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) userByUsername(name string) *resources.User {
+	for _, u := range s.users {
+		if strings.EqualFold(gotwi.StringValue(u.Username), name) {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *Server) usersLookup(w http.ResponseWriter, c *call) {
+	s.writeUsers(w, c, splitComma(c.query.Get("ids")), func(id string) *resources.User { return s.users[id] }, "ids")
+}
+
+func (s *Server) usersLookupBy(w http.ResponseWriter, c *call) {
+	s.writeUsers(w, c, splitComma(c.query.Get("usernames")), s.userByUsername, "usernames")
+}
+
+func (s *Server) usersLookupID(w http.ResponseWriter, c *call) {
+	s.writeUser(w, c, s.users[c.param("id")], "id", c.param("id"))
+}
+
+func (s *Server) usersLookupByUsername(w http.ResponseWriter, c *call) {
+	s.writeUser(w, c, s.userByUsername(c.param("username")), "username", c.param("username"))
+}
+
+func (s *Server) usersMe(w http.ResponseWriter, c *call) {
+	s.writeUser(w, c, s.users[c.auth.userID], "id", c.auth.userID)
+}
+
+// writeUserPage writes the page of the users.
+func (s *Server) writeUserPage(w http.ResponseWriter, c *call, ids []string, defaultMax, max int) {
+	page, meta, ok := paginate(w, c, ids, defaultMax, 1, max)
+	if !ok {
+		return
+	}
+
+	res := &response{Meta: meta}
+	data := []interface{}{}
+	for _, id := range page {
+		if u, ok := s.users[id]; ok {
+			data = append(data, s.userData(c, u, res))
+		}
+	}
+	if len(data) > 0 {
+		res.Data = data
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// relationList serves the users related from the user, such as GET /2/users/:id/following.
+func (s *Server) relationList(r Relation) func(w http.ResponseWriter, c *call) {
+	return func(w http.ResponseWriter, c *call) {
+		id := c.param("id")
+		if r != RelationFollowing && !requireUser(w, c, id) {
+			return
+		}
+		if _, ok := s.users[id]; !ok {
+			s.writeUser(w, c, nil, "id", id)
+			return
+		}
+		s.writeUserPage(w, c, s.related(r, id), 100, 1000)
+	}
+}
+
+func (s *Server) followers(w http.ResponseWriter, c *call) {
+	id := c.param("id")
+	if _, ok := s.users[id]; !ok {
+		s.writeUser(w, c, nil, "id", id)
+		return
+	}
+	s.writeUserPage(w, c, s.relatedTo(RelationFollowing, id), 100, 1000)
+}
+
+// relationPost serves such as POST /2/users/:id/following.
+func (s *Server) relationPost(r Relation) func(w http.ResponseWriter, c *call) {
+	return func(w http.ResponseWriter, c *call) {
+		id := c.param("id")
+		if !requireUser(w, c, id) {
+			return
+		}
+		body := struct {
+			TargetUserID string `json:"target_user_id"`
+		}{}
+		if !decodeBody(w, c, &body) {
+			return
+		}
+		target, ok := s.users[body.TargetUserID]
+		if !ok || body.TargetUserID == id {
+			writeInvalidRequest(w, "target_user_id", body.TargetUserID)
+			return
+		}
+
+		data := map[string]interface{}{}
+		switch r {
+		case RelationFollowing:
+			// a protected user approves the follow request
+			pending := gotwi.BoolValue(target.Protected) && !s.hasRelation(r, id, body.TargetUserID)
+			if !pending {
+				s.relate(r, id, body.TargetUserID)
+			}
+			data["following"] = !pending
+			data["pending_follow"] = pending
+		case RelationBlocking:
+			s.relate(r, id, body.TargetUserID)
+			// blocking removes the follows of both directions
+			s.unrelate(RelationFollowing, id, body.TargetUserID)
+			s.unrelate(RelationFollowing, body.TargetUserID, id)
+			data["blocking"] = true
+		case RelationMuting:
+			s.relate(r, id, body.TargetUserID)
+			data["muting"] = true
+		}
+		writeJSON(w, http.StatusOK, &response{Data: data})
+	}
+}
+
+// relationDelete serves such as DELETE /2/users/:source_user_id/following/:target_user_id.
+func (s *Server) relationDelete(r Relation) func(w http.ResponseWriter, c *call) {
+	return func(w http.ResponseWriter, c *call) {
+		id := c.param("source_user_id")
+		if !requireUser(w, c, id) {
+			return
+		}
+		target := c.param("target_user_id")
+		if _, ok := s.users[target]; !ok {
+			writeInvalidRequest(w, "target_user_id", target)
+			return
+		}
+
+		s.unrelate(r, id, target)
+		writeJSON(w, http.StatusOK, &response{Data: map[string]interface{}{string(r): false}})
+	}
+}
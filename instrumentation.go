@@ -0,0 +1,160 @@
+package gotwi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/michimani/gotwi/internal/util"
+	"github.com/michimani/gotwi/resources"
+)
+
+// Instrumentation receives the calls of CallAPI and the stream connections of CallStreamAPI,
+// such as to create the spans and to record the metrics. The telemetry package is an adapter of it.
+// The methods are called concurrently by the concurrent calls.
+type Instrumentation interface {
+	// StartCall is called when a call starts, and the returned function is called with its result when it ends.
+	// The returned context is used for the call, so it can carry the span.
+	// For a stream, the call ends when the response header is received.
+	StartCall(ctx context.Context, call *CallInfo) (context.Context, func(*CallResult))
+	// StreamConnected is called when a stream is connected.
+	StreamConnected(call *CallInfo)
+	// StreamClosed is called when the body of a connected stream is closed, with the duration it was connected.
+	StreamClosed(call *CallInfo, uptime time.Duration)
+}
+
+// CallInfo is the endpoint of a call.
+type CallInfo struct {
+	Method string
+	// Endpoint is the path template of the endpoint, such as "/2/users/:id/following", not the resolved URL.
+	Endpoint string
+	Stream   bool
+}
+
+// Name returns the method and the endpoint template, such as "GET /2/users/:id/following",
+// which is the name of the span.
+func (ci *CallInfo) Name() string {
+	return ci.Method + " " + ci.Endpoint
+}
+
+// CallResult is the result of a call.
+type CallResult struct {
+	// StatusCode is the status code of the response. It is 0 if there is no response, such as for the cached responses.
+	StatusCode int
+	// ErrorCode is the Twitter error code of the error response, such as "88", or the problem type of API v2
+	// such as "https://api.twitter.com/2/problems/resource-not-found". It is empty if there is none.
+	ErrorCode string
+	// RateLimit is the rate limit state of the response, if the response has it.
+	RateLimit *util.RateLimitInformation
+	Cached    bool
+	Duration  time.Duration
+	Err       error
+}
+
+// EndpointTemplate returns the path of the endpoint constant, such as "/2/users/:id/following"
+// of users.FollowsFollowingGetEndpoint.
+func EndpointTemplate(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Path == "" {
+		return endpoint
+	}
+	return u.Path
+}
+
+type callResultKey struct{}
+
+// startCall starts the instrumentation of the call. The returned function must be called with the error of the call.
+func (c *GotwiClient) startCall(ctx context.Context, endpoint, method string, stream bool) (context.Context, *CallInfo, func(error)) {
+	if c.Instrumentation == nil {
+		return ctx, nil, func(error) {}
+	}
+
+	ci := &CallInfo{Method: method, Endpoint: EndpointTemplate(endpoint), Stream: stream}
+	r := &CallResult{}
+	start := time.Now()
+	ctx, done := c.Instrumentation.StartCall(ctx, ci)
+	ctx = context.WithValue(ctx, callResultKey{}, r)
+
+	return ctx, ci, func(err error) {
+		r.Duration = time.Since(start)
+		r.Err = err
+		non2xx := &resources.Non2XXError{}
+		if errors.As(err, &non2xx) {
+			if r.StatusCode == 0 {
+				r.StatusCode = IntValue(non2xx.StatusCode)
+			}
+			r.ErrorCode = errorCode(non2xx)
+		}
+		done(r)
+	}
+}
+
+// callResultFromContext returns the result of the instrumented call of the context, or nil.
+func callResultFromContext(ctx context.Context) *CallResult {
+	r, _ := ctx.Value(callResultKey{}).(*CallResult)
+	return r
+}
+
+// recordResponse sets the status and the rate limit state of the response to the instrumented call.
+func recordResponse(req *http.Request, res *http.Response) {
+	r := callResultFromContext(req.Context())
+	if r == nil || res == nil {
+		return
+	}
+	r.StatusCode = res.StatusCode
+	r.RateLimit = rateLimitInformation(res)
+}
+
+// rateLimitInformation returns the rate limit state of the response, or nil if the response does not have it.
+func rateLimitInformation(res *http.Response) *util.RateLimitInformation {
+	if len(util.HeaderValues(util.RATE_LIMIT_LIMIT_HEADER_KEY, res.Header)) == 0 {
+		return nil
+	}
+	rli, err := util.GetRateLimitInformation(res)
+	if err != nil {
+		return nil
+	}
+	return rli
+}
+
+func errorCode(e *resources.Non2XXError) string {
+	for _, ei := range e.Errors {
+		if ei.Code > 0 {
+			return strconv.Itoa(int(ei.Code))
+		}
+	}
+	if e.Type != nil && *e.Type != "about:blank" {
+		return *e.Type
+	}
+	return ""
+}
+
+// streamBody calls StreamClosed when the stream is closed.
+type streamBody struct {
+	io.ReadCloser
+	once   sync.Once
+	closed func()
+}
+
+func (b *streamBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.closed)
+	return err
+}
+
+// instrumentStream reports the connected stream and replaces its body with the one that reports the close.
+func (c *GotwiClient) instrumentStream(ci *CallInfo, res *http.Response) {
+	if c.Instrumentation == nil {
+		return
+	}
+	c.Instrumentation.StreamConnected(ci)
+	start := time.Now()
+	res.Body = &streamBody{ReadCloser: res.Body, closed: func() {
+		c.Instrumentation.StreamClosed(ci, time.Since(start))
+	}}
+}
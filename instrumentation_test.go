@@ -0,0 +1,77 @@
+package gotwi_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/users"
+	"github.com/michimani/gotwi/users/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type callRecord struct {
+	info   *gotwi.CallInfo
+	result *gotwi.CallResult
+}
+
+type recordingInstrumentation struct {
+	mu    sync.Mutex
+	calls []callRecord
+}
+
+func (ri *recordingInstrumentation) StartCall(ctx context.Context, call *gotwi.CallInfo) (context.Context, func(*gotwi.CallResult)) {
+	return ctx, func(r *gotwi.CallResult) {
+		ri.mu.Lock()
+		defer ri.mu.Unlock()
+		ri.calls = append(ri.calls, callRecord{info: call, result: r})
+	}
+}
+
+func (ri *recordingInstrumentation) StreamConnected(call *gotwi.CallInfo)               {}
+func (ri *recordingInstrumentation) StreamClosed(call *gotwi.CallInfo, d time.Duration) {}
+
+func Test_Instrumentation(t *testing.T) {
+	ri := &recordingInstrumentation{}
+	c := newMiddlewareClient(t, meHandler)
+	c.Instrumentation = ri
+	c.Cache = &gotwi.CacheConfig{Cache: gotwi.NewLRUCache(10), DefaultTTL: time.Minute}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := users.UserLookupMe(ctx, c, &types.UserLookupMeParams{})
+		require.NoError(t, err)
+	}
+
+	require.Len(t, ri.calls, 2)
+	for _, cr := range ri.calls {
+		assert.Equal(t, "GET /2/users/me", cr.info.Name())
+		assert.False(t, cr.info.Stream)
+	}
+	assert.Equal(t, 200, ri.calls[0].result.StatusCode)
+	assert.False(t, ri.calls[0].result.Cached)
+	assert.Greater(t, int64(ri.calls[0].result.Duration), int64(0))
+	assert.Equal(t, 0, ri.calls[1].result.StatusCode)
+	assert.True(t, ri.calls[1].result.Cached)
+}
+
+func Test_EndpointTemplate(t *testing.T) {
+	cases := []struct {
+		name     string
+		endpoint string
+		expect   string
+	}{
+		{name: "users", endpoint: users.FollowsFollowingGetEndpoint, expect: "/2/users/:id/following"},
+		{name: "two params", endpoint: users.FollowsFollowingDeleteEndpoint, expect: "/2/users/:source_user_id/following/:target_user_id"},
+		{name: "path", endpoint: "/2/users/me", expect: "/2/users/me"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			assert.Equal(tt, c.expect, gotwi.EndpointTemplate(c.endpoint))
+		})
+	}
+}
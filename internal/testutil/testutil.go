@@ -0,0 +1,75 @@
+// Package testutil provides the helpers shared by the tests of gotwi that send the requests to a local handler.
+// The tests of the behavior of the API itself should use gotwitest instead.
+package testutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/michimani/gotwi"
+)
+
+// RewriteTransport sends the requests for the Twitter API to Host over HTTP.
+type RewriteTransport struct {
+	Host string
+}
+
+func (rt RewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = "http"
+	r.URL.Host = rt.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// HTTPClient starts a server of the handler, and returns an http.Client that sends the requests to it.
+// The server is closed when the test ends.
+func HTTPClient(t *testing.T, h http.Handler) *http.Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	u, _ := url.Parse(srv.URL)
+	return &http.Client{Transport: RewriteTransport{Host: u.Host}}
+}
+
+// NewClient returns a client with the OAuth 1.0a user context, whose requests are sent to the handler
+// instead of the Twitter API. The API key and secret are set to the environment variables for the test.
+func NewClient(t *testing.T, h http.Handler) *gotwi.GotwiClient {
+	t.Helper()
+	t.Setenv(gotwi.APIKeyEnvName, "api-key")
+	t.Setenv(gotwi.APIKeySecretEnvName, "api-key-secret")
+
+	c, err := gotwi.NewGotwiClient(&gotwi.NewGotwiClientInput{
+		HTTPClient:           HTTPClient(t, h),
+		AuthenticationMethod: gotwi.AuthenMethodOAuth1UserContext,
+		OAuthToken:           "token",
+		OAuthTokenSecret:     "token-secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+// Logger records the messages of the debug logs. It is safe for concurrent use.
+type Logger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *Logger) Debug(msg string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, msg)
+}
+
+// Messages returns the recorded messages in the logged order.
+func (l *Logger) Messages() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string{}, l.messages...)
+}
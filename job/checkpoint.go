@@ -0,0 +1,159 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Checkpoint is the state of a job saved after each page.
+type Checkpoint struct {
+	// NextToken is the token of the next page. It is empty before the first page.
+	NextToken string `json:"next_token,omitempty"`
+	// StartTime and EndTime are the window of the job. They are kept as they were when the job started.
+	StartTime *time.Time `json:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+	Pages     int        `json:"pages"`
+	Done      bool       `json:"done"`
+	// Data is the state specific to the job, like the progress of the archive fetcher.
+	Data      json.RawMessage `json:"data,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// CheckpointStore persists checkpoints by the key of the job.
+type CheckpointStore interface {
+	// Load returns the checkpoint of the key, or nil if there is none.
+	Load(ctx context.Context, key string) (*Checkpoint, error)
+	Save(ctx context.Context, key string, cp *Checkpoint) error
+	Delete(ctx context.Context, key string) error
+}
+
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: map[string]Checkpoint{}}
+}
+
+func (s *MemoryCheckpointStore) Load(ctx context.Context, key string) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cp, ok := s.checkpoints[key]
+	if !ok {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+func (s *MemoryCheckpointStore) Save(ctx context.Context, key string, cp *Checkpoint) error {
+	if cp == nil {
+		return fmt.Errorf("Checkpoint is nil.")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkpoints[key] = *cp
+	return nil
+}
+
+func (s *MemoryCheckpointStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.checkpoints, key)
+	return nil
+}
+
+// FileCheckpointStore saves each checkpoint as a JSON file in the directory.
+// A checkpoint is written to a temporary file and renamed, so a crash does not leave a broken file.
+type FileCheckpointStore struct {
+	Dir string
+}
+
+func NewFileCheckpointStore(dir string) *FileCheckpointStore {
+	return &FileCheckpointStore{Dir: dir}
+}
+
+func (s *FileCheckpointStore) path(key string) string {
+	return filepath.Join(s.Dir, escapeFileName(key)+".json")
+}
+
+// escapeFileName encodes the key to a file name reversibly, so that different keys do not share a file.
+// The bytes other than the lower case letters, the digits, "_", "-" and "." are encoded as "%XX",
+// also the upper case letters for the case-insensitive file systems, and a leading "." not to hide the file.
+func escapeFileName(key string) string {
+	const hex = "0123456789ABCDEF"
+	b := make([]byte, 0, len(key))
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '_' || c == '-' || c == '.' && i > 0 {
+			b = append(b, c)
+			continue
+		}
+		b = append(b, '%', hex[c>>4], hex[c&15])
+	}
+	return string(b)
+}
+
+func (s *FileCheckpointStore) Load(ctx context.Context, key string) (*Checkpoint, error) {
+	b, err := ioutil.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(b, cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+func (s *FileCheckpointStore) Save(ctx context.Context, key string, cp *Checkpoint) error {
+	if cp == nil {
+		return fmt.Errorf("Checkpoint is nil.")
+	}
+
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(s.Dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), s.path(key))
+}
+
+func (s *FileCheckpointStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+// Package job runs paginated collections with checkpoints, so that they resume after a crash or a rate limit.
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/resources"
+)
+
+// DefaultRateLimitWait is used when a 429 response has no reset time.
+const DefaultRateLimitWait = time.Minute
+
+// PageFunc fetches the page of cp.NextToken in the window of cp, handles its output, and returns the next token.
+// An empty next token ends the job.
+type PageFunc func(ctx context.Context, cp *Checkpoint) (nextToken string, err error)
+
+type Job struct {
+	// Key identifies the checkpoint of the job in the Store.
+	Key   string
+	Store CheckpointStore
+	// StartTime and EndTime are the window of a new job. A resumed job uses the window in its checkpoint.
+	StartTime *time.Time
+	EndTime   *time.Time
+	Page      PageFunc
+	// MaxRateLimitWait is the longest sleep on a 429 response. If it is 0, the job sleeps until the rate limit is reset.
+	MaxRateLimitWait time.Duration
+	// Logger logs the retries on the 429 responses at the debug level, if it is not nil.
+	Logger gotwi.Logger
+}
+
+// Run runs the pages from the checkpoint of the job until the next token is empty.
+// The checkpoint is saved after each page is handled, so a resumed job starts from the page after the last saved one.
+// Only a page handled right before a crash, whose checkpoint is not saved yet, can be handled again.
+// On a 429 response, it sleeps until the rate limit is reset and retries the page.
+func (j *Job) Run(ctx context.Context) (*Checkpoint, error) {
+	if j.Key == "" || j.Store == nil || j.Page == nil {
+		return nil, fmt.Errorf("Key, Store and Page of Job are required.")
+	}
+
+	cp, err := j.Store.Load(ctx, j.Key)
+	if err != nil {
+		return nil, err
+	}
+	if cp == nil {
+		cp = &Checkpoint{StartTime: j.StartTime, EndTime: j.EndTime}
+	}
+
+	for !cp.Done {
+		next, err := j.Page(ctx, cp)
+		if wait, limited := RateLimitWait(err); limited {
+			if j.MaxRateLimitWait > 0 && wait > j.MaxRateLimitWait {
+				wait = j.MaxRateLimitWait
+			}
+			if j.Logger != nil {
+				j.Logger.Debug("gotwi: retry after rate limit", "key", j.Key, "pages", cp.Pages, "wait", wait)
+			}
+			if err := sleep(ctx, wait); err != nil {
+				return cp, err
+			}
+			continue
+		}
+		if err != nil {
+			return cp, err
+		}
+
+		cp.NextToken = next
+		cp.Pages++
+		cp.Done = next == ""
+		cp.UpdatedAt = time.Now()
+		if err := j.Store.Save(ctx, j.Key, cp); err != nil {
+			return cp, err
+		}
+	}
+
+	return cp, nil
+}
+
+// RateLimitWait returns how long to wait if the error is a 429 response.
+func RateLimitWait(err error) (time.Duration, bool) {
+	non2xx := &resources.Non2XXError{}
+	if err == nil || !errors.As(err, &non2xx) || gotwi.IntValue(non2xx.StatusCode) != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if non2xx.RateLimitInfo == nil || non2xx.RateLimitInfo.ResetAt == nil {
+		return DefaultRateLimitWait, true
+	}
+	wait := time.Until(*non2xx.RateLimitInfo.ResetAt)
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
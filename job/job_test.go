@@ -0,0 +1,235 @@
+package job_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/internal/testutil"
+	"github.com/michimani/gotwi/internal/util"
+	"github.com/michimani/gotwi/job"
+	"github.com/michimani/gotwi/resources"
+	"github.com/michimani/gotwi/users/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name  string
+		store job.CheckpointStore
+	}{
+		{name: "memory", store: job.NewMemoryCheckpointStore()},
+		{name: "file", store: job.NewFileCheckpointStore(t.TempDir())},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			cp, err := c.store.Load(ctx, "job/1")
+			assert.NoError(tt, err)
+			assert.Nil(tt, cp)
+
+			start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			saved := &job.Checkpoint{NextToken: "token", StartTime: &start, Pages: 2, Data: []byte(`{"a":1}`)}
+			assert.NoError(tt, c.store.Save(ctx, "job/1", saved))
+
+			cp, err = c.store.Load(ctx, "job/1")
+			assert.NoError(tt, err)
+			if assert.NotNil(tt, cp) {
+				assert.Equal(tt, "token", cp.NextToken)
+				assert.Equal(tt, start, *cp.StartTime)
+				assert.Equal(tt, 2, cp.Pages)
+				assert.JSONEq(tt, `{"a":1}`, string(cp.Data))
+			}
+
+			assert.NoError(tt, c.store.Delete(ctx, "job/1"))
+			assert.NoError(tt, c.store.Delete(ctx, "job/1"))
+			cp, err = c.store.Load(ctx, "job/1")
+			assert.NoError(tt, err)
+			assert.Nil(tt, cp)
+		})
+	}
+}
+
+func Test_Job_Run(t *testing.T) {
+	pages := map[string]string{"": "a", "a": "b", "b": ""}
+	cases := []struct {
+		name         string
+		saved        *job.Checkpoint
+		failAt       string
+		expectTokens []string
+		expectPages  int
+		wantErr      bool
+	}{
+		{
+			name:         "ok",
+			expectTokens: []string{"", "a", "b"},
+			expectPages:  3,
+		},
+		{
+			name:         "ok: resume",
+			saved:        &job.Checkpoint{NextToken: "a", Pages: 1},
+			expectTokens: []string{"a", "b"},
+			expectPages:  3,
+		},
+		{
+			name:         "ok: already done",
+			saved:        &job.Checkpoint{Pages: 3, Done: true},
+			expectTokens: []string{},
+			expectPages:  3,
+		},
+		{
+			name:         "ng: failed page is not checkpointed",
+			failAt:       "b",
+			expectTokens: []string{"", "a", "b"},
+			expectPages:  2,
+			wantErr:      true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			ctx := context.Background()
+			store := job.NewMemoryCheckpointStore()
+			if c.saved != nil {
+				store.Save(ctx, "key", c.saved)
+			}
+
+			tokens := []string{}
+			j := &job.Job{
+				Key:   "key",
+				Store: store,
+				Page: func(ctx context.Context, cp *job.Checkpoint) (string, error) {
+					tokens = append(tokens, cp.NextToken)
+					if cp.NextToken == c.failAt && c.failAt != "" {
+						return "", errors.New("failed")
+					}
+					return pages[cp.NextToken], nil
+				},
+			}
+
+			_, err := j.Run(ctx)
+			assert.Equal(tt, c.wantErr, err != nil)
+			assert.Equal(tt, c.expectTokens, tokens)
+
+			cp, _ := store.Load(ctx, "key")
+			if assert.NotNil(tt, cp) {
+				assert.Equal(tt, c.expectPages, cp.Pages)
+				assert.Equal(tt, !c.wantErr, cp.Done)
+			}
+		})
+	}
+}
+
+func Test_Job_Run_rateLimit(t *testing.T) {
+	requests := 0
+	c := testutil.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if requests == 1 {
+			w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(time.Now().Unix(), 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"title":"Too Many Requests","type":"about:blank","status":429}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data":[{"id":"%d","name":"n","username":"u"}],"meta":{"result_count":1}}`, requests)
+	}))
+
+	users := []string{}
+	store := job.NewMemoryCheckpointStore()
+	l := &messageLogger{}
+	j := &job.Job{
+		Key:   "followers",
+		Store: store,
+		Page: job.FollowsFollowersPages(c, &types.FollowsFollowersParams{ID: "1"}, func(res *types.FollowsFollowersResponse) error {
+			for _, u := range res.Data {
+				users = append(users, gotwi.StringValue(u.ID))
+			}
+			return nil
+		}),
+		MaxRateLimitWait: 10 * time.Millisecond,
+		Logger:           l,
+	}
+
+	cp, err := j.Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2"}, users)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 1, cp.Pages)
+	assert.Equal(t, []string{"gotwi: retry after rate limit"}, l.messages)
+}
+
+type messageLogger struct {
+	messages []string
+}
+
+func (l *messageLogger) Debug(msg string, args ...interface{}) {
+	l.messages = append(l.messages, msg)
+}
+
+func Test_RateLimitWait(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	cases := []struct {
+		name          string
+		err           error
+		expectLimited bool
+		expectMin     time.Duration
+	}{
+		{name: "nil", err: nil},
+		{name: "other error", err: errors.New("error")},
+		{name: "403", err: &resources.Non2XXError{StatusCode: gotwi.Int(403)}},
+		{name: "429 without reset", err: &resources.Non2XXError{StatusCode: gotwi.Int(429)}, expectLimited: true, expectMin: job.DefaultRateLimitWait},
+		{
+			name:          "wrapped 429",
+			err:           fmt.Errorf("wrapped: %w", &resources.Non2XXError{StatusCode: gotwi.Int(429), RateLimitInfo: rateLimitInfo(reset)}),
+			expectLimited: true,
+			expectMin:     59 * time.Minute,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			wait, limited := job.RateLimitWait(c.err)
+			assert.Equal(tt, c.expectLimited, limited)
+			assert.GreaterOrEqual(tt, wait, c.expectMin)
+		})
+	}
+}
+
+func rateLimitInfo(reset time.Time) *util.RateLimitInformation {
+	return &util.RateLimitInformation{Limit: 300, ResetAt: &reset}
+}
+
+func Test_FileCheckpointStore_keys(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := job.NewFileCheckpointStore(dir)
+
+	keys := []string{"a/b", "a_b", "a:b", "a%3Ab", "A_b", "a.b", ".ab", "日本"}
+	for i, key := range keys {
+		assert.NoError(t, store.Save(ctx, key, &job.Checkpoint{NextToken: key, Pages: i}))
+	}
+
+	for i, key := range keys {
+		cp, err := store.Load(ctx, key)
+		assert.NoError(t, err)
+		if assert.NotNil(t, cp, key) {
+			assert.Equal(t, key, cp.NextToken)
+			assert.Equal(t, i, cp.Pages)
+		}
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, len(keys))
+	for _, f := range files {
+		assert.False(t, strings.HasPrefix(f.Name(), "."), f.Name())
+	}
+}
@@ -0,0 +1,66 @@
+package job
+
+import (
+	"context"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/lists"
+	listtypes "github.com/michimani/gotwi/lists/types"
+	"github.com/michimani/gotwi/tweets"
+	tweettypes "github.com/michimani/gotwi/tweets/types"
+	"github.com/michimani/gotwi/users"
+	usertypes "github.com/michimani/gotwi/users/types"
+)
+
+// SearchTweetsAllPages returns a PageFunc that calls SearchTweetsAll with the window and the token of the checkpoint.
+func SearchTweetsAllPages(c *gotwi.GotwiClient, p *tweettypes.SearchTweetsAllParams, handle func(*tweettypes.SearchTweetsAllResponse) error) PageFunc {
+	return func(ctx context.Context, cp *Checkpoint) (string, error) {
+		params := *p
+		params.StartTime = cp.StartTime
+		params.EndTime = cp.EndTime
+		params.NextToken = cp.NextToken
+
+		res, err := tweets.SearchTweetsAll(ctx, c, &params)
+		if err != nil {
+			return "", err
+		}
+		if err := handle(res); err != nil {
+			return "", err
+		}
+		return gotwi.StringValue(res.Meta.NextToken), nil
+	}
+}
+
+// FollowsFollowersPages returns a PageFunc that calls FollowsFollowers with the token of the checkpoint.
+func FollowsFollowersPages(c *gotwi.GotwiClient, p *usertypes.FollowsFollowersParams, handle func(*usertypes.FollowsFollowersResponse) error) PageFunc {
+	return func(ctx context.Context, cp *Checkpoint) (string, error) {
+		params := *p
+		params.PaginationToken = cp.NextToken
+
+		res, err := users.FollowsFollowers(ctx, c, &params)
+		if err != nil {
+			return "", err
+		}
+		if err := handle(res); err != nil {
+			return "", err
+		}
+		return gotwi.StringValue(res.Meta.NextToken), nil
+	}
+}
+
+// ListMembersGetPages returns a PageFunc that calls ListMembersGet with the token of the checkpoint.
+func ListMembersGetPages(c *gotwi.GotwiClient, p *listtypes.ListMembersGetParams, handle func(*listtypes.ListMembersGetResponse) error) PageFunc {
+	return func(ctx context.Context, cp *Checkpoint) (string, error) {
+		params := *p
+		params.PaginationToken = cp.NextToken
+
+		res, err := lists.ListMembersGet(ctx, c, &params)
+		if err != nil {
+			return "", err
+		}
+		if err := handle(res); err != nil {
+			return "", err
+		}
+		return gotwi.StringValue(res.Meta.NextToken), nil
+	}
+}
@@ -3,12 +3,10 @@ package types
 import "github.com/michimani/gotwi/resources"
 
 type ListFollowsFollowersResponse struct {
-	Data     []resources.User `json:"data"`
-	Includes struct {
-		Tweets []resources.Tweet `json:"tweets"`
-	} `json:"includes"`
-	Meta   resources.ListFollowsFollowersMeta `json:"meta"`
-	Errors []resources.PartialError           `json:"errors"`
+	Data     []resources.User                   `json:"data"`
+	Includes resources.Includes                 `json:"includes"`
+	Meta     resources.ListFollowsFollowersMeta `json:"meta"`
+	Errors   []resources.PartialError           `json:"errors"`
 }
 
 func (r *ListFollowsFollowersResponse) HasPartialError() bool {
@@ -16,12 +14,10 @@ func (r *ListFollowsFollowersResponse) HasPartialError() bool {
 }
 
 type ListFollowsFollowedListsResponse struct {
-	Data     []resources.List `json:"data"`
-	Includes struct {
-		Users []resources.User `json:"users"`
-	} `json:"includes"`
-	Meta   resources.ListFollowsFollowedListsMeta `json:"meta"`
-	Errors []resources.PartialError               `json:"errors"`
+	Data     []resources.List                       `json:"data"`
+	Includes resources.Includes                     `json:"includes"`
+	Meta     resources.ListFollowsFollowedListsMeta `json:"meta"`
+	Errors   []resources.PartialError               `json:"errors"`
 }
 
 func (r *ListFollowsFollowedListsResponse) HasPartialError() bool {
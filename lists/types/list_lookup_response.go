@@ -3,11 +3,9 @@ package types
 import "github.com/michimani/gotwi/resources"
 
 type ListLookupIDResponse struct {
-	Data     resources.List `json:"data"`
-	Includes struct {
-		Users []resources.User `json:"users"`
-	} `json:"includes,omitempty"`
-	Errors []resources.PartialError `json:"errors,omitempty"`
+	Data     resources.List           `json:"data"`
+	Includes resources.Includes       `json:"includes,omitempty"`
+	Errors   []resources.PartialError `json:"errors,omitempty"`
 }
 
 func (r *ListLookupIDResponse) HasPartialError() bool {
@@ -15,12 +13,10 @@ func (r *ListLookupIDResponse) HasPartialError() bool {
 }
 
 type ListLookupOwnedListsResponse struct {
-	Data     []resources.List `json:"data"`
-	Includes struct {
-		Users []resources.User `json:"users"`
-	} `json:"includes,omitempty"`
-	Meta   resources.ListLookupOwnedListsMeta
-	Errors []resources.PartialError `json:"errors,omitempty"`
+	Data     []resources.List   `json:"data"`
+	Includes resources.Includes `json:"includes,omitempty"`
+	Meta     resources.ListLookupOwnedListsMeta
+	Errors   []resources.PartialError `json:"errors,omitempty"`
 }
 
 func (r *ListLookupOwnedListsResponse) HasPartialError() bool {
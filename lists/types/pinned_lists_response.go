@@ -3,10 +3,8 @@ package types
 import "github.com/michimani/gotwi/resources"
 
 type PinnedListsGetResponse struct {
-	Data     []resources.List `json:"data"`
-	Includes struct {
-		Users []resources.User `json:"users"`
-	} `json:"includes"`
+	Data     []resources.List   `json:"data"`
+	Includes resources.Includes `json:"includes"`
 }
 
 func (r *PinnedListsGetResponse) HasPartialError() bool {
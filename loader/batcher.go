@@ -0,0 +1,211 @@
+// Package loader combines concurrent single ID lookups into multi ID lookups in the dataloader style.
+package loader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/michimani/gotwi/resources"
+)
+
+const (
+	// MaxBatchSize is the maximum number of IDs of the multi ID lookup endpoints.
+	MaxBatchSize = 100
+
+	DefaultWait = 10 * time.Millisecond
+)
+
+type Options struct {
+	// Wait is the window to collect the IDs of a batch. Default is DefaultWait.
+	Wait time.Duration
+	// MaxBatch is the maximum number of IDs of a batch. Default and upper limit is MaxBatchSize.
+	MaxBatch int
+}
+
+// batchResult is the result of a multi ID lookup. Values are keyed by ID.
+type batchResult struct {
+	values   map[string]interface{}
+	includes resources.Includes
+	errors   []resources.PartialError
+}
+
+type batchFunc func(ctx context.Context, ids []string) (*batchResult, error)
+
+// call is a lookup of an ID that may be shared by the callers of the same ID.
+type call struct {
+	id       string
+	done     chan struct{}
+	value    interface{}
+	includes resources.Includes
+	errors   []resources.PartialError
+	err      error
+	batch    *batch
+}
+
+// batch is the IDs of a multi ID lookup. Its lookup runs with its own context, which is canceled
+// when no caller waits for it, so that a caller that stops waiting does not fail the other callers.
+// The context has the values of the first caller, such as the span of the tracing, but not its deadline.
+type batch struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	waiters int
+	calls   []*call
+	timer   *time.Timer
+}
+
+type batcher struct {
+	wait     time.Duration
+	maxBatch int
+	fetch    batchFunc
+
+	mu       sync.Mutex
+	pending  *batch
+	inflight map[string]*call
+}
+
+func newBatcher(fetch batchFunc, opts *Options) *batcher {
+	b := &batcher{
+		wait:     DefaultWait,
+		maxBatch: MaxBatchSize,
+		fetch:    fetch,
+		inflight: map[string]*call{},
+	}
+
+	if opts != nil {
+		if opts.Wait > 0 {
+			b.wait = opts.Wait
+		}
+		if opts.MaxBatch > 0 && opts.MaxBatch < MaxBatchSize {
+			b.maxBatch = opts.MaxBatch
+		}
+	}
+
+	return b
+}
+
+// load waits for the result of the ID. A caller stops waiting when its own context is done,
+// and the lookup of the batch is canceled only when all the callers of the batch stop waiting.
+func (b *batcher) load(ctx context.Context, id string) (*call, error) {
+	b.mu.Lock()
+	cl, ok := b.inflight[id]
+	if !ok {
+		cl = &call{id: id, done: make(chan struct{})}
+		b.inflight[id] = cl
+		b.enqueue(ctx, cl)
+	}
+	cl.batch.waiters++
+	b.mu.Unlock()
+
+	select {
+	case <-cl.done:
+		if cl.err != nil {
+			return nil, cl.err
+		}
+		return cl, nil
+	case <-ctx.Done():
+		b.leave(cl.batch)
+		return nil, ctx.Err()
+	}
+}
+
+// leave is called when a caller stops waiting. If no caller waits for the batch, the batch is canceled
+// and its IDs are removed from inflight, so that the next callers of them start a new batch.
+func (b *batcher) leave(bt *batch) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bt.waiters--
+	if bt.waiters > 0 {
+		return
+	}
+	for _, cl := range bt.calls {
+		if b.inflight[cl.id] == cl {
+			delete(b.inflight, cl.id)
+		}
+	}
+	if b.pending == bt {
+		b.pending = nil
+		bt.timer.Stop()
+	}
+	bt.cancel()
+}
+
+// enqueue must be called with b.mu held.
+func (b *batcher) enqueue(ctx context.Context, cl *call) {
+	if b.pending == nil {
+		ctx, cancel := context.WithCancel(valuesContext{ctx})
+		bt := &batch{ctx: ctx, cancel: cancel}
+		bt.timer = time.AfterFunc(b.wait, func() {
+			b.mu.Lock()
+			if b.pending != bt {
+				// already dispatched because it was full
+				b.mu.Unlock()
+				return
+			}
+			b.pending = nil
+			b.mu.Unlock()
+
+			b.dispatch(bt)
+		})
+		b.pending = bt
+	}
+
+	cl.batch = b.pending
+	b.pending.calls = append(b.pending.calls, cl)
+	if len(b.pending.calls) >= b.maxBatch {
+		bt := b.pending
+		b.pending = nil
+		bt.timer.Stop()
+		go b.dispatch(bt)
+	}
+}
+
+func (b *batcher) dispatch(bt *batch) {
+	ids := make([]string, 0, len(bt.calls))
+	for _, cl := range bt.calls {
+		ids = append(ids, cl.id)
+	}
+
+	res, err := b.fetch(bt.ctx, ids)
+	bt.cancel()
+
+	b.mu.Lock()
+	for _, cl := range bt.calls {
+		if b.inflight[cl.id] == cl {
+			delete(b.inflight, cl.id)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, cl := range bt.calls {
+		if err != nil {
+			cl.err = err
+		} else {
+			cl.value = res.values[cl.id]
+			cl.includes = res.includes
+			cl.errors = partialErrorsOf(res.errors, cl.id)
+		}
+		close(cl.done)
+	}
+}
+
+// valuesContext has the values of the parent, without its deadline and cancellation.
+type valuesContext struct {
+	context.Context
+}
+
+func (valuesContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (valuesContext) Done() <-chan struct{}       { return nil }
+func (valuesContext) Err() error                  { return nil }
+
+// partialErrorsOf returns the partial errors of the ID.
+func partialErrorsOf(errs []resources.PartialError, id string) []resources.PartialError {
+	var pes []resources.PartialError
+	for _, e := range errs {
+		if (e.ResourceId != nil && *e.ResourceId == id) || (e.Value != nil && *e.Value == id) {
+			pes = append(pes, e)
+		}
+	}
+	return pes
+}
@@ -0,0 +1,154 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/resources"
+	"github.com/michimani/gotwi/spaces"
+	spacestypes "github.com/michimani/gotwi/spaces/types"
+	"github.com/michimani/gotwi/tweets"
+	tweetstypes "github.com/michimani/gotwi/tweets/types"
+	"github.com/michimani/gotwi/users"
+	userstypes "github.com/michimani/gotwi/users/types"
+)
+
+// UserLoader loads users by ID with users.UserLookup.
+type UserLoader struct {
+	b *batcher
+}
+
+// NewUserLoader returns UserLoader. The fields and the expansions of p are used for all batches, and p.IDs is ignored.
+func NewUserLoader(c *gotwi.GotwiClient, p *userstypes.UserLookupParams, opts *Options) *UserLoader {
+	if p == nil {
+		p = &userstypes.UserLookupParams{}
+	}
+
+	return &UserLoader{b: newBatcher(func(ctx context.Context, ids []string) (*batchResult, error) {
+		q := *p
+		q.IDs = ids
+		res, err := users.UserLookup(ctx, c, &q)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &batchResult{values: map[string]interface{}{}, includes: res.Includes, errors: res.Errors}
+		for _, u := range res.Data {
+			r.values[gotwi.StringValue(u.ID)] = u
+		}
+		return r, nil
+	}, opts)}
+}
+
+// Load returns the user of the ID in the same shape as users.UserLookupID.
+// Includes is of the whole batch, and Errors is the partial errors of the ID.
+func (l *UserLoader) Load(ctx context.Context, id string) (*userstypes.UserLookupIDResponse, error) {
+	cl, err := l.b.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &userstypes.UserLookupIDResponse{Includes: cl.includes, Errors: cl.errors}
+	if u, ok := cl.value.(resources.User); ok {
+		res.Data = u
+	} else if len(res.Errors) == 0 {
+		return nil, notFoundError(id)
+	}
+
+	return res, nil
+}
+
+// TweetLoader loads tweets by ID with tweets.TweetLookup.
+type TweetLoader struct {
+	b *batcher
+}
+
+// NewTweetLoader returns TweetLoader. The fields and the expansions of p are used for all batches, and p.IDs is ignored.
+func NewTweetLoader(c *gotwi.GotwiClient, p *tweetstypes.TweetLookupParams, opts *Options) *TweetLoader {
+	if p == nil {
+		p = &tweetstypes.TweetLookupParams{}
+	}
+
+	return &TweetLoader{b: newBatcher(func(ctx context.Context, ids []string) (*batchResult, error) {
+		q := *p
+		q.IDs = ids
+		res, err := tweets.TweetLookup(ctx, c, &q)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &batchResult{values: map[string]interface{}{}, includes: res.Includes, errors: res.Errors}
+		for _, t := range res.Data {
+			r.values[gotwi.StringValue(t.ID)] = t
+		}
+		return r, nil
+	}, opts)}
+}
+
+// Load returns the tweet of the ID in the same shape as tweets.TweetLookupID.
+// Includes is of the whole batch, and Errors is the partial errors of the ID.
+func (l *TweetLoader) Load(ctx context.Context, id string) (*tweetstypes.TweetLookupIDResponse, error) {
+	cl, err := l.b.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &tweetstypes.TweetLookupIDResponse{Includes: cl.includes, Errors: cl.errors}
+	if t, ok := cl.value.(resources.Tweet); ok {
+		res.Data = t
+	} else if len(res.Errors) == 0 {
+		return nil, notFoundError(id)
+	}
+
+	return res, nil
+}
+
+// SpaceLoader loads spaces by ID with spaces.SpacesLookup.
+type SpaceLoader struct {
+	b *batcher
+}
+
+// NewSpaceLoader returns SpaceLoader. The fields and the expansions of p are used for all batches, and p.IDs is ignored.
+func NewSpaceLoader(c *gotwi.GotwiClient, p *spacestypes.SpacesLookupParams, opts *Options) *SpaceLoader {
+	if p == nil {
+		p = &spacestypes.SpacesLookupParams{}
+	}
+
+	return &SpaceLoader{b: newBatcher(func(ctx context.Context, ids []string) (*batchResult, error) {
+		q := *p
+		q.IDs = ids
+		res, err := spaces.SpacesLookup(ctx, c, &q)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &batchResult{values: map[string]interface{}{}, includes: res.Includes, errors: res.Errors}
+		for _, s := range res.Data {
+			r.values[gotwi.StringValue(s.ID)] = s
+		}
+		return r, nil
+	}, opts)}
+}
+
+// Load returns the space of the ID in the same shape as spaces.SpacesLookupID.
+// Includes is of the whole batch, and Errors is the partial errors of the ID.
+func (l *SpaceLoader) Load(ctx context.Context, id string) (*spacestypes.SpacesLookupIDResponse, error) {
+	cl, err := l.b.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &spacestypes.SpacesLookupIDResponse{Includes: cl.includes, Errors: cl.errors}
+	if s, ok := cl.value.(resources.Space); ok {
+		res.Data = s
+	} else if len(res.Errors) == 0 {
+		return nil, notFoundError(id)
+	}
+
+	return res, nil
+}
+
+func notFoundError(id string) error {
+	return fmt.Errorf("ID '%s' is not found in the response.", id)
+}
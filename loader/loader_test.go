@@ -0,0 +1,268 @@
+package loader_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/internal/testutil"
+	"github.com/michimani/gotwi/loader"
+	"github.com/michimani/gotwi/resources"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_UserLoader_Load(t *testing.T) {
+	var mu sync.Mutex
+	batches := [][]string{}
+	c := testutil.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		sort.Strings(ids)
+		mu.Lock()
+		batches = append(batches, ids)
+		mu.Unlock()
+
+		data := []string{}
+		errs := []string{}
+		for _, id := range ids {
+			if id == "404" {
+				errs = append(errs, fmt.Sprintf(`{"value":"%s","detail":"Could not find user with ids: [%s].","title":"Not Found Error","resource_type":"user","parameter":"ids","resource_id":"%s"}`, id, id, id))
+				continue
+			}
+			data = append(data, fmt.Sprintf(`{"id":"%s","name":"user%s","username":"user%s"}`, id, id, id))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":[%s],"errors":[%s]}`, strings.Join(data, ","), strings.Join(errs, ","))
+	}))
+
+	l := loader.NewUserLoader(c, nil, &loader.Options{Wait: 20 * time.Millisecond})
+
+	ids := []string{"1", "2", "2", "3", "404"}
+	results := make([]*struct {
+		name string
+		errs []resources.PartialError
+		err  error
+	}, len(ids))
+	wg := sync.WaitGroup{}
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			res, err := l.Load(context.Background(), id)
+			r := &struct {
+				name string
+				errs []resources.PartialError
+				err  error
+			}{err: err}
+			if res != nil {
+				r.name = gotwi.StringValue(res.Data.Name)
+				r.errs = res.Errors
+			}
+			results[i] = r
+		}(i, id)
+	}
+	wg.Wait()
+
+	assert.Equal(t, [][]string{{"1", "2", "3", "404"}}, batches)
+	for i, id := range ids {
+		assert.NoError(t, results[i].err)
+		if id == "404" {
+			assert.Equal(t, "", results[i].name)
+			assert.Len(t, results[i].errs, 1)
+			assert.Equal(t, "404", gotwi.StringValue(results[i].errs[0].ResourceId))
+			continue
+		}
+		assert.Equal(t, "user"+id, results[i].name)
+		assert.Empty(t, results[i].errs)
+	}
+}
+
+func Test_TweetLoader_Load_MaxBatch(t *testing.T) {
+	var mu sync.Mutex
+	sizes := []int{}
+	c := testutil.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		mu.Lock()
+		sizes = append(sizes, len(ids))
+		mu.Unlock()
+
+		data := []string{}
+		for _, id := range ids {
+			data = append(data, fmt.Sprintf(`{"id":"%s","text":"tweet%s"}`, id, id))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+	}))
+
+	l := loader.NewTweetLoader(c, nil, &loader.Options{Wait: time.Second, MaxBatch: 2})
+
+	wg := sync.WaitGroup{}
+	for _, id := range []string{"1", "2", "3", "4"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			res, err := l.Load(context.Background(), id)
+			assert.NoError(t, err)
+			assert.Equal(t, "tweet"+id, gotwi.StringValue(res.Data.Text))
+		}(id)
+	}
+	wg.Wait()
+
+	// full batches are dispatched without waiting for the window
+	assert.Equal(t, []int{2, 2}, sizes)
+}
+
+func Test_SpaceLoader_Load_Error(t *testing.T) {
+	c := testutil.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"title":"Service Unavailable"}`)
+	}))
+
+	l := loader.NewSpaceLoader(c, nil, nil)
+
+	wg := sync.WaitGroup{}
+	for _, id := range []string{"a", "b"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			res, err := l.Load(context.Background(), id)
+			assert.Nil(t, res)
+			var non2xx *resources.Non2XXError
+			assert.True(t, errors.As(err, &non2xx))
+		}(id)
+	}
+	wg.Wait()
+}
+
+func Test_UserLoader_Load_Cancel(t *testing.T) {
+	arrived := make(chan struct{}, 1)
+	release := make(chan struct{})
+	requestDone := make(chan error, 1)
+	c := testutil.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		if ids[0] == "3" {
+			// never released
+			<-r.Context().Done()
+			requestDone <- r.Context().Err()
+			return
+		}
+		<-release
+
+		data := []string{}
+		for _, id := range ids {
+			data = append(data, fmt.Sprintf(`{"id":"%s","name":"user%s","username":"user%s"}`, id, id, id))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+	}))
+
+	t.Run("the other caller gets its result", func(tt *testing.T) {
+		l := loader.NewUserLoader(c, nil, &loader.Options{Wait: 50 * time.Millisecond})
+
+		// the first caller of the batch stops waiting
+		firstCtx, cancel := context.WithCancel(context.Background())
+		firstErr := make(chan error, 1)
+		go func() {
+			_, err := l.Load(firstCtx, "1")
+			firstErr <- err
+		}()
+		time.Sleep(5 * time.Millisecond)
+
+		second := make(chan string, 1)
+		go func() {
+			res, err := l.Load(context.Background(), "2")
+			assert.NoError(tt, err)
+			if res != nil {
+				second <- gotwi.StringValue(res.Data.Name)
+			}
+			close(second)
+		}()
+
+		<-arrived
+		cancel()
+		assert.ErrorIs(tt, <-firstErr, context.Canceled)
+		close(release)
+		assert.Equal(tt, "user2", <-second)
+	})
+
+	t.Run("the lookup is canceled when no caller waits", func(tt *testing.T) {
+		l := loader.NewUserLoader(c, nil, &loader.Options{Wait: time.Millisecond})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errc := make(chan error, 1)
+		go func() {
+			_, err := l.Load(ctx, "3")
+			errc <- err
+		}()
+
+		<-arrived
+		cancel()
+		assert.ErrorIs(tt, <-errc, context.Canceled)
+		select {
+		case err := <-requestDone:
+			assert.Error(tt, err)
+		case <-time.After(time.Second):
+			tt.Error("the request is not canceled")
+		}
+	})
+}
+
+type ctxKey struct{}
+
+// ctxInstrumentation records the context values and deadlines of the calls.
+type ctxInstrumentation struct {
+	mu        sync.Mutex
+	values    []interface{}
+	deadlines []bool
+}
+
+func (in *ctxInstrumentation) StartCall(ctx context.Context, call *gotwi.CallInfo) (context.Context, func(*gotwi.CallResult)) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	_, ok := ctx.Deadline()
+	in.values = append(in.values, ctx.Value(ctxKey{}))
+	in.deadlines = append(in.deadlines, ok)
+	return ctx, func(*gotwi.CallResult) {}
+}
+
+func (in *ctxInstrumentation) StreamConnected(call *gotwi.CallInfo)                    {}
+func (in *ctxInstrumentation) StreamClosed(call *gotwi.CallInfo, uptime time.Duration) {}
+
+func Test_UserLoader_Load_ContextValues(t *testing.T) {
+	c := testutil.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data":[{"id":"1","name":"user1","username":"user1"},{"id":"2","name":"user2","username":"user2"}]}`)
+	}))
+	in := &ctxInstrumentation{}
+	c.Instrumentation = in
+	l := loader.NewUserLoader(c, nil, &loader.Options{Wait: 20 * time.Millisecond})
+
+	firstCtx, cancel := context.WithTimeout(context.WithValue(context.Background(), ctxKey{}, "first"), time.Minute)
+	defer cancel()
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, err := l.Load(firstCtx, "1")
+		assert.NoError(t, err)
+	}()
+	time.Sleep(5 * time.Millisecond)
+	go func() {
+		defer wg.Done()
+		_, err := l.Load(context.WithValue(context.Background(), ctxKey{}, "second"), "2")
+		assert.NoError(t, err)
+	}()
+	wg.Wait()
+
+	// the batch has the values of the first caller, but not its deadline
+	assert.Equal(t, []interface{}{"first"}, in.values)
+	assert.Equal(t, []bool{false}, in.deadlines)
+}
@@ -0,0 +1,133 @@
+package gotwi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/michimani/gotwi/resources"
+)
+
+// Logger is a structured logger that takes the message and the alternating keys and values,
+// such as *slog.Logger of log/slog.
+//
+// GotwiClient logs at the debug level the signed requests, the responses, the rate limit state and the errors.
+// tweets/archive and the CLI log their retries and the stream reconnections by Debug of the client,
+// and job.Job logs its retries by its own Logger. The Authorization header, oauth_signature, OAuthToken, SigningKey
+// and the bearer tokens are never logged as they are.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+}
+
+// Debug logs the event at the debug level if the client has the logger.
+// It is used by the packages that call the API with the client, such as for their retries.
+func (c *GotwiClient) Debug(msg string, args ...interface{}) {
+	if c == nil || c.Logger == nil {
+		return
+	}
+	c.Logger.Debug(msg, args...)
+}
+
+// logRequest logs the signed request.
+func (c *GotwiClient) logRequest(req *http.Request) {
+	if c.Logger == nil {
+		return
+	}
+	c.Debug("gotwi: request signed",
+		"method", req.Method,
+		"url", RedactURL(req.URL),
+		"authentication_method", string(c.AuthenticationMethod),
+		"authorization", RedactAuthorization(req.Header.Get("Authorization")),
+	)
+}
+
+// logResponse logs the response of the request, and its rate limit state if the response has it.
+func (c *GotwiClient) logResponse(req *http.Request, res *http.Response, err error, d time.Duration) {
+	if c.Logger == nil {
+		return
+	}
+
+	args := []interface{}{"method", req.Method, "url", RedactURL(req.URL)}
+	if id := RequestIDFromContext(req.Context()); id != "" {
+		args = append(args, "request_id", id)
+	}
+	if err != nil {
+		c.Debug("gotwi: request failed", append(args, "duration", d, "error", err)...)
+		return
+	}
+	c.Debug("gotwi: response", append(args, "status", res.StatusCode, "duration", d)...)
+
+	rli := rateLimitInformation(res)
+	if rli == nil {
+		return
+	}
+	c.Debug("gotwi: rate limit",
+		"method", req.Method,
+		"path", req.URL.Path,
+		"limit", rli.Limit,
+		"remaining", rli.Remaining,
+		"reset_at", rli.ResetAt,
+	)
+}
+
+// RedactAuthorization returns the Authorization header value whose credentials are redacted.
+// The parameters of OAuth 1.0a other than oauth_signature and oauth_token, such as oauth_nonce, are kept.
+func RedactAuthorization(v string) string {
+	if v == "" {
+		return ""
+	}
+
+	i := strings.Index(v, " ")
+	if i < 0 {
+		return Redacted
+	}
+	scheme, params := v[:i], v[i+1:]
+	if !strings.EqualFold(scheme, "OAuth") {
+		return scheme + " " + Redacted
+	}
+
+	l := strings.Split(params, ",")
+	for i, p := range l {
+		name := strings.TrimSpace(p)
+		if j := strings.Index(name, "="); j >= 0 {
+			name = name[:j]
+		}
+		switch name {
+		case "oauth_consumer_key", "oauth_nonce", "oauth_signature_method", "oauth_timestamp", "oauth_version":
+		default:
+			l[i] = name + `="` + Redacted + `"`
+		}
+	}
+	return scheme + " " + strings.Join(l, ",")
+}
+
+// String returns the client without its secrets, so that the client can be logged.
+func (c *GotwiClient) String() string {
+	return fmt.Sprintf("GotwiClient{AuthenticationMethod: %q, OAuthConsumerKey: %q, AccessToken: %s, OAuthToken: %s, SigningKey: %s}",
+		c.AuthenticationMethod, c.OAuthConsumerKey, redactSecret(c.AccessToken), redactSecret(c.OAuthToken), redactSecret(c.SigningKey))
+}
+
+// GoString is String for the %#v format.
+func (c *GotwiClient) GoString() string {
+	return c.String()
+}
+
+func redactSecret(s string) string {
+	if s == "" {
+		return `""`
+	}
+	return Redacted
+}
+
+// logNon2XX logs the error response, whose summary has the status and the error messages.
+func (c *GotwiClient) logNon2XX(e *resources.Non2XXError) {
+	if c.Logger == nil {
+		return
+	}
+	args := []interface{}{"status", IntValue(e.StatusCode), "summary", e.Summary()}
+	if e.RateLimitInfo != nil && e.RateLimitInfo.ResetAt != nil {
+		args = append(args, "reset_at", e.RateLimitInfo.ResetAt)
+	}
+	c.Debug("gotwi: error response", args...)
+}
@@ -0,0 +1,165 @@
+package gotwi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/internal/testutil"
+	"github.com/michimani/gotwi/users"
+	"github.com/michimani/gotwi/users/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logEntry struct {
+	msg  string
+	args map[string]string
+}
+
+type testLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e := logEntry{msg: msg, args: map[string]string{}}
+	for i := 0; i+1 < len(args); i += 2 {
+		e.args[fmt.Sprint(args[i])] = fmt.Sprint(args[i+1])
+	}
+	l.entries = append(l.entries, e)
+}
+
+func (l *testLogger) messages() []string {
+	m := []string{}
+	for _, e := range l.entries {
+		m = append(m, e.msg)
+	}
+	return m
+}
+
+func Test_Logger(t *testing.T) {
+	requests := 0
+	h := func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("x-rate-limit-limit", "75")
+		w.Header().Set("x-rate-limit-reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+		if requests > 1 {
+			w.Header().Set("x-rate-limit-remaining", "0")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"title":"Too Many Requests","detail":"Too Many Requests","type":"about:blank","status":429}`)
+			return
+		}
+		w.Header().Set("x-rate-limit-remaining", "74")
+		meHandler(w, r)
+	}
+	l := &testLogger{}
+	c, err := gotwi.NewGotwiClient(&gotwi.NewGotwiClientInput{
+		HTTPClient:           testutil.HTTPClient(t, http.HandlerFunc(h)),
+		AuthenticationMethod: gotwi.AuthenMethodOAuth1UserContext,
+		APIKey:               "api-key",
+		APIKeySecret:         "secret-api-key-secret",
+		OAuthToken:           "secret-oauth-token",
+		OAuthTokenSecret:     "secret-oauth-token-secret",
+		Logger:               l,
+	})
+	require.NoError(t, err)
+
+	_, err = users.UserLookupMe(context.Background(), c, &types.UserLookupMeParams{})
+	require.NoError(t, err)
+	_, err = users.UserLookupMe(context.Background(), c, &types.UserLookupMeParams{})
+	require.Error(t, err)
+
+	assert.Equal(t, []string{
+		"gotwi: request signed", "gotwi: response", "gotwi: rate limit",
+		"gotwi: request signed", "gotwi: response", "gotwi: rate limit", "gotwi: error response",
+	}, l.messages())
+
+	signed := l.entries[0].args
+	assert.Equal(t, "GET", signed["method"])
+	assert.Equal(t, "https://api.twitter.com/2/users/me", signed["url"])
+	assert.Contains(t, signed["authorization"], `oauth_consumer_key="api-key"`)
+	assert.Contains(t, signed["authorization"], `oauth_signature="REDACTED"`)
+	assert.Contains(t, signed["authorization"], `oauth_token="REDACTED"`)
+
+	assert.Equal(t, "200", l.entries[1].args["status"])
+	assert.Equal(t, "74", l.entries[2].args["remaining"])
+	assert.Equal(t, "75", l.entries[2].args["limit"])
+	assert.Equal(t, "/2/users/me", l.entries[2].args["path"])
+	assert.Equal(t, "429", l.entries[6].args["status"])
+	assert.Contains(t, l.entries[6].args["summary"], "Too Many Requests")
+
+	for _, e := range l.entries {
+		for _, v := range e.args {
+			assert.NotContains(t, v, "secret")
+		}
+	}
+}
+
+func Test_Logger_nil(t *testing.T) {
+	c := newMiddlewareClient(t, meHandler)
+	_, err := users.UserLookupMe(context.Background(), c, &types.UserLookupMeParams{})
+	assert.NoError(t, err)
+}
+
+func Test_GotwiClient_Debug(t *testing.T) {
+	l := &testLogger{}
+	c := &gotwi.GotwiClient{Logger: l}
+	c.Debug("gotwi: retry after rate limit", "wait", time.Second)
+	assert.Equal(t, []logEntry{{msg: "gotwi: retry after rate limit", args: map[string]string{"wait": "1s"}}}, l.entries)
+
+	// the client without the logger and the nil client do not log
+	assert.NotPanics(t, func() {
+		(&gotwi.GotwiClient{}).Debug("message")
+		var nilClient *gotwi.GotwiClient
+		nilClient.Debug("message")
+	})
+}
+
+func Test_RedactAuthorization(t *testing.T) {
+	cases := []struct {
+		name   string
+		value  string
+		expect string
+	}{
+		{name: "empty", value: "", expect: ""},
+		{name: "bearer", value: "Bearer secret", expect: "Bearer REDACTED"},
+		{name: "basic", value: "Basic c2VjcmV0", expect: "Basic REDACTED"},
+		{name: "no scheme", value: "secret", expect: "REDACTED"},
+		{
+			name:   "oauth",
+			value:  `OAuth oauth_consumer_key="key",oauth_nonce="n",oauth_signature="secret",oauth_signature_method="HMAC-SHA1",oauth_timestamp="1",oauth_token="secret",oauth_version="1.0"`,
+			expect: `OAuth oauth_consumer_key="key",oauth_nonce="n",oauth_signature="REDACTED",oauth_signature_method="HMAC-SHA1",oauth_timestamp="1",oauth_token="REDACTED",oauth_version="1.0"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			assert.Equal(tt, c.expect, gotwi.RedactAuthorization(c.value))
+		})
+	}
+}
+
+func Test_GotwiClient_String(t *testing.T) {
+	c := &gotwi.GotwiClient{
+		AuthenticationMethod: gotwi.AuthenMethodOAuth1UserContext,
+		OAuthConsumerKey:     "key",
+		OAuthToken:           "secret-token",
+		SigningKey:           "secret-key",
+	}
+
+	for _, s := range []string{fmt.Sprint(c), fmt.Sprintf("%v", c), fmt.Sprintf("%#v", c)} {
+		assert.NotContains(t, s, "secret")
+		assert.True(t, strings.HasPrefix(s, "GotwiClient{"), s)
+	}
+	assert.Equal(t, `GotwiClient{AuthenticationMethod: "OAuth 1.0a User context", OAuthConsumerKey: "key", AccessToken: "", OAuthToken: REDACTED, SigningKey: REDACTED}`, c.String())
+}
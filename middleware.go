@@ -0,0 +1,272 @@
+package gotwi
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Handler sends the request and returns its response.
+type Handler func(ctx context.Context, req *http.Request) (*http.Response, error)
+
+// Middleware wraps the next Handler, such as to modify the request or to observe the response.
+// The middlewares of GotwiClient run for every request of CallAPI and CallStreamAPI, in the order of the slice,
+// so the first one is the outermost. The cached responses of CallAPI do not run them.
+type Middleware func(next Handler) Handler
+
+// Use appends the middlewares to the client.
+func (c *GotwiClient) Use(m ...Middleware) {
+	c.Middlewares = append(c.Middlewares, m...)
+}
+
+// do sends the request by the http.Client through the middlewares.
+func (c *GotwiClient) do(hc *http.Client, req *http.Request) (*http.Response, error) {
+	h := Handler(func(ctx context.Context, req *http.Request) (*http.Response, error) {
+		return hc.Do(req.WithContext(ctx))
+	})
+	for i := len(c.Middlewares) - 1; i >= 0; i-- {
+		h = c.Middlewares[i](h)
+	}
+	start := time.Now()
+	res, err := h(req.Context(), req)
+	c.logResponse(req, res, err, time.Since(start))
+	recordResponse(req, res)
+	return res, err
+}
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	streamKey
+)
+
+// IsStream reports whether the request of the context is of CallStreamAPI, whose response body is the stream.
+// A middleware must not read such a body to the end.
+func IsStream(ctx context.Context) bool {
+	v, _ := ctx.Value(streamKey).(bool)
+	return v
+}
+
+// RequestIDFromContext returns the request ID set by RequestIDMiddleware, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(requestIDKey).(string)
+	return v
+}
+
+// DefaultRequestIDHeader is the header of RequestIDMiddleware.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware sets the request ID to the header, such as DefaultRequestIDHeader, and to the context
+// for RequestIDFromContext. If generate is nil, the ID is 16 random bytes in hex.
+// The ID of the header is kept if it is already set.
+func RequestIDMiddleware(header string, generate func() string) Middleware {
+	if generate == nil {
+		generate = randomID
+	}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			id := req.Header.Get(header)
+			if id == "" {
+				id = generate()
+				req.Header.Set(header, id)
+			}
+			return next(context.WithValue(ctx, requestIDKey, id), req)
+		}
+	}
+}
+
+func randomID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// UserAgentMiddleware sets the User-Agent header, such as "my-app/1.0".
+func UserAgentMiddleware(userAgent string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			req.Header.Set("User-Agent", userAgent)
+			return next(ctx, req)
+		}
+	}
+}
+
+// TimingMiddleware calls observe with the duration of each request, until the response header for the streams.
+// The response is nil if err is not nil.
+func TimingMiddleware(observe func(req *http.Request, res *http.Response, err error, d time.Duration)) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			res, err := next(ctx, req)
+			observe(req, res, err, time.Since(start))
+			return res, err
+		}
+	}
+}
+
+// LoggingMiddleware logs each request and its response with the log function such as log.Printf.
+// The Authorization header, the cookies, the oauth_* values and the tokens are redacted.
+// The bodies are logged if withBody is true, except the response bodies of the streams.
+func LoggingMiddleware(logf func(format string, v ...interface{}), withBody bool) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			prefix := ""
+			if id := RequestIDFromContext(ctx); id != "" {
+				prefix = "[" + id + "] "
+			}
+
+			line := prefix + "--> " + req.Method + " " + RedactURL(req.URL) + " " + formatHeader(RedactHeader(req.Header))
+			if withBody && req.Body != nil && req.Body != http.NoBody {
+				b, err := ioutil.ReadAll(req.Body)
+				req.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				req.Body = ioutil.NopCloser(bytes.NewReader(b))
+				line += " " + RedactBody(b)
+			}
+			logf("%s", line)
+
+			start := time.Now()
+			res, err := next(ctx, req)
+			elapsed := time.Since(start).Round(time.Millisecond)
+			if err != nil {
+				logf("%s<-- %s %s error (%s): %v", prefix, req.Method, RedactURL(req.URL), elapsed, err)
+				return nil, err
+			}
+
+			line = prefix + "<-- " + res.Status + " " + req.Method + " " + RedactURL(req.URL) + " (" + elapsed.String() + ") " + formatHeader(RedactHeader(res.Header))
+			if withBody && !IsStream(ctx) {
+				b, err := ioutil.ReadAll(res.Body)
+				res.Body.Close()
+				if err != nil {
+					return nil, err
+				}
+				res.Body = ioutil.NopCloser(bytes.NewReader(b))
+				line += " " + RedactBody(b)
+			}
+			logf("%s", line)
+			return res, nil
+		}
+	}
+}
+
+func formatHeader(h http.Header) string {
+	b, _ := json.Marshal(h)
+	return string(b)
+}
+
+// Redacted replaces the secrets redacted by RedactHeader, RedactURL and RedactBody.
+const Redacted = "REDACTED"
+
+// secretParameters are the names of the parameters and the JSON members that are redacted,
+// in addition to the names starting with oauth_.
+var secretParameters = map[string]bool{
+	"access_token":  true,
+	"refresh_token": true,
+	"token":         true,
+	"code":          true,
+	"code_verifier": true,
+	"client_secret": true,
+}
+
+var secretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// IsSecretParameter reports whether the query parameter or the JSON member is a secret, such as oauth_token.
+func IsSecretParameter(name string) bool {
+	name = strings.ToLower(name)
+	return secretParameters[name] || strings.HasPrefix(name, "oauth_")
+}
+
+// RedactHeader returns a copy of the header whose secrets, such as Authorization, are redacted.
+func RedactHeader(h http.Header) http.Header {
+	c := h.Clone()
+	for _, name := range secretHeaders {
+		if c.Get(name) != "" {
+			c.Set(name, Redacted)
+		}
+	}
+	return c
+}
+
+// RedactURL returns the URL whose query parameters of the secrets, such as oauth_token, are redacted.
+func RedactURL(u *url.URL) string {
+	q := u.Query()
+	redacted := false
+	for k, vs := range q {
+		if IsSecretParameter(k) {
+			for i := range vs {
+				vs[i] = Redacted
+			}
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.String()
+	}
+
+	c := *u
+	c.RawQuery = q.Encode()
+	return c.String()
+}
+
+// RedactBody returns the JSON or the form encoded body whose secrets, such as access_token, are redacted.
+// The other bodies are returned as they are.
+func RedactBody(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&v); err == nil {
+		redactJSON(v)
+		buf := &bytes.Buffer{}
+		e := json.NewEncoder(buf)
+		e.SetEscapeHTML(false)
+		e.Encode(v)
+		return strings.TrimSuffix(buf.String(), "\n")
+	}
+
+	if q, err := url.ParseQuery(string(b)); err == nil && strings.Contains(string(b), "=") {
+		redacted := false
+		for k, vs := range q {
+			if IsSecretParameter(k) {
+				for i := range vs {
+					vs[i] = Redacted
+				}
+				redacted = true
+			}
+		}
+		if redacted {
+			return q.Encode()
+		}
+	}
+	return string(b)
+}
+
+func redactJSON(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, m := range v {
+			if _, ok := m.(string); ok && IsSecretParameter(k) {
+				v[k] = Redacted
+				continue
+			}
+			redactJSON(m)
+		}
+	case []interface{}:
+		for _, m := range v {
+			redactJSON(m)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package gotwi_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/internal/testutil"
+	"github.com/michimani/gotwi/users"
+	"github.com/michimani/gotwi/users/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMiddlewareClient(t *testing.T, h http.HandlerFunc, m ...gotwi.Middleware) *gotwi.GotwiClient {
+	c, err := gotwi.NewGotwiClient(&gotwi.NewGotwiClientInput{
+		HTTPClient:           testutil.HTTPClient(t, h),
+		AuthenticationMethod: gotwi.AuthenMethodOAuth2BearerToken,
+		AccessToken:          "secret-access-token",
+		Middlewares:          m,
+	})
+	require.NoError(t, err)
+	return c
+}
+
+func meHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, `{"data":{"id":"1","name":"alice","username":"alice"}}`)
+}
+
+func Test_Middleware_Order(t *testing.T) {
+	order := []string{}
+	mark := func(name string) gotwi.Middleware {
+		return func(next gotwi.Handler) gotwi.Handler {
+			return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+				order = append(order, name+" in")
+				res, err := next(ctx, req)
+				order = append(order, name+" out")
+				return res, err
+			}
+		}
+	}
+
+	c := newMiddlewareClient(t, meHandler, mark("a"))
+	c.Use(mark("b"))
+
+	_, err := users.UserLookupMe(context.Background(), c, &types.UserLookupMeParams{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a in", "b in", "b out", "a out"}, order)
+}
+
+func Test_Middleware_BuiltIns(t *testing.T) {
+	var got *http.Request
+	h := func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		meHandler(w, r)
+	}
+
+	var mu sync.Mutex
+	logs := []string{}
+	logf := func(format string, v ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		logs = append(logs, fmt.Sprintf(format, v...))
+	}
+	var observed time.Duration
+	timing := gotwi.TimingMiddleware(func(req *http.Request, res *http.Response, err error, d time.Duration) {
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		observed = d
+	})
+
+	c := newMiddlewareClient(t, h,
+		gotwi.RequestIDMiddleware(gotwi.DefaultRequestIDHeader, func() string { return "req-1" }),
+		gotwi.UserAgentMiddleware("my-app/1.0"),
+		gotwi.LoggingMiddleware(logf, true),
+		timing,
+	)
+
+	res, err := users.UserLookupMe(context.Background(), c, &types.UserLookupMeParams{})
+	require.NoError(t, err)
+	// the logging middleware restores the body
+	assert.Equal(t, "alice", gotwi.StringValue(res.Data.Username))
+
+	assert.Equal(t, "req-1", got.Header.Get("X-Request-ID"))
+	assert.Equal(t, "my-app/1.0", got.Header.Get("User-Agent"))
+	assert.Greater(t, int64(observed), int64(0))
+
+	require.Len(t, logs, 2)
+	assert.True(t, strings.HasPrefix(logs[0], "[req-1] --> GET https://api.twitter.com/2/users/me"), logs[0])
+	assert.True(t, strings.HasPrefix(logs[1], "[req-1] <-- 200 OK GET https://api.twitter.com/2/users/me"), logs[1])
+	assert.Contains(t, logs[1], `"username":"alice"`)
+	for _, l := range logs {
+		assert.NotContains(t, l, "secret-access-token")
+	}
+}
+
+func Test_RedactURL(t *testing.T) {
+	cases := []struct {
+		name   string
+		url    string
+		expect string
+	}{
+		{
+			name:   "no secrets",
+			url:    "https://api.twitter.com/2/users?ids=1,2",
+			expect: "https://api.twitter.com/2/users?ids=1,2",
+		},
+		{
+			name:   "oauth values",
+			url:    "https://api.twitter.com/oauth/access_token?oauth_token=t&oauth_verifier=v&x=1",
+			expect: "https://api.twitter.com/oauth/access_token?oauth_token=REDACTED&oauth_verifier=REDACTED&x=1",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			u, _ := url.Parse(c.url)
+			assert.Equal(tt, c.expect, gotwi.RedactURL(u))
+		})
+	}
+}
+
+func Test_RedactBody(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		expect string
+	}{
+		{
+			name:   "json",
+			body:   `{"token_type":"bearer","access_token":"secret","data":[{"refresh_token":"secret","id":1460323737035677698}]}`,
+			expect: `{"access_token":"REDACTED","data":[{"id":1460323737035677698,"refresh_token":"REDACTED"}],"token_type":"bearer"}`,
+		},
+		{
+			name:   "form",
+			body:   "oauth_token=secret&oauth_token_secret=secret&user_id=1",
+			expect: "oauth_token=REDACTED&oauth_token_secret=REDACTED&user_id=1",
+		},
+		{
+			name:   "text",
+			body:   "Too Many Requests",
+			expect: "Too Many Requests",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			assert.Equal(tt, c.expect, gotwi.RedactBody([]byte(c.body)))
+		})
+	}
+}
+
+func Test_Middleware_Stream(t *testing.T) {
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "{\"data\":{\"id\":\"1\",\"text\":\"hello\"}}\r\n")
+	}
+	var isStream bool
+	c := newMiddlewareClient(t, h, gotwi.LoggingMiddleware(func(string, ...interface{}) {}, true), func(next gotwi.Handler) gotwi.Handler {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			isStream = gotwi.IsStream(ctx)
+			return next(ctx, req)
+		}
+	})
+
+	res, err := c.CallStreamAPI(context.Background(), "https://api.twitter.com/2/tweets/search/stream", "GET", &types.UserLookupMeParams{})
+	require.NoError(t, err)
+	defer res.Body.Close()
+	b, _ := ioutil.ReadAll(res.Body)
+	assert.Contains(t, string(b), "hello")
+	assert.True(t, isStream)
+}
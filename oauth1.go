@@ -137,7 +137,10 @@ func createParameterString(paramsMap map[string]string, nonce, ts string, in *Cr
 	qv.Add("oauth_nonce", nonce)
 	qv.Add("oauth_signature_method", OAuthSignatureMethodHMACSHA1)
 	qv.Add("oauth_timestamp", ts)
-	qv.Add("oauth_token", in.OAuthToken)
+	if in.OAuthToken != "" {
+		// oauth_token is omitted in the request of the request token
+		qv.Add("oauth_token", in.OAuthToken)
+	}
 	qv.Add("oauth_version", OAuthVersion10)
 
 	return qv.Encode()
@@ -0,0 +1,182 @@
+package gotwi
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	OAuth1RequestTokenEndpoint = "https://api.twitter.com/oauth/request_token"
+	OAuth1AuthorizeEndpoint    = "https://api.twitter.com/oauth/authorize"
+	OAuth1AccessTokenEndpoint  = "https://api.twitter.com/oauth/access_token"
+
+	// OAuth1CallbackOOB is the callback of the PIN-based flow.
+	OAuth1CallbackOOB = "oob"
+)
+
+// OAuth1RequestToken is the temporary token of the OAuth 1.0a 3-legged flow.
+type OAuth1RequestToken struct {
+	OAuthToken       string
+	OAuthTokenSecret string
+}
+
+// AuthorizeURL returns the URL where the user authorizes the app.
+// With the PIN-based flow, the page shows the PIN to pass to AccessOAuth1Token.
+func (t *OAuth1RequestToken) AuthorizeURL() string {
+	return OAuth1AuthorizeEndpoint + "?oauth_token=" + url.QueryEscape(t.OAuthToken)
+}
+
+// OAuth1AccessToken is the user token of the OAuth 1.0a 3-legged flow.
+type OAuth1AccessToken struct {
+	OAuthToken       string
+	OAuthTokenSecret string
+	UserID           string
+	ScreenName       string
+}
+
+// RequestOAuth1Token gets the request token. Use OAuth1CallbackOOB as callback for the PIN-based flow.
+// If httpClient is nil, the default client is used.
+func RequestOAuth1Token(ctx context.Context, httpClient *http.Client, apiKey, apiKeySecret, callback string) (*OAuth1RequestToken, error) {
+	if apiKey == "" || apiKeySecret == "" {
+		return nil, fmt.Errorf("API key and API key secret are required.")
+	}
+
+	signingKey := url.QueryEscape(apiKeySecret) + "&"
+	values, err := postOAuth1(ctx, httpClient, OAuth1RequestTokenEndpoint, apiKey, "", signingKey, map[string]string{"oauth_callback": callback}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &OAuth1RequestToken{
+		OAuthToken:       values.Get("oauth_token"),
+		OAuthTokenSecret: values.Get("oauth_token_secret"),
+	}
+	if t.OAuthToken == "" || t.OAuthTokenSecret == "" {
+		return nil, fmt.Errorf("oauth_token is empty")
+	}
+
+	return t, nil
+}
+
+// AccessOAuth1Token exchanges the request token and the verifier, which is the PIN with the PIN-based flow, for the user token.
+// If httpClient is nil, the default client is used.
+func AccessOAuth1Token(ctx context.Context, httpClient *http.Client, apiKey, apiKeySecret string, rt *OAuth1RequestToken, verifier string) (*OAuth1AccessToken, error) {
+	if rt == nil {
+		return nil, fmt.Errorf("OAuth1RequestToken is nil.")
+	}
+	if verifier == "" {
+		return nil, fmt.Errorf("verifier is required.")
+	}
+
+	signingKey := url.QueryEscape(apiKeySecret) + "&" + url.QueryEscape(rt.OAuthTokenSecret)
+	body := map[string]string{"oauth_verifier": verifier}
+	values, err := postOAuth1(ctx, httpClient, OAuth1AccessTokenEndpoint, apiKey, rt.OAuthToken, signingKey, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &OAuth1AccessToken{
+		OAuthToken:       values.Get("oauth_token"),
+		OAuthTokenSecret: values.Get("oauth_token_secret"),
+		UserID:           values.Get("user_id"),
+		ScreenName:       values.Get("screen_name"),
+	}
+	if t.OAuthToken == "" || t.OAuthTokenSecret == "" {
+		return nil, fmt.Errorf("oauth_token is empty")
+	}
+
+	return t, nil
+}
+
+// postOAuth1 posts the form body with the OAuth 1.0a header that has the extra oauth parameters,
+// and returns the form encoded response.
+func postOAuth1(ctx context.Context, httpClient *http.Client, endpoint, consumerKey, token, signingKey string, oauthParams, body map[string]string) (url.Values, error) {
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
+	}
+
+	pm := map[string]string{}
+	for k, v := range oauthParams {
+		pm[k] = v
+	}
+	form := url.Values{}
+	for k, v := range body {
+		pm[k] = v
+		form.Set(k, v)
+	}
+
+	out, err := CreateOAuthSignature(&CreateOAthSignatureInput{
+		HTTPMethod:       http.MethodPost,
+		RawEndpoint:      endpoint,
+		OAuthConsumerKey: consumerKey,
+		OAuthToken:       token,
+		SigningKey:       signingKey,
+		ParameterMap:     pm,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hp := map[string]string{
+		"oauth_consumer_key":     consumerKey,
+		"oauth_nonce":            out.OAuthNonce,
+		"oauth_signature":        out.OAuthSignature,
+		"oauth_signature_method": out.OAuthSignatureMethod,
+		"oauth_timestamp":        out.OAuthTimestamp,
+		"oauth_version":          out.OAuthVersion,
+	}
+	if token != "" {
+		hp["oauth_token"] = token
+	}
+	for k, v := range oauthParams {
+		hp[k] = v
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", oauth1HeaderValue(hp))
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		non200err, err := resolveNon2XXResponse(res)
+		if err != nil {
+			return nil, err
+		}
+		return nil, non200err
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return url.ParseQuery(string(b))
+}
+
+func oauth1HeaderValue(params map[string]string) string {
+	keys := []string{}
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := []string{}
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, url.QueryEscape(k), url.QueryEscape(params[k])))
+	}
+
+	return "OAuth " + strings.Join(pairs, ",")
+}
@@ -0,0 +1,166 @@
+package gotwi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	OAuth2AuthorizeEndpoint = "https://twitter.com/i/oauth2/authorize"
+	OAuth2UserTokenEndpoint = "https://api.twitter.com/2/oauth2/token"
+)
+
+// OAuth2PKCE is the OAuth 2.0 Authorization Code Flow with PKCE.
+type OAuth2PKCE struct {
+	ClientID     string
+	ClientSecret string // Only for confidential clients.
+	RedirectURI  string
+	Scopes       []string
+	State        string
+	CodeVerifier string
+}
+
+// OAuth2UserToken is the user token of the OAuth 2.0 Authorization Code Flow with PKCE.
+type OAuth2UserToken struct {
+	TokenType    string `json:"token_type"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+}
+
+// NewOAuth2PKCE returns OAuth2PKCE with a random state and code verifier.
+func NewOAuth2PKCE(clientID, clientSecret, redirectURI string, scopes []string) (*OAuth2PKCE, error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("ClientID is required.")
+	}
+	if redirectURI == "" {
+		return nil, fmt.Errorf("RedirectURI is required.")
+	}
+
+	state, err := randomURLSafe(16)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomURLSafe(32)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OAuth2PKCE{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURI:  redirectURI,
+		Scopes:       scopes,
+		State:        state,
+		CodeVerifier: verifier,
+	}, nil
+}
+
+// CodeChallenge returns the S256 code challenge of the code verifier.
+func (p *OAuth2PKCE) CodeChallenge() string {
+	sum := sha256.Sum256([]byte(p.CodeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthorizeURL returns the URL where the user authorizes the app.
+func (p *OAuth2PKCE) AuthorizeURL() string {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.ClientID)
+	q.Set("redirect_uri", p.RedirectURI)
+	q.Set("scope", strings.Join(p.Scopes, " "))
+	q.Set("state", p.State)
+	q.Set("code_challenge", p.CodeChallenge())
+	q.Set("code_challenge_method", "S256")
+
+	return OAuth2AuthorizeEndpoint + "?" + q.Encode()
+}
+
+// Exchange exchanges the authorization code of the redirect for the user token.
+// If httpClient is nil, the default client is used.
+func (p *OAuth2PKCE) Exchange(ctx context.Context, httpClient *http.Client, code string) (*OAuth2UserToken, error) {
+	if code == "" {
+		return nil, fmt.Errorf("code is required.")
+	}
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.RedirectURI)
+	form.Set("code_verifier", p.CodeVerifier)
+	form.Set("client_id", p.ClientID)
+
+	return p.requestToken(ctx, httpClient, form)
+}
+
+// Refresh gets a new user token by the refresh token, which is issued with the offline.access scope.
+// Only ClientID and ClientSecret of p are used. If httpClient is nil, the default client is used.
+func (p *OAuth2PKCE) Refresh(ctx context.Context, httpClient *http.Client, refreshToken string) (*OAuth2UserToken, error) {
+	if refreshToken == "" {
+		return nil, fmt.Errorf("refreshToken is required.")
+	}
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	form.Set("client_id", p.ClientID)
+
+	return p.requestToken(ctx, httpClient, form)
+}
+
+func (p *OAuth2PKCE) requestToken(ctx context.Context, httpClient *http.Client, form url.Values) (*OAuth2UserToken, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, OAuth2UserTokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if p.ClientSecret != "" {
+		req.SetBasicAuth(p.ClientID, p.ClientSecret)
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		non200err, err := resolveNon2XXResponse(res)
+		if err != nil {
+			return nil, err
+		}
+		return nil, non200err
+	}
+
+	t := &OAuth2UserToken{}
+	if err := json.NewDecoder(res.Body).Decode(t); err != nil {
+		return nil, err
+	}
+	if t.AccessToken == "" {
+		return nil, fmt.Errorf("access_token is empty")
+	}
+
+	return t, nil
+}
+
+func randomURLSafe(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
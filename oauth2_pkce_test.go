@@ -0,0 +1,142 @@
+package gotwi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OAuth2PKCE_AuthorizeURL(t *testing.T) {
+	p := &gotwi.OAuth2PKCE{
+		ClientID:     "client-id",
+		RedirectURI:  "http://127.0.0.1:8910/callback",
+		Scopes:       []string{"tweet.read", "users.read"},
+		State:        "state",
+		CodeVerifier: "dBjftJeZ4CVP-mJ92K9cfC5ixKqOeLcJHm4CoX6Ca-Q",
+	}
+
+	assert.Equal(t, "1P-slnosENfqq_ncSM36fEXqT77dA_kpa02cBTe6-Fc", p.CodeChallenge())
+
+	u, err := url.Parse(p.AuthorizeURL())
+	assert.NoError(t, err)
+	assert.Equal(t, "https://twitter.com/i/oauth2/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, url.Values{
+		"response_type":         {"code"},
+		"client_id":             {"client-id"},
+		"redirect_uri":          {"http://127.0.0.1:8910/callback"},
+		"scope":                 {"tweet.read users.read"},
+		"state":                 {"state"},
+		"code_challenge":        {"1P-slnosENfqq_ncSM36fEXqT77dA_kpa02cBTe6-Fc"},
+		"code_challenge_method": {"S256"},
+	}, u.Query())
+}
+
+func Test_NewOAuth2PKCE(t *testing.T) {
+	p1, err := gotwi.NewOAuth2PKCE("client-id", "", "http://127.0.0.1/callback", nil)
+	assert.NoError(t, err)
+	p2, err := gotwi.NewOAuth2PKCE("client-id", "", "http://127.0.0.1/callback", nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, p1.State, p2.State)
+	assert.NotEqual(t, p1.CodeVerifier, p2.CodeVerifier)
+	assert.GreaterOrEqual(t, len(p1.CodeVerifier), 43)
+
+	_, err = gotwi.NewOAuth2PKCE("", "", "http://127.0.0.1/callback", nil)
+	assert.Error(t, err)
+}
+
+func Test_OAuth2PKCE_Exchange(t *testing.T) {
+	hc := testutil.HTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.URL.Path != "/2/oauth2/token" || r.PostForm.Get("code") != "code" || r.PostForm.Get("code_verifier") != "verifier" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_request","error_description":"Value passed for the authorization code was invalid."}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"token_type":"bearer","expires_in":7200,"access_token":"user-access-token","refresh_token":"refresh","scope":"tweet.read users.read"}`)
+	}))
+
+	p := &gotwi.OAuth2PKCE{ClientID: "client-id", RedirectURI: "http://127.0.0.1/callback", CodeVerifier: "verifier"}
+
+	token, err := p.Exchange(context.Background(), hc, "code")
+	assert.NoError(t, err)
+	assert.Equal(t, &gotwi.OAuth2UserToken{
+		TokenType:    "bearer",
+		AccessToken:  "user-access-token",
+		RefreshToken: "refresh",
+		ExpiresIn:    7200,
+		Scope:        "tweet.read users.read",
+	}, token)
+
+	_, err = p.Exchange(context.Background(), hc, "invalid")
+	assert.Error(t, err)
+
+	// the user token is used without the API key
+	t.Setenv(gotwi.APIKeyEnvName, "")
+	t.Setenv(gotwi.APIKeySecretEnvName, "")
+	c, err := gotwi.NewGotwiClient(&gotwi.NewGotwiClientInput{
+		AuthenticationMethod: gotwi.AuthenMethodOAuth2BearerToken,
+		AccessToken:          token.AccessToken,
+	})
+	assert.NoError(t, err)
+	assert.True(t, c.IsReady())
+	assert.Equal(t, "user-access-token", c.AccessToken)
+}
+
+func Test_OAuth2PKCE_Refresh(t *testing.T) {
+	hc := testutil.HTTPClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		user, pass, _ := r.BasicAuth()
+		if r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("refresh_token") != "refresh" ||
+			r.PostForm.Get("client_id") != "client-id" || user != "client-id" || pass != "client-secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_request","error_description":"Value passed for the token was invalid."}`)
+			return
+		}
+		fmt.Fprint(w, `{"token_type":"bearer","expires_in":7200,"access_token":"new-access-token","refresh_token":"new-refresh","scope":"tweet.read offline.access"}`)
+	}))
+
+	p := &gotwi.OAuth2PKCE{ClientID: "client-id", ClientSecret: "client-secret"}
+
+	cases := []struct {
+		name         string
+		refreshToken string
+		expect       *gotwi.OAuth2UserToken
+		wantErr      bool
+	}{
+		{
+			name:         "ok",
+			refreshToken: "refresh",
+			expect: &gotwi.OAuth2UserToken{
+				TokenType:    "bearer",
+				AccessToken:  "new-access-token",
+				RefreshToken: "new-refresh",
+				ExpiresIn:    7200,
+				Scope:        "tweet.read offline.access",
+			},
+		},
+		{name: "ng: invalid refresh token", refreshToken: "invalid", wantErr: true},
+		{name: "ng: empty refresh token", refreshToken: "", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			token, err := p.Refresh(context.Background(), hc, c.refreshToken)
+			if c.wantErr {
+				assert.Error(tt, err)
+				assert.Nil(tt, token)
+				return
+			}
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, token)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Package profile reads the named credentials of the gotwi command from a config file.
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/michimani/gotwi"
+)
+
+const (
+	// PathEnvName is the environment variable of the config file path.
+	PathEnvName = "GOTWI_CONFIG"
+	// NameEnvName is the environment variable of the profile name.
+	NameEnvName = "GOTWI_PROFILE"
+
+	DefaultName = "default"
+)
+
+type Profile struct {
+	AuthenticationMethod gotwi.AuthenticationMethod `json:"authentication_method,omitempty"`
+	APIKey               string                     `json:"api_key,omitempty"`
+	APIKeySecret         string                     `json:"api_key_secret,omitempty"`
+	OAuthToken           string                     `json:"oauth_token,omitempty"`
+	OAuthTokenSecret     string                     `json:"oauth_token_secret,omitempty"`
+	AccessToken          string                     `json:"access_token,omitempty"` // OAuth 2.0 user token
+	RefreshToken         string                     `json:"refresh_token,omitempty"`
+	ExpiresAt            *time.Time                 `json:"expires_at,omitempty"` // of AccessToken
+	ClientID             string                     `json:"client_id,omitempty"`
+	ClientSecret         string                     `json:"client_secret,omitempty"` // only for confidential clients
+	UserID               string                     `json:"user_id,omitempty"`       // authenticated user
+	Username             string                     `json:"username,omitempty"`
+}
+
+type Config struct {
+	// Current is the profile used when no profile is specified.
+	Current  string              `json:"current,omitempty"`
+	Profiles map[string]*Profile `json:"profiles"`
+}
+
+// DefaultPath returns the value of GOTWI_CONFIG, or gotwi/config.json in the user config directory.
+func DefaultPath() (string, error) {
+	if p := os.Getenv(PathEnvName); p != "" {
+		return p, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "gotwi", "config.json"), nil
+}
+
+// Load reads the config file. It returns an empty config if the file does not exist.
+func Load(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{Profiles: map[string]*Profile{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Config{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("config file '%s' is invalid: %w", path, err)
+	}
+	if c.Profiles == nil {
+		c.Profiles = map[string]*Profile{}
+	}
+
+	return c, nil
+}
+
+// Save writes the config file with 0600 permissions, creating the directory if needed.
+func (c *Config) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Set adds or replaces the profile of the name. The first profile becomes the current profile.
+func (c *Config) Set(name string, p *Profile) {
+	if c.Profiles == nil {
+		c.Profiles = map[string]*Profile{}
+	}
+	c.Profiles[name] = p
+	if c.Current == "" {
+		c.Current = name
+	}
+}
+
+// Name returns the profile name to use. The order is name, GOTWI_PROFILE, Current and DefaultName.
+func (c *Config) Name(name string) string {
+	if name != "" {
+		return name
+	}
+	if n := os.Getenv(NameEnvName); n != "" {
+		return n
+	}
+	if c != nil && c.Current != "" {
+		return c.Current
+	}
+	return DefaultName
+}
+
+// Profile returns the profile of the name, or nil if it does not exist.
+func (c *Config) Profile(name string) *Profile {
+	if c == nil {
+		return nil
+	}
+	return c.Profiles[c.Name(name)]
+}
+
+// SetToken sets the OAuth 2.0 user token and its expiration time. The refresh token is kept if the token has none.
+func (p *Profile) SetToken(t *gotwi.OAuth2UserToken, now time.Time) {
+	p.AccessToken = t.AccessToken
+	if t.RefreshToken != "" {
+		p.RefreshToken = t.RefreshToken
+	}
+	p.ExpiresAt = nil
+	if t.ExpiresIn > 0 {
+		expiresAt := now.Add(time.Duration(t.ExpiresIn) * time.Second)
+		p.ExpiresAt = &expiresAt
+	}
+}
+
+// NeedsRefresh reports whether the OAuth 2.0 user token expires within a minute and it can be refreshed.
+func (p *Profile) NeedsRefresh(now time.Time) bool {
+	return p.RefreshToken != "" && p.ExpiresAt != nil && now.Add(time.Minute).After(*p.ExpiresAt)
+}
+
+// ClientInput returns NewGotwiClientInput with the credentials of the profile.
+// AuthenticationMethod defaults to OAuth 1.0a User context if the profile has the OAuth token, otherwise OAuth 2.0 Bearer token
+// with the access token of the profile, or with an app-only token if it is empty.
+func (p *Profile) ClientInput() *gotwi.NewGotwiClientInput {
+	in := &gotwi.NewGotwiClientInput{
+		AuthenticationMethod: p.AuthenticationMethod,
+		APIKey:               p.APIKey,
+		APIKeySecret:         p.APIKeySecret,
+		OAuthToken:           p.OAuthToken,
+		OAuthTokenSecret:     p.OAuthTokenSecret,
+		AccessToken:          p.AccessToken,
+	}
+
+	if in.AuthenticationMethod == "" {
+		if p.OAuthToken != "" {
+			in.AuthenticationMethod = gotwi.AuthenMethodOAuth1UserContext
+		} else {
+			in.AuthenticationMethod = gotwi.AuthenMethodOAuth2BearerToken
+		}
+	}
+
+	return in
+}
@@ -0,0 +1,215 @@
+package profile_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Config_Save(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "gotwi")
+	path := filepath.Join(dir, "config.json")
+
+	c := &profile.Config{}
+	c.Set("work", &profile.Profile{APIKey: "key", OAuthToken: "token"})
+	require.NoError(t, c.Save(path))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	dirInfo, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), dirInfo.Mode().Perm())
+
+	// the file is replaced by rename, so the temporary file is not left and the permissions are kept
+	require.NoError(t, os.Chmod(path, 0o644))
+	c.Set("other", &profile.Profile{APIKey: "other-key"})
+	require.NoError(t, c.Save(path))
+	info, err = os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "config.json", entries[0].Name())
+
+	loaded, err := profile.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, c, loaded)
+	assert.Equal(t, "work", loaded.Current)
+}
+
+func Test_Config_Save_failure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"profiles":{}}`), 0o600))
+
+	// the existing file is not broken when the new file cannot be written
+	require.NoError(t, os.Chmod(dir, 0o500))
+	t.Cleanup(func() { os.Chmod(dir, 0o700) })
+	if f, err := ioutil.TempFile(dir, "probe"); err == nil {
+		// the permissions are not enforced, such as for root
+		f.Close()
+		os.Remove(f.Name())
+		t.Skip("the directory is writable")
+	}
+
+	c := &profile.Config{}
+	c.Set("work", &profile.Profile{APIKey: "key"})
+	assert.Error(t, c.Save(path))
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"profiles":{}}`, string(b))
+}
+
+func Test_Load(t *testing.T) {
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.json")
+	require.NoError(t, ioutil.WriteFile(corrupt, []byte(`{"profiles":`), 0o600))
+	noProfiles := filepath.Join(dir, "no_profiles.json")
+	require.NoError(t, ioutil.WriteFile(noProfiles, []byte(`{"current":"work"}`), 0o600))
+
+	cases := []struct {
+		name      string
+		path      string
+		expect    *profile.Config
+		expectErr string
+	}{
+		{
+			name:   "missing file",
+			path:   filepath.Join(dir, "missing.json"),
+			expect: &profile.Config{Profiles: map[string]*profile.Profile{}},
+		},
+		{
+			name:   "no profiles",
+			path:   noProfiles,
+			expect: &profile.Config{Current: "work", Profiles: map[string]*profile.Profile{}},
+		},
+		{
+			name:      "corrupt file",
+			path:      corrupt,
+			expectErr: "config file '" + corrupt + "' is invalid: unexpected end of JSON input",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			config, err := profile.Load(c.path)
+			if c.expectErr != "" {
+				assert.Nil(tt, config)
+				assert.EqualError(tt, err, c.expectErr)
+				return
+			}
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, config)
+		})
+	}
+}
+
+func Test_Config_Profile(t *testing.T) {
+	work := &profile.Profile{Username: "work"}
+	other := &profile.Profile{Username: "other"}
+	def := &profile.Profile{Username: "default"}
+
+	cases := []struct {
+		name    string
+		config  *profile.Config
+		arg     string
+		envName string
+		expect  *profile.Profile
+	}{
+		{name: "name", config: &profile.Config{Current: "work", Profiles: map[string]*profile.Profile{"work": work, "other": other}}, arg: "other", envName: "work", expect: other},
+		{name: "env", config: &profile.Config{Current: "work", Profiles: map[string]*profile.Profile{"work": work, "other": other}}, envName: "other", expect: other},
+		{name: "current", config: &profile.Config{Current: "work", Profiles: map[string]*profile.Profile{"work": work, "default": def}}, expect: work},
+		{name: "default", config: &profile.Config{Profiles: map[string]*profile.Profile{"work": work, "default": def}}, expect: def},
+		{name: "not found", config: &profile.Config{Profiles: map[string]*profile.Profile{"work": work}}, arg: "other", expect: nil},
+		{name: "nil config", config: nil, arg: "work", expect: nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			tt.Setenv(profile.NameEnvName, c.envName)
+			assert.Equal(tt, c.expect, c.config.Profile(c.arg))
+		})
+	}
+}
+
+func Test_Profile_ClientInput(t *testing.T) {
+	cases := []struct {
+		name    string
+		profile *profile.Profile
+		expect  *gotwi.NewGotwiClientInput
+	}{
+		{
+			name:    "oauth1",
+			profile: &profile.Profile{APIKey: "key", APIKeySecret: "secret", OAuthToken: "token", OAuthTokenSecret: "token-secret", UserID: "1"},
+			expect: &gotwi.NewGotwiClientInput{
+				AuthenticationMethod: gotwi.AuthenMethodOAuth1UserContext,
+				APIKey:               "key",
+				APIKeySecret:         "secret",
+				OAuthToken:           "token",
+				OAuthTokenSecret:     "token-secret",
+			},
+		},
+		{
+			name:    "oauth2 user token",
+			profile: &profile.Profile{AuthenticationMethod: gotwi.AuthenMethodOAuth2BearerToken, AccessToken: "access", RefreshToken: "refresh", ClientID: "client"},
+			expect: &gotwi.NewGotwiClientInput{
+				AuthenticationMethod: gotwi.AuthenMethodOAuth2BearerToken,
+				AccessToken:          "access",
+			},
+		},
+		{
+			name:    "oauth2 app-only",
+			profile: &profile.Profile{APIKey: "key", APIKeySecret: "secret"},
+			expect: &gotwi.NewGotwiClientInput{
+				AuthenticationMethod: gotwi.AuthenMethodOAuth2BearerToken,
+				APIKey:               "key",
+				APIKeySecret:         "secret",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			assert.Equal(tt, c.expect, c.profile.ClientInput())
+		})
+	}
+}
+
+func Test_Profile_SetToken(t *testing.T) {
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	p := &profile.Profile{AccessToken: "old", RefreshToken: "old-refresh"}
+
+	p.SetToken(&gotwi.OAuth2UserToken{AccessToken: "new", ExpiresIn: 7200}, now)
+	assert.Equal(t, "new", p.AccessToken)
+	// the refresh token is kept if the token has none
+	assert.Equal(t, "old-refresh", p.RefreshToken)
+	assert.Equal(t, now.Add(2*time.Hour), *p.ExpiresAt)
+
+	cases := []struct {
+		name   string
+		now    time.Time
+		expect bool
+	}{
+		{name: "valid", now: now, expect: false},
+		{name: "expires within a minute", now: now.Add(2*time.Hour - 30*time.Second), expect: true},
+		{name: "expired", now: now.Add(3 * time.Hour), expect: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			assert.Equal(tt, c.expect, p.NeedsRefresh(c.now))
+		})
+	}
+
+	assert.False(t, (&profile.Profile{RefreshToken: "refresh"}).NeedsRefresh(now), "unknown expiration")
+	assert.False(t, (&profile.Profile{ExpiresAt: &now}).NeedsRefresh(now), "no refresh token")
+}
@@ -0,0 +1,273 @@
+// Package render renders a tweet to HTML, Markdown or plain text using its entities.
+package render
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/michimani/gotwi/resources"
+	"github.com/michimani/gotwi/twittertext"
+)
+
+type Format int
+
+const (
+	FormatHTML Format = iota
+	FormatMarkdown
+	FormatPlainText
+)
+
+// Renderer renders tweets. The zero value renders links to twitter.com.
+type Renderer struct {
+	// UserURL returns the URL linked from a mention. The default is https://twitter.com/<username>.
+	UserURL func(username string) string
+	// HashtagURL returns the URL linked from a hashtag. The default is https://twitter.com/hashtag/<tag>.
+	HashtagURL func(tag string) string
+	// CashtagURL returns the URL linked from a cashtag. The default is https://twitter.com/search?q=%24<tag>.
+	CashtagURL func(tag string) string
+	// KeepMediaURL keeps the trailing t.co URL of the attached media.
+	KeepMediaURL bool
+}
+
+var defaultRenderer = &Renderer{}
+
+// HTML renders the tweet to HTML with the default Renderer.
+func HTML(t resources.Tweet, inc *resources.Includes) string {
+	return defaultRenderer.Render(t, inc, FormatHTML)
+}
+
+// Markdown renders the tweet to Markdown with the default Renderer.
+func Markdown(t resources.Tweet, inc *resources.Includes) string {
+	return defaultRenderer.Render(t, inc, FormatMarkdown)
+}
+
+// PlainText renders the tweet to plain text with the default Renderer.
+func PlainText(t resources.Tweet, inc *resources.Includes) string {
+	return defaultRenderer.Render(t, inc, FormatPlainText)
+}
+
+type spanKind int
+
+const (
+	spanURL spanKind = iota
+	spanMention
+	spanHashtag
+	spanCashtag
+)
+
+type span struct {
+	start int
+	end   int
+	kind  spanKind
+	text  string // the text to display
+	href  string
+}
+
+// Render renders the tweet in the format.
+// The text of the tweet is HTML-unescaped, and the entities are applied with their code point offsets.
+// If the tweet has no entities, they are extracted from the text by twittertext.
+// The includes are used to find the attached media, and may be nil.
+func (r *Renderer) Render(t resources.Tweet, inc *resources.Includes, f Format) string {
+	text := html.UnescapeString(stringValue(t.Text))
+	rs := []rune(text)
+
+	entities := t.Entities
+	if entities == nil {
+		entities = twittertext.ExtractEntities(text)
+	}
+
+	spans := r.spans(entities, len(rs))
+	if !r.KeepMediaURL && hasMedia(t, inc) {
+		spans, rs = stripMediaURL(spans, rs)
+	}
+
+	b := strings.Builder{}
+	pos := 0
+	for _, s := range spans {
+		b.WriteString(formatText(string(rs[pos:s.start]), f))
+		b.WriteString(formatSpan(s, f))
+		pos = s.end
+	}
+	b.WriteString(formatText(string(rs[pos:]), f))
+
+	return b.String()
+}
+
+func (r *Renderer) spans(e *resources.TweetEntities, length int) []span {
+	spans := []span{}
+	add := func(start, end *int, s span) {
+		if start == nil || end == nil || *start < 0 || *end > length || *start >= *end {
+			return
+		}
+		s.start, s.end = *start, *end
+		spans = append(spans, s)
+	}
+
+	for _, u := range e.URLs {
+		display := firstNonEmpty(u.DisplayURL, u.ExpandedURL, u.URL)
+		href := firstNonEmpty(u.ExpandedURL, u.URL)
+		if parsed, err := url.Parse(href); err == nil && parsed.Scheme == "" {
+			// URLs extracted from the text may not have a protocol.
+			href = "http://" + href
+		}
+		add(u.Start, u.End, span{kind: spanURL, text: display, href: href})
+	}
+	for _, m := range e.Mentions {
+		username := stringValue(m.Username)
+		add(m.Start, m.End, span{kind: spanMention, text: "@" + username, href: r.userURL(username)})
+	}
+	for _, h := range e.HashTags {
+		tag := stringValue(h.Tag)
+		add(h.Start, h.End, span{kind: spanHashtag, text: "#" + tag, href: r.hashtagURL(tag)})
+	}
+	for _, c := range e.CashTags {
+		tag := stringValue(c.Tag)
+		add(c.Start, c.End, span{kind: spanCashtag, text: "$" + tag, href: r.cashtagURL(tag)})
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	// drop overlapping entities
+	nonOverlapping := []span{}
+	pos := 0
+	for _, s := range spans {
+		if s.start < pos {
+			continue
+		}
+		nonOverlapping = append(nonOverlapping, s)
+		pos = s.end
+	}
+
+	return nonOverlapping
+}
+
+// stripMediaURL removes the URL of the attached media at the end of the text.
+func stripMediaURL(spans []span, rs []rune) ([]span, []rune) {
+	if len(spans) == 0 {
+		return spans, rs
+	}
+
+	last := spans[len(spans)-1]
+	if last.kind != spanURL || !isMediaURL(last) || strings.TrimSpace(string(rs[last.end:])) != "" {
+		return spans, rs
+	}
+
+	rest := []rune(strings.TrimRight(string(rs[:last.start]), " \t\n"))
+	return spans[:len(spans)-1], rest
+}
+
+func isMediaURL(s span) bool {
+	if strings.HasPrefix(s.text, "pic.twitter.com/") {
+		return true
+	}
+
+	u, err := url.Parse(s.href)
+	if err != nil || (u.Host != "twitter.com" && u.Host != "mobile.twitter.com") {
+		return false
+	}
+	return strings.Contains(u.Path, "/photo/") || strings.Contains(u.Path, "/video/")
+}
+
+func hasMedia(t resources.Tweet, inc *resources.Includes) bool {
+	if t.Attachments == nil || len(t.Attachments.MediaKeys) == 0 {
+		return false
+	}
+	if inc == nil || len(inc.Media) == 0 {
+		// The media is attached even if it is not expanded.
+		return true
+	}
+	return len(resources.NewIncludesIndex(inc).HydrateTweet(t).Media) > 0
+}
+
+func formatText(s string, f Format) string {
+	switch f {
+	case FormatHTML:
+		return strings.ReplaceAll(html.EscapeString(s), "\n", "<br>\n")
+	case FormatMarkdown:
+		return strings.ReplaceAll(escapeMarkdown(s), "\n", "  \n")
+	default:
+		return s
+	}
+}
+
+func formatSpan(s span, f Format) string {
+	switch f {
+	case FormatHTML:
+		if !isSafeURL(s.href) {
+			return html.EscapeString(s.text)
+		}
+		return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(s.href), html.EscapeString(s.text))
+	case FormatMarkdown:
+		if !isSafeURL(s.href) {
+			return escapeMarkdown(s.text)
+		}
+		return fmt.Sprintf("[%s](%s)", escapeMarkdown(s.text), escapeMarkdownURL(s.href))
+	default:
+		if s.kind == spanURL {
+			return s.href
+		}
+		return s.text
+	}
+}
+
+func isSafeURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`(`, `\(`, `)`, `\)`, `#`, `\#`, `!`, `\!`, `|`, `\|`, `~`, `\~`,
+	`<`, `&lt;`, `>`, `&gt;`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+func escapeMarkdownURL(s string) string {
+	return strings.NewReplacer(`(`, `%28`, `)`, `%29`, ` `, `%20`).Replace(s)
+}
+
+func (r *Renderer) userURL(username string) string {
+	if r.UserURL != nil {
+		return r.UserURL(username)
+	}
+	return "https://twitter.com/" + url.PathEscape(username)
+}
+
+func (r *Renderer) hashtagURL(tag string) string {
+	if r.HashtagURL != nil {
+		return r.HashtagURL(tag)
+	}
+	return "https://twitter.com/hashtag/" + url.PathEscape(tag)
+}
+
+func (r *Renderer) cashtagURL(tag string) string {
+	if r.CashtagURL != nil {
+		return r.CashtagURL(tag)
+	}
+	return "https://twitter.com/search?q=" + url.QueryEscape("$"+tag)
+}
+
+func firstNonEmpty(ss ...*string) string {
+	for _, s := range ss {
+		if s != nil && *s != "" {
+			return *s
+		}
+	}
+	return ""
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
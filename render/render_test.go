@@ -0,0 +1,131 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/michimani/gotwi/render"
+	"github.com/michimani/gotwi/resources"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Render(t *testing.T) {
+	cases := []struct {
+		name           string
+		tweet          resources.Tweet
+		includes       *resources.Includes
+		expectHTML     string
+		expectMarkdown string
+		expectPlain    string
+	}{
+		{
+			name: "entities with code point offsets",
+			tweet: resources.Tweet{
+				Text: str("🎉 @gopher #golang $GOOG https://t.co/abc"),
+				Entities: &resources.TweetEntities{
+					Mentions: []resources.TweetEntityTag{{Start: num(2), End: num(9), Username: str("gopher")}},
+					HashTags: []resources.TweetEntityTag{{Start: num(10), End: num(17), Tag: str("golang")}},
+					CashTags: []resources.TweetEntityTag{{Start: num(18), End: num(23), Tag: str("GOOG")}},
+					URLs: []resources.URL{{
+						Start:       num(24),
+						End:         num(40),
+						URL:         str("https://t.co/abc"),
+						ExpandedURL: str("https://go.dev/doc"),
+						DisplayURL:  str("go.dev/doc"),
+					}},
+				},
+			},
+			expectHTML: `🎉 <a href="https://twitter.com/gopher">@gopher</a> ` +
+				`<a href="https://twitter.com/hashtag/golang">#golang</a> ` +
+				`<a href="https://twitter.com/search?q=%24GOOG">$GOOG</a> ` +
+				`<a href="https://go.dev/doc">go.dev/doc</a>`,
+			expectMarkdown: `🎉 [@gopher](https://twitter.com/gopher) ` +
+				`[\#golang](https://twitter.com/hashtag/golang) ` +
+				`[$GOOG](https://twitter.com/search?q=%24GOOG) ` +
+				`[go.dev/doc](https://go.dev/doc)`,
+			expectPlain: "🎉 @gopher #golang $GOOG https://go.dev/doc",
+		},
+		{
+			name: "unescape and escape",
+			tweet: resources.Tweet{
+				Text:     str("a &amp; b &lt;i&gt;*c*&lt;/i&gt;\nd"),
+				Entities: &resources.TweetEntities{},
+			},
+			expectHTML:     "a &amp; b &lt;i&gt;*c*&lt;/i&gt;<br>\nd",
+			expectMarkdown: "a & b &lt;i&gt;\\*c\\*&lt;/i&gt;  \nd",
+			expectPlain:    "a & b <i>*c*</i>\nd",
+		},
+		{
+			name: "strip trailing media URL",
+			tweet: resources.Tweet{
+				Text:        str("photo https://t.co/pic"),
+				Attachments: &resources.TweetAttachments{MediaKeys: []*string{str("3_1")}},
+				Entities: &resources.TweetEntities{
+					URLs: []resources.URL{{
+						Start:       num(6),
+						End:         num(22),
+						URL:         str("https://t.co/pic"),
+						ExpandedURL: str("https://twitter.com/user/status/1/photo/1"),
+						DisplayURL:  str("pic.twitter.com/pic"),
+					}},
+				},
+			},
+			includes:       &resources.Includes{Media: []resources.Media{{MediaKey: str("3_1")}}},
+			expectHTML:     "photo",
+			expectMarkdown: "photo",
+			expectPlain:    "photo",
+		},
+		{
+			name: "keep media URL without attachments",
+			tweet: resources.Tweet{
+				Text: str("photo https://t.co/pic"),
+				Entities: &resources.TweetEntities{
+					URLs: []resources.URL{{
+						Start:       num(6),
+						End:         num(22),
+						URL:         str("https://t.co/pic"),
+						ExpandedURL: str("https://twitter.com/user/status/1/photo/1"),
+						DisplayURL:  str("pic.twitter.com/pic"),
+					}},
+				},
+			},
+			expectHTML:     `photo <a href="https://twitter.com/user/status/1/photo/1">pic.twitter.com/pic</a>`,
+			expectMarkdown: `photo [pic.twitter.com/pic](https://twitter.com/user/status/1/photo/1)`,
+			expectPlain:    "photo https://twitter.com/user/status/1/photo/1",
+		},
+		{
+			name:           "extract entities if not set",
+			tweet:          resources.Tweet{Text: str("hi @gopher")},
+			expectHTML:     `hi <a href="https://twitter.com/gopher">@gopher</a>`,
+			expectMarkdown: `hi [@gopher](https://twitter.com/gopher)`,
+			expectPlain:    "hi @gopher",
+		},
+		{
+			name: "unsafe URL is not linked",
+			tweet: resources.Tweet{
+				Text: str("x https://t.co/x"),
+				Entities: &resources.TweetEntities{
+					URLs: []resources.URL{{Start: num(2), End: num(16), ExpandedURL: str("javascript:alert(1)"), DisplayURL: str("<b>")}},
+				},
+			},
+			expectHTML:     "x &lt;b&gt;",
+			expectMarkdown: "x &lt;b&gt;",
+			expectPlain:    "x javascript:alert(1)",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			assert.Equal(tt, c.expectHTML, render.HTML(c.tweet, c.includes))
+			assert.Equal(tt, c.expectMarkdown, render.Markdown(c.tweet, c.includes))
+			assert.Equal(tt, c.expectPlain, render.PlainText(c.tweet, c.includes))
+		})
+	}
+}
+
+func str(s string) *string {
+	return &s
+}
+
+func num(i int) *int {
+	return &i
+}
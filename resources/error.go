@@ -3,6 +3,7 @@ package resources
 import (
 	"fmt"
 
+	"github.com/michimani/gotwi/internal/gotwierrors"
 	"github.com/michimani/gotwi/internal/util"
 )
 
@@ -16,6 +17,12 @@ type Non2XXError struct {
 	RateLimitInfo *util.RateLimitInformation `json:"-"`
 }
 
+// Error implements error. CallAPI returns *Non2XXError for a non-2XX response,
+// so callers can get the status code and the rate limit information with errors.As.
+func (e *Non2XXError) Error() string {
+	return fmt.Sprintf(gotwierrors.ErrorNon2XXStatus, e.Summary())
+}
+
 type ErrorInformation struct {
 	Message    *string              `json:"message"`
 	Code       ErrorCode            `json:"code,omitempty"`
@@ -5,7 +5,7 @@ import "time"
 type Place struct {
 	FullName        *string     `json:"full_name"`
 	ID              *string     `json:"id"`
-	ContainedWithin *string     `json:"contained_within,omitempty"`
+	ContainedWithin []*string   `json:"contained_within,omitempty"`
 	Country         *string     `json:"country,omitempty"`
 	CountryCode     *string     `json:"country_code,omitempty"`
 	Geo             *IncludeGeo `json:"geo,omitempty"`
@@ -15,7 +15,7 @@ type Place struct {
 
 type Media struct {
 	MediaKey         *string         `json:"media_key"`
-	Type             *string         `json:"type"`
+	Type             *MediaType      `json:"type"`
 	DurationMs       *int            `json:"duration_ms,omitempty"`
 	Height           *int            `json:"height,omitempty"`
 	NonPublicMetrics map[string]*int `json:"non_public_metrics,omitempty"`
@@ -24,7 +24,8 @@ type Media struct {
 	PromotedMetrics  map[string]*int `json:"promoted_metrics,omitempty"`
 	PublicMetrics    map[string]*int `json:"public_metrics,omitempty"`
 	Width            *int            `json:"width,omitempty"`
-	AltText          *int            `json:"alt_text,omitempty"`
+	AltText          *string         `json:"alt_text,omitempty"`
+	URL              *string         `json:"url,omitempty"`
 }
 
 type Poll struct {
@@ -0,0 +1,256 @@
+package resources_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/michimani/gotwi/resources"
+	"github.com/stretchr/testify/assert"
+)
+
+// decodeFixture decodes testdata/<name> into v.
+// Unknown fields are rejected, so that a field returned by the API but missing in resources fails the test.
+func decodeFixture(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	if err := d.Decode(v); err != nil {
+		t.Fatalf("failed to decode %s: %v", name, err)
+	}
+}
+
+func Test_Fixture_Tweet(t *testing.T) {
+	res := struct {
+		Data []resources.Tweet `json:"data"`
+	}{}
+	decodeFixture(t, "tweet.json", &res)
+
+	assert.Len(t, res.Data, 2)
+
+	tw := res.Data[0]
+	assert.Equal(t, "1460323737035677698", *tw.ID)
+	assert.Equal(t, "7_1460322142680072196", *tw.Attachments.MediaKeys[0])
+	assert.Equal(t, "Brand", *tw.ContextAnnotations[1].Domain.Name)
+	assert.Equal(t, "Twitter", *tw.ContextAnnotations[1].Entity.Name)
+	assert.Equal(t, 2021, tw.CreatedAt.Year())
+	assert.Equal(t, "Twitter API v2", *tw.Entities.Annotations[0].NormalizedText)
+	assert.Equal(t, 200, *tw.Entities.URLs[0].Status)
+	assert.Equal(t, 1200, *tw.Entities.URLs[0].Images[0].Width)
+	assert.Equal(t, "pic.twitter.com/YFfCDErHsg", *tw.Entities.URLs[1].DisplayURL)
+	assert.Equal(t, "v2API", *tw.Entities.HashTags[0].Tag)
+	assert.Equal(t, "TWTR", *tw.Entities.CashTags[0].Tag)
+	assert.Equal(t, "TwitterAPI", *tw.Entities.Mentions[0].Username)
+	assert.Equal(t, "6253282", *tw.Entities.Mentions[0].ID)
+	assert.Equal(t, "Point", *tw.Geo.Coordinates.Type)
+	assert.Equal(t, -122.41942, *tw.Geo.Coordinates.Coordinates[0])
+	assert.Equal(t, 37.77493, *tw.Geo.Coordinates.Coordinates[1])
+	assert.Equal(t, "5a110d312052166f", *tw.Geo.PlaceID)
+	assert.Equal(t, 1119, *tw.PublicMetrics.LikeCount)
+	assert.Equal(t, resources.ReferencedTweetTypeQuoted, *tw.ReferencedTweets[0].Type)
+	assert.Equal(t, resources.ReplySettingsEveryone, *tw.ReplySettings)
+	assert.Equal(t, "tweet", *tw.Withheld.Scope)
+
+	reply := res.Data[1]
+	assert.Equal(t, "1460302839327019008", *reply.Attachments.PollIDs[0])
+	assert.Nil(t, reply.Attachments.MediaKeys)
+	assert.Equal(t, "2244994945", *reply.InReplyToUserID)
+	assert.Equal(t, resources.ReplySettingsMentionedUsers, *reply.ReplySettings)
+	assert.Equal(t, 120, *reply.NonPublicMetrics.ImpressionCount)
+	assert.Equal(t, 5, *reply.OrganicMetrics.LikeCount)
+	assert.Equal(t, 0, *reply.PromotedMetrics.RetweetCount)
+	assert.Nil(t, reply.Geo)
+}
+
+func Test_Fixture_User(t *testing.T) {
+	res := struct {
+		Data []resources.User `json:"data"`
+	}{}
+	decodeFixture(t, "user.json", &res)
+
+	assert.Len(t, res.Data, 1)
+
+	u := res.Data[0]
+	assert.Equal(t, "TwitterDev", *u.Username)
+	assert.Equal(t, "https://developer.twitter.com/en/community", *u.Entities.URL.URLs[0].ExpandedURL)
+	assert.Equal(t, "TwitterDev", *u.Entities.Description.HashTags[0].Tag)
+	assert.Equal(t, "TwitterAPI", *u.Entities.Description.Mentions[0].Username)
+	assert.Equal(t, "TWTR", *u.Entities.Description.CashTags[0].Tag)
+	assert.Equal(t, 513868, *u.PublicMetrics.FollowersCount)
+	assert.True(t, *u.Verified)
+	assert.Equal(t, "user", *u.Withheld.Scope)
+}
+
+func Test_Fixture_List(t *testing.T) {
+	res := struct {
+		Data resources.List `json:"data"`
+	}{}
+	decodeFixture(t, "list.json", &res)
+
+	assert.Equal(t, "Twitter Developers", *res.Data.Name)
+	assert.False(t, *res.Data.Private)
+	assert.Equal(t, 32, *res.Data.MemberCount)
+	assert.Equal(t, "2244994945", *res.Data.OwnerID)
+}
+
+func Test_Fixture_Space(t *testing.T) {
+	cases := []struct {
+		name           string
+		fixture        string
+		expectState    string
+		expectSpeakers int
+		expectTopics   int
+		expectSchedule bool
+	}{
+		{
+			name:           "live space",
+			fixture:        "space.json",
+			expectState:    "live",
+			expectSpeakers: 2,
+			expectTopics:   1,
+			expectSchedule: false,
+		},
+		{
+			name:           "scheduled space",
+			fixture:        "scheduled_space.json",
+			expectState:    "scheduled",
+			expectSpeakers: 0,
+			expectTopics:   0,
+			expectSchedule: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			res := struct {
+				Data resources.Space `json:"data"`
+			}{}
+			decodeFixture(tt, c.fixture, &res)
+
+			assert.Equal(tt, c.expectState, *res.Data.State)
+			assert.Len(tt, res.Data.SpeakerIDs, c.expectSpeakers)
+			assert.Len(tt, res.Data.TopicIDs, c.expectTopics)
+			assert.Equal(tt, c.expectSchedule, res.Data.ScheduledStart != nil)
+		})
+	}
+}
+
+func Test_Fixture_Includes(t *testing.T) {
+	inc := resources.Includes{}
+	decodeFixture(t, "includes.json", &inc)
+
+	assert.Len(t, inc.Users, 1)
+	assert.Len(t, inc.Tweets, 1)
+
+	assert.Len(t, inc.Media, 2)
+	assert.Equal(t, resources.MediaTypeVideo, *inc.Media[0].Type)
+	assert.Equal(t, 46947, *inc.Media[0].DurationMs)
+	assert.Equal(t, 3162, *inc.Media[0].PublicMetrics["view_count"])
+	assert.Equal(t, "The Twitter logo on a blue background", *inc.Media[1].AltText)
+	assert.Equal(t, "https://pbs.twimg.com/media/FEMCgBqXoAUGPJL.jpg", *inc.Media[1].URL)
+
+	assert.Len(t, inc.Places, 1)
+	assert.Equal(t, "US", *inc.Places[0].CountryCode)
+	assert.Empty(t, inc.Places[0].ContainedWithin)
+	assert.Equal(t, -122.514926, *inc.Places[0].Geo.BBox[0])
+
+	assert.Len(t, inc.Polls, 1)
+	assert.Equal(t, "closed", *inc.Polls[0].VotingStatus)
+	assert.Equal(t, 12, *inc.Polls[0].Options[0].Votes)
+
+	assert.Len(t, inc.Topics, 1)
+	assert.Equal(t, "Technology", *inc.Topics[0].Name)
+}
+
+func Test_Fixture_TweetCount(t *testing.T) {
+	res := struct {
+		Data []resources.TweetCount      `json:"data"`
+		Meta resources.TweetCountAllMeta `json:"meta"`
+	}{}
+	decodeFixture(t, "tweet_count.json", &res)
+
+	assert.Len(t, res.Data, 2)
+	assert.Equal(t, 22, *res.Data[0].TweetCount)
+	assert.True(t, res.Data[0].Start.Before(*res.Data[0].End))
+	assert.Equal(t, 36, *res.Meta.TotalTweetCount)
+	assert.NotNil(t, res.Meta.NextToken)
+}
+
+func Test_Fixture_StreamRule(t *testing.T) {
+	res := struct {
+		Data []resources.FilterdStreamRule       `json:"data"`
+		Meta resources.FilterdStreamRulesGetMeta `json:"meta"`
+	}{}
+	decodeFixture(t, "stream_rule.json", &res)
+
+	assert.Len(t, res.Data, 2)
+	assert.Equal(t, "dog pictures", *res.Data[0].Tag)
+	assert.Nil(t, res.Data[1].Tag)
+	assert.Equal(t, 2019, res.Meta.Sent.Year())
+}
+
+func Test_Fixture_TimelineMeta(t *testing.T) {
+	res := struct {
+		Meta resources.TweetTimelineMeta `json:"meta"`
+	}{}
+	decodeFixture(t, "timeline_meta.json", &res)
+
+	assert.Equal(t, 5, *res.Meta.Count)
+	assert.Equal(t, "1337122535188652033", *res.Meta.NewestID)
+	assert.Equal(t, "77qp8", *res.Meta.PreviousToken)
+}
+
+func Test_Fixture_Non2XXError(t *testing.T) {
+	cases := []struct {
+		name          string
+		fixture       string
+		expectTitle   bool
+		expectCode    resources.ErrorCode
+		expectMessage string
+	}{
+		{
+			name:          "problem details",
+			fixture:       "non2xx_error.json",
+			expectTitle:   true,
+			expectCode:    0,
+			expectMessage: "The `id` query parameter value [abc] is not valid",
+		},
+		{
+			name:          "error code",
+			fixture:       "non2xx_error_code.json",
+			expectTitle:   false,
+			expectCode:    88,
+			expectMessage: "Rate limit exceeded",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			e := resources.Non2XXError{}
+			decodeFixture(tt, c.fixture, &e)
+
+			assert.Equal(tt, c.expectTitle, e.Title != nil)
+			assert.Equal(tt, c.expectCode, e.Errors[0].Code)
+			assert.Equal(tt, c.expectMessage, *e.Errors[0].Message)
+		})
+	}
+}
+
+func Test_Fixture_PartialError(t *testing.T) {
+	res := struct {
+		Errors []resources.PartialError `json:"errors"`
+	}{}
+	decodeFixture(t, "partial_error.json", &res)
+
+	assert.Len(t, res.Errors, 2)
+	assert.Equal(t, "Not Found Error", *res.Errors[0].Title)
+	assert.Equal(t, "includes", *res.Errors[1].Section)
+	assert.Equal(t, "pinned_tweet_id", *res.Errors[1].Field)
+}
@@ -0,0 +1,257 @@
+package resources
+
+// IncludesIndex indexes the objects in Includes by their IDs (or media keys),
+// so that they can be joined back onto the primary data objects of a response.
+type IncludesIndex struct {
+	users  map[string]*User
+	tweets map[string]*Tweet
+	media  map[string]*Media
+	places map[string]*Place
+	polls  map[string]*Poll
+	topics map[string]*Topic
+}
+
+type HydratedTweet struct {
+	Tweet
+	Author     *User
+	InReplyTo  *User
+	Media      []*Media
+	Place      *Place
+	Polls      []*Poll
+	Referenced []HydratedReferencedTweet
+}
+
+type HydratedReferencedTweet struct {
+	Type   *ReferencedTweetType
+	Tweet  *Tweet
+	Author *User
+}
+
+type HydratedSpace struct {
+	Space
+	Creator      *User
+	Hosts        []*User
+	Speakers     []*User
+	InvitedUsers []*User
+	Topics       []*Topic
+}
+
+type HydratedUser struct {
+	User
+	PinnedTweet *Tweet
+}
+
+type HydratedList struct {
+	List
+	Owner *User
+}
+
+// NewIncludesIndex returns an index of the given includes. A nil Includes returns an empty index.
+// Every response type in the tweets, lists, spaces and users packages has an Includes field,
+// so it can be used as NewIncludesIndex(&res.Includes).
+func NewIncludesIndex(inc *Includes) *IncludesIndex {
+	x := &IncludesIndex{
+		users:  map[string]*User{},
+		tweets: map[string]*Tweet{},
+		media:  map[string]*Media{},
+		places: map[string]*Place{},
+		polls:  map[string]*Poll{},
+		topics: map[string]*Topic{},
+	}
+
+	if inc == nil {
+		return x
+	}
+
+	for i := range inc.Users {
+		if inc.Users[i].ID != nil {
+			x.users[*inc.Users[i].ID] = &inc.Users[i]
+		}
+	}
+	for i := range inc.Tweets {
+		if inc.Tweets[i].ID != nil {
+			x.tweets[*inc.Tweets[i].ID] = &inc.Tweets[i]
+		}
+	}
+	for i := range inc.Media {
+		if inc.Media[i].MediaKey != nil {
+			x.media[*inc.Media[i].MediaKey] = &inc.Media[i]
+		}
+	}
+	for i := range inc.Places {
+		if inc.Places[i].ID != nil {
+			x.places[*inc.Places[i].ID] = &inc.Places[i]
+		}
+	}
+	for i := range inc.Polls {
+		if inc.Polls[i].ID != nil {
+			x.polls[*inc.Polls[i].ID] = &inc.Polls[i]
+		}
+	}
+	for i := range inc.Topics {
+		if inc.Topics[i].ID != nil {
+			x.topics[*inc.Topics[i].ID] = &inc.Topics[i]
+		}
+	}
+
+	return x
+}
+
+func (x *IncludesIndex) User(id *string) *User {
+	if id == nil {
+		return nil
+	}
+	return x.users[*id]
+}
+
+func (x *IncludesIndex) Tweet(id *string) *Tweet {
+	if id == nil {
+		return nil
+	}
+	return x.tweets[*id]
+}
+
+func (x *IncludesIndex) Media(key *string) *Media {
+	if key == nil {
+		return nil
+	}
+	return x.media[*key]
+}
+
+func (x *IncludesIndex) Place(id *string) *Place {
+	if id == nil {
+		return nil
+	}
+	return x.places[*id]
+}
+
+func (x *IncludesIndex) Poll(id *string) *Poll {
+	if id == nil {
+		return nil
+	}
+	return x.polls[*id]
+}
+
+func (x *IncludesIndex) Topic(id *string) *Topic {
+	if id == nil {
+		return nil
+	}
+	return x.topics[*id]
+}
+
+// HydrateTweet joins the author, media, place, polls and referenced tweets
+// in the includes onto the tweet. Objects that are not present in the includes are left nil (or omitted).
+func (x *IncludesIndex) HydrateTweet(t Tweet) HydratedTweet {
+	h := HydratedTweet{
+		Tweet:     t,
+		Author:    x.User(t.AuthorID),
+		InReplyTo: x.User(t.InReplyToUserID),
+	}
+
+	if t.Attachments != nil {
+		for _, k := range t.Attachments.MediaKeys {
+			if m := x.Media(k); m != nil {
+				h.Media = append(h.Media, m)
+			}
+		}
+		for _, id := range t.Attachments.PollIDs {
+			if p := x.Poll(id); p != nil {
+				h.Polls = append(h.Polls, p)
+			}
+		}
+	}
+
+	if t.Geo != nil {
+		h.Place = x.Place(t.Geo.PlaceID)
+	}
+
+	for _, rt := range t.ReferencedTweets {
+		hr := HydratedReferencedTweet{
+			Type:  rt.Type,
+			Tweet: x.Tweet(rt.ID),
+		}
+		if hr.Tweet != nil {
+			hr.Author = x.User(hr.Tweet.AuthorID)
+		}
+		h.Referenced = append(h.Referenced, hr)
+	}
+
+	return h
+}
+
+func (x *IncludesIndex) HydrateTweets(ts []Tweet) []HydratedTweet {
+	hs := make([]HydratedTweet, 0, len(ts))
+	for _, t := range ts {
+		hs = append(hs, x.HydrateTweet(t))
+	}
+	return hs
+}
+
+// HydrateSpace joins the creator, hosts, speakers, invited users and topics in the includes onto the space.
+func (x *IncludesIndex) HydrateSpace(s Space) HydratedSpace {
+	h := HydratedSpace{
+		Space:        s,
+		Creator:      x.User(s.CreatorID),
+		Hosts:        x.usersByIDs(s.HostIDs),
+		Speakers:     x.usersByIDs(s.SpeakerIDs),
+		InvitedUsers: x.usersByIDs(s.InvitedUserIDs),
+	}
+
+	for _, id := range s.TopicIDs {
+		if t := x.Topic(id); t != nil {
+			h.Topics = append(h.Topics, t)
+		}
+	}
+
+	return h
+}
+
+func (x *IncludesIndex) HydrateSpaces(ss []Space) []HydratedSpace {
+	hs := make([]HydratedSpace, 0, len(ss))
+	for _, s := range ss {
+		hs = append(hs, x.HydrateSpace(s))
+	}
+	return hs
+}
+
+// HydrateUser joins the pinned tweet in the includes onto the user.
+func (x *IncludesIndex) HydrateUser(u User) HydratedUser {
+	return HydratedUser{
+		User:        u,
+		PinnedTweet: x.Tweet(u.PinnedTweetID),
+	}
+}
+
+func (x *IncludesIndex) HydrateUsers(us []User) []HydratedUser {
+	hs := make([]HydratedUser, 0, len(us))
+	for _, u := range us {
+		hs = append(hs, x.HydrateUser(u))
+	}
+	return hs
+}
+
+// HydrateList joins the owner in the includes onto the list.
+func (x *IncludesIndex) HydrateList(l List) HydratedList {
+	return HydratedList{
+		List:  l,
+		Owner: x.User(l.OwnerID),
+	}
+}
+
+func (x *IncludesIndex) HydrateLists(ls []List) []HydratedList {
+	hs := make([]HydratedList, 0, len(ls))
+	for _, l := range ls {
+		hs = append(hs, x.HydrateList(l))
+	}
+	return hs
+}
+
+func (x *IncludesIndex) usersByIDs(ids []*string) []*User {
+	var us []*User
+	for _, id := range ids {
+		if u := x.User(id); u != nil {
+			us = append(us, u)
+		}
+	}
+	return us
+}
@@ -0,0 +1,170 @@
+package resources_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/michimani/gotwi/resources"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IncludesIndex_HydrateTweet(t *testing.T) {
+	const includes = `{
+		"users": [
+			{"id": "10", "name": "author", "username": "author"},
+			{"id": "20", "name": "quoted author", "username": "quoted_author"}
+		],
+		"tweets": [{"id": "2", "text": "quoted", "author_id": "20"}],
+		"media": [{"media_key": "3_1", "type": "photo"}, {"media_key": "3_2", "type": "photo"}],
+		"places": [{"id": "p1", "full_name": "Tokyo, Japan"}],
+		"polls": [{"id": "poll1", "options": []}]
+	}`
+
+	cases := []struct {
+		name             string
+		tweet            string
+		expectAuthor     string
+		expectMediaCount int
+		expectPlace      string
+		expectPollCount  int
+		expectRefAuthors []string
+	}{
+		{
+			name: "ok: all expansions",
+			tweet: `{
+				"id": "1", "text": "test", "author_id": "10",
+				"attachments": {"media_keys": ["3_1", "3_2"], "poll_ids": ["poll1"]},
+				"geo": {"place_id": "p1"},
+				"referenced_tweets": [{"type": "quoted", "id": "2"}]
+			}`,
+			expectAuthor:     "author",
+			expectMediaCount: 2,
+			expectPlace:      "Tokyo, Japan",
+			expectPollCount:  1,
+			expectRefAuthors: []string{"quoted_author"},
+		},
+		{
+			name:             "ok: objects not in includes",
+			tweet:            `{"id": "1", "text": "test", "author_id": "99", "attachments": {"media_keys": ["9_9"]}, "referenced_tweets": [{"type": "replied_to", "id": "99"}]}`,
+			expectAuthor:     "",
+			expectMediaCount: 0,
+			expectPlace:      "",
+			expectPollCount:  0,
+			expectRefAuthors: []string{""},
+		},
+		{
+			name:             "ok: no expansions",
+			tweet:            `{"id": "1", "text": "test"}`,
+			expectAuthor:     "",
+			expectMediaCount: 0,
+			expectPlace:      "",
+			expectPollCount:  0,
+			expectRefAuthors: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			inc := resources.Includes{}
+			assert.NoError(tt, json.Unmarshal([]byte(includes), &inc))
+			tweet := resources.Tweet{}
+			assert.NoError(tt, json.Unmarshal([]byte(c.tweet), &tweet))
+
+			h := resources.NewIncludesIndex(&inc).HydrateTweet(tweet)
+
+			assert.Equal(tt, tweet.ID, h.ID)
+			if c.expectAuthor == "" {
+				assert.Nil(tt, h.Author)
+			} else {
+				assert.Equal(tt, c.expectAuthor, *h.Author.Username)
+			}
+			assert.Len(tt, h.Media, c.expectMediaCount)
+			if c.expectPlace == "" {
+				assert.Nil(tt, h.Place)
+			} else {
+				assert.Equal(tt, c.expectPlace, *h.Place.FullName)
+			}
+			assert.Len(tt, h.Polls, c.expectPollCount)
+			assert.Len(tt, h.Referenced, len(c.expectRefAuthors))
+			for i, a := range c.expectRefAuthors {
+				if a == "" {
+					assert.Nil(tt, h.Referenced[i].Author)
+				} else {
+					assert.Equal(tt, a, *h.Referenced[i].Author.Username)
+				}
+			}
+		})
+	}
+}
+
+func Test_IncludesIndex_HydrateSpace(t *testing.T) {
+	cases := []struct {
+		name          string
+		includes      *resources.Includes
+		space         string
+		expectCreator bool
+		expectHosts   int
+		expectSpeaker int
+		expectTopics  int
+	}{
+		{
+			name: "ok",
+			includes: &resources.Includes{
+				Users: []resources.User{
+					{ID: str("10"), Username: str("host")},
+					{ID: str("20"), Username: str("speaker")},
+				},
+				Topics: []resources.Topic{{ID: str("t1"), Name: str("Technology")}},
+			},
+			space:         `{"id": "s1", "state": "live", "creator_id": "10", "host_ids": ["10"], "speaker_ids": ["10", "20"], "topic_ids": ["t1"]}`,
+			expectCreator: true,
+			expectHosts:   1,
+			expectSpeaker: 2,
+			expectTopics:  1,
+		},
+		{
+			name:          "ok: nil includes",
+			includes:      nil,
+			space:         `{"id": "s1", "state": "live", "creator_id": "10", "host_ids": ["10"]}`,
+			expectCreator: false,
+			expectHosts:   0,
+			expectSpeaker: 0,
+			expectTopics:  0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			s := resources.Space{}
+			assert.NoError(tt, json.Unmarshal([]byte(c.space), &s))
+
+			h := resources.NewIncludesIndex(c.includes).HydrateSpace(s)
+
+			assert.Equal(tt, c.expectCreator, h.Creator != nil)
+			assert.Len(tt, h.Hosts, c.expectHosts)
+			assert.Len(tt, h.Speakers, c.expectSpeaker)
+			assert.Len(tt, h.Topics, c.expectTopics)
+		})
+	}
+}
+
+func Test_IncludesIndex_HydrateUserAndList(t *testing.T) {
+	inc := &resources.Includes{
+		Users:  []resources.User{{ID: str("10"), Username: str("owner")}},
+		Tweets: []resources.Tweet{{ID: str("1"), Text: str("pinned")}},
+	}
+	x := resources.NewIncludesIndex(inc)
+
+	u := x.HydrateUser(resources.User{ID: str("10"), PinnedTweetID: str("1")})
+	assert.Equal(t, "pinned", *u.PinnedTweet.Text)
+
+	l := x.HydrateList(resources.List{ID: str("100"), OwnerID: str("10")})
+	assert.Equal(t, "owner", *l.Owner.Username)
+
+	assert.Nil(t, x.HydrateUser(resources.User{ID: str("10")}).PinnedTweet)
+	assert.Nil(t, x.HydrateList(resources.List{ID: str("100")}).Owner)
+}
+
+func str(s string) *string {
+	return &s
+}
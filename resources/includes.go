@@ -0,0 +1,16 @@
+package resources
+
+type Includes struct {
+	Users  []User  `json:"users,omitempty"`
+	Tweets []Tweet `json:"tweets,omitempty"`
+	Places []Place `json:"places,omitempty"`
+	Media  []Media `json:"media,omitempty"`
+	Polls  []Poll  `json:"polls,omitempty"`
+	Topics []Topic `json:"topics,omitempty"`
+}
+
+type Topic struct {
+	ID          *string `json:"id"`
+	Name        *string `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
@@ -0,0 +1,17 @@
+package resources
+
+type MediaType string
+
+const (
+	MediaTypePhoto       MediaType = "photo"
+	MediaTypeVideo       MediaType = "video"
+	MediaTypeAnimatedGIF MediaType = "animated_gif"
+)
+
+func (m MediaType) String() string {
+	return string(m)
+}
+
+func (m MediaType) Valid() bool {
+	return m == MediaTypePhoto || m == MediaTypeVideo || m == MediaTypeAnimatedGIF
+}
@@ -30,7 +30,22 @@ type SpacesLookupByCreatorsIDsMeta struct {
 }
 
 type FilterdStreamRulesGetMeta struct {
-	Sent *time.Time `json:"sent"`
+	Sent        *time.Time `json:"sent"`
+	ResultCount *int       `json:"result_count,omitempty"`
+}
+
+type FilterdStreamRulesPostMeta struct {
+	Sent    *time.Time                        `json:"sent"`
+	Summary FilterdStreamRulesPostMetaSummary `json:"summary"`
+}
+
+type FilterdStreamRulesPostMetaSummary struct {
+	Created    *int `json:"created"`
+	NotCreated *int `json:"not_created"`
+	Valid      *int `json:"valid"`
+	Invalid    *int `json:"invalid"`
+	Deleted    *int `json:"deleted"`
+	NotDeleted *int `json:"not_deleted"`
 }
 
 type ListLookupOwnedListsMeta struct {
@@ -0,0 +1,17 @@
+package resources
+
+type ReferencedTweetType string
+
+const (
+	ReferencedTweetTypeRetweeted ReferencedTweetType = "retweeted"
+	ReferencedTweetTypeQuoted    ReferencedTweetType = "quoted"
+	ReferencedTweetTypeRepliedTo ReferencedTweetType = "replied_to"
+)
+
+func (r ReferencedTweetType) String() string {
+	return string(r)
+}
+
+func (r ReferencedTweetType) Valid() bool {
+	return r == ReferencedTweetTypeRetweeted || r == ReferencedTweetTypeQuoted || r == ReferencedTweetTypeRepliedTo
+}
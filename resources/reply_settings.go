@@ -0,0 +1,17 @@
+package resources
+
+type ReplySettings string
+
+const (
+	ReplySettingsEveryone       ReplySettings = "everyone"
+	ReplySettingsMentionedUsers ReplySettings = "mentionedUsers"
+	ReplySettingsFollowing      ReplySettings = "following"
+)
+
+func (r ReplySettings) String() string {
+	return string(r)
+}
+
+func (r ReplySettings) Valid() bool {
+	return r == ReplySettingsEveryone || r == ReplySettingsMentionedUsers || r == ReplySettingsFollowing
+}
@@ -13,6 +13,7 @@ type Space struct {
 	InvitedUserIDs   []*string  `json:"invited_user_ids,omitempty"`
 	ParticipantCount *int       `json:"participant_count,omitempty"`
 	SpeakerIDs       []*string  `json:"speaker_ids,omitempty"`
+	TopicIDs         []*string  `json:"topic_ids,omitempty"`
 	State            *string    `json:"state"`
 	Title            *string    `json:"title,omitempty"`
 	ScheduledStart   *time.Time `json:"scheduled_start,omitempty"`
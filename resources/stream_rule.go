@@ -5,3 +5,9 @@ type FilterdStreamRule struct {
 	Value *string `json:"value"`
 	Tag   *string `json:"tag"`
 }
+
+// FilterdStreamMatchingRule is a rule that a tweet of the filtered stream matched.
+type FilterdStreamMatchingRule struct {
+	ID  *string `json:"id"`
+	Tag *string `json:"tag"`
+}
@@ -5,7 +5,7 @@ import "time"
 type Tweet struct {
 	ID                 *string             `json:"id"`
 	Text               *string             `json:"text"`
-	Attachments        []map[string]string `json:"attachments,omitempty"`
+	Attachments        *TweetAttachments   `json:"attachments,omitempty"`
 	AuthorID           *string             `json:"author_id,omitempty"`
 	ContextAnnotations []ContextAnnotation `json:"context_annotations,omitempty"`
 	ConversationId     *string             `json:"conversation_id,omitempty"`
@@ -20,11 +20,60 @@ type Tweet struct {
 	PromotedMetrics    *PromotedMetrics    `json:"promoted_metrics,omitempty"`
 	PublicMetrics      *TweetPublicMetrics `json:"public_metrics,omitempty"`
 	ReferencedTweets   []ReferencedTweet   `json:"referenced_tweets,omitempty"`
-	ReplySettings      *string             `json:"reply_settings,omitempty"`
+	ReplySettings      *ReplySettings      `json:"reply_settings,omitempty"`
 	Source             *string             `json:"source,omitempty"`
 	Withheld           *TweetWithheld      `json:"withheld,omitempty"`
 }
 
+type TweetAttachments struct {
+	MediaKeys []*string `json:"media_keys,omitempty"`
+	PollIDs   []*string `json:"poll_ids,omitempty"`
+}
+
+// IsRetweet returns true if the tweet is a retweet of another tweet.
+func (t *Tweet) IsRetweet() bool {
+	return t.referencedTweetID(ReferencedTweetTypeRetweeted) != nil
+}
+
+// IsQuote returns true if the tweet quotes another tweet.
+func (t *Tweet) IsQuote() bool {
+	return t.referencedTweetID(ReferencedTweetTypeQuoted) != nil
+}
+
+// IsReply returns true if the tweet is a reply to another tweet.
+func (t *Tweet) IsReply() bool {
+	return t.referencedTweetID(ReferencedTweetTypeRepliedTo) != nil
+}
+
+// RetweetedTweetID returns the ID of the retweeted tweet, or nil if the tweet is not a retweet.
+func (t *Tweet) RetweetedTweetID() *string {
+	return t.referencedTweetID(ReferencedTweetTypeRetweeted)
+}
+
+// QuotedTweetID returns the ID of the quoted tweet, or nil if the tweet is not a quote.
+func (t *Tweet) QuotedTweetID() *string {
+	return t.referencedTweetID(ReferencedTweetTypeQuoted)
+}
+
+// RepliedToTweetID returns the ID of the tweet replied to, or nil if the tweet is not a reply.
+func (t *Tweet) RepliedToTweetID() *string {
+	return t.referencedTweetID(ReferencedTweetTypeRepliedTo)
+}
+
+func (t *Tweet) referencedTweetID(typ ReferencedTweetType) *string {
+	if t == nil {
+		return nil
+	}
+
+	for _, rt := range t.ReferencedTweets {
+		if rt.Type != nil && *rt.Type == typ {
+			return rt.ID
+		}
+	}
+
+	return nil
+}
+
 type ContextAnnotation struct {
 	Domain struct {
 		ID          *string `json:"id"`
@@ -32,9 +81,10 @@ type ContextAnnotation struct {
 		Description *string `json:"description"`
 	} `json:"domain"`
 	Entity struct {
-		ID   *string `json:"id"`
-		Name *string `json:"name"`
-	}
+		ID          *string `json:"id"`
+		Name        *string `json:"name"`
+		Description *string `json:"description,omitempty"`
+	} `json:"entity"`
 }
 
 type TweetEntities struct {
@@ -53,10 +103,14 @@ type Annotation struct {
 	NormalizedText *string  `json:"normalized_text"`
 }
 
+// TweetEntityTag is a hashtag, a cashtag or a mention. Tag is set for the hashtags and the cashtags,
+// and Username and ID are set for the mentions.
 type TweetEntityTag struct {
-	Start *int    `json:"start"`
-	End   *int    `json:"end"`
-	Tag   *string `json:"tag"`
+	Start    *int    `json:"start"`
+	End      *int    `json:"end"`
+	Tag      *string `json:"tag,omitempty"`
+	Username *string `json:"username,omitempty"`
+	ID       *string `json:"id,omitempty"`
 }
 
 type URL struct {
@@ -65,16 +119,23 @@ type URL struct {
 	URL         *string `json:"url"`
 	ExpandedURL *string `json:"expanded_url"`
 	DisplayURL  *string `json:"display_url"`
-	Status      *string `json:"status"`
-	Title       *string `json:"title"`
-	Description *string `json:"description"`
-	UnwoundURL  *string `json:"unwound_url"`
+	Status      *int    `json:"status,omitempty"`
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+	UnwoundURL  *string `json:"unwound_url,omitempty"`
+	Images      []Image `json:"images,omitempty"`
+}
+
+type Image struct {
+	URL    *string `json:"url"`
+	Width  *int    `json:"width"`
+	Height *int    `json:"height"`
 }
 
 type Geo struct {
 	Coordinates struct {
-		Type        *string `json:"type"`
-		Coordinates []*int  `json:"coordinates"`
+		Type        *string    `json:"type"`
+		Coordinates []*float64 `json:"coordinates"`
 	} `json:"coordinates"`
 	PlaceID *string `json:"place_id"`
 }
@@ -111,11 +172,12 @@ type TweetPublicMetrics struct {
 }
 
 type ReferencedTweet struct {
-	Type *string `json:"type"`
-	ID   *string `json:"id"`
+	Type *ReferencedTweetType `json:"type"`
+	ID   *string              `json:"id"`
 }
 
 type TweetWithheld struct {
 	Copyright    *bool     `json:"copyright"`
 	CountryCodes []*string `json:"country_codes"`
+	Scope        *string   `json:"scope,omitempty"`
 }
@@ -0,0 +1,100 @@
+package resources_test
+
+import (
+	"testing"
+
+	"github.com/michimani/gotwi/resources"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tweet_ReferencedTweets(t *testing.T) {
+	ref := func(typ resources.ReferencedTweetType, id string) resources.ReferencedTweet {
+		return resources.ReferencedTweet{Type: &typ, ID: str(id)}
+	}
+
+	cases := []struct {
+		name            string
+		tweet           *resources.Tweet
+		expectRetweet   bool
+		expectQuote     bool
+		expectReply     bool
+		expectRetweeted string
+		expectQuoted    string
+		expectRepliedTo string
+	}{
+		{
+			name:            "retweet",
+			tweet:           &resources.Tweet{ReferencedTweets: []resources.ReferencedTweet{ref(resources.ReferencedTweetTypeRetweeted, "1")}},
+			expectRetweet:   true,
+			expectRetweeted: "1",
+		},
+		{
+			name: "quote reply",
+			tweet: &resources.Tweet{ReferencedTweets: []resources.ReferencedTweet{
+				ref(resources.ReferencedTweetTypeQuoted, "2"),
+				ref(resources.ReferencedTweetTypeRepliedTo, "3"),
+			}},
+			expectQuote:     true,
+			expectReply:     true,
+			expectQuoted:    "2",
+			expectRepliedTo: "3",
+		},
+		{
+			name:  "no referenced tweets",
+			tweet: &resources.Tweet{},
+		},
+		{
+			name:  "nil tweet",
+			tweet: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			assert.Equal(tt, c.expectRetweet, c.tweet.IsRetweet())
+			assert.Equal(tt, c.expectQuote, c.tweet.IsQuote())
+			assert.Equal(tt, c.expectReply, c.tweet.IsReply())
+			assertStringPtr(tt, c.expectRetweeted, c.tweet.RetweetedTweetID())
+			assertStringPtr(tt, c.expectQuoted, c.tweet.QuotedTweetID())
+			assertStringPtr(tt, c.expectRepliedTo, c.tweet.RepliedToTweetID())
+		})
+	}
+}
+
+func Test_Enums_Valid(t *testing.T) {
+	cases := []struct {
+		name   string
+		value  interface{ Valid() bool }
+		expect bool
+	}{
+		{name: "reply settings: everyone", value: resources.ReplySettingsEveryone, expect: true},
+		{name: "reply settings: mentionedUsers", value: resources.ReplySettingsMentionedUsers, expect: true},
+		{name: "reply settings: following", value: resources.ReplySettingsFollowing, expect: true},
+		{name: "reply settings: invalid", value: resources.ReplySettings("mentioned_users"), expect: false},
+		{name: "referenced tweet type: retweeted", value: resources.ReferencedTweetTypeRetweeted, expect: true},
+		{name: "referenced tweet type: quoted", value: resources.ReferencedTweetTypeQuoted, expect: true},
+		{name: "referenced tweet type: replied_to", value: resources.ReferencedTweetTypeRepliedTo, expect: true},
+		{name: "referenced tweet type: invalid", value: resources.ReferencedTweetType("reply"), expect: false},
+		{name: "media type: photo", value: resources.MediaTypePhoto, expect: true},
+		{name: "media type: video", value: resources.MediaTypeVideo, expect: true},
+		{name: "media type: animated_gif", value: resources.MediaTypeAnimatedGIF, expect: true},
+		{name: "media type: invalid", value: resources.MediaType("gif"), expect: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			assert.Equal(tt, c.expect, c.value.Valid())
+		})
+	}
+}
+
+func assertStringPtr(t *testing.T, expect string, actual *string) {
+	t.Helper()
+	if expect == "" {
+		assert.Nil(t, actual)
+		return
+	}
+	if assert.NotNil(t, actual) {
+		assert.Equal(t, expect, *actual)
+	}
+}
@@ -25,26 +25,22 @@ type UserEntities struct {
 }
 
 type UserURL struct {
-	URLs []struct {
-		Start       *int    `json:"start"`
-		End         *int    `json:"end"`
-		URL         *string `json:"url"`
-		ExpandedURL *string `json:"expanded_url"`
-		DisplayURL  *string `json:"display_url"`
-	} `json:"urls"`
+	URLs []UserEntityURL `json:"urls"`
 }
 
 type UserDescription struct {
-	URLs []struct {
-		Start       *int    `json:"start"`
-		End         *int    `json:"end"`
-		URL         *string `json:"url"`
-		ExpandedURL *string `json:"expanded_url"`
-		DisplayURL  *string `json:"display_url"`
-	} `json:"urls"`
-	HashTags []UserEntityTag `json:"hashtags"`
-	Mentions []UserEntities  `json:"mentions"`
-	CashTags []UserEntityTag `json:"cashtags"`
+	URLs     []UserEntityURL     `json:"urls"`
+	HashTags []UserEntityTag     `json:"hashtags"`
+	Mentions []UserEntityMention `json:"mentions"`
+	CashTags []UserEntityTag     `json:"cashtags"`
+}
+
+type UserEntityURL struct {
+	Start       *int    `json:"start"`
+	End         *int    `json:"end"`
+	URL         *string `json:"url"`
+	ExpandedURL *string `json:"expanded_url"`
+	DisplayURL  *string `json:"display_url"`
 }
 
 type UserEntityTag struct {
@@ -53,6 +49,12 @@ type UserEntityTag struct {
 	Tag   *string `json:"tag"`
 }
 
+type UserEntityMention struct {
+	Start    *int    `json:"start"`
+	End      *int    `json:"end"`
+	Username *string `json:"username"`
+}
+
 type UserPublicMetrics struct {
 	FollowersCount *int `json:"followers_count"`
 	FollowingCount *int `json:"following_count"`
@@ -63,4 +65,5 @@ type UserPublicMetrics struct {
 type UserWithheld struct {
 	Copyright    *bool     `json:"copyright"`
 	CountryCodes []*string `json:"country_codes"`
+	Scope        *string   `json:"scope,omitempty"`
 }
@@ -0,0 +1,467 @@
+// Code generated by go run ./internal/gen. DO NOT EDIT.
+
+package service
+
+import (
+	"context"
+
+	"github.com/michimani/gotwi/lists"
+	liststypes "github.com/michimani/gotwi/lists/types"
+	"github.com/michimani/gotwi/spaces"
+	spacestypes "github.com/michimani/gotwi/spaces/types"
+	"github.com/michimani/gotwi/tweets"
+	tweetstypes "github.com/michimani/gotwi/tweets/types"
+	"github.com/michimani/gotwi/users"
+	userstypes "github.com/michimani/gotwi/users/types"
+)
+
+// TweetsAPI is the API of the tweets package.
+type TweetsAPI interface {
+	// Return a list of rules currently active on the streaming endpoint, either as a list or individually.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/get-tweets-search-stream-rules
+	FilteredStreamRulesGet(ctx context.Context, p *tweetstypes.FilteredStreamRulesGetParams) (*tweetstypes.FilteredStreamRulesGetResponse, error)
+	// Add or delete rules to your stream.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/post-tweets-search-stream-rules
+	FilteredStreamRulesPost(ctx context.Context, p *tweetstypes.FilteredStreamRulesPostParams) (*tweetstypes.FilteredStreamRulesPostResponse, error)
+	// Streams Tweets in real-time based on a specific set of filter rules.
+	// The stream is read with Next until it returns false, and must be closed.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/get-tweets-search-stream
+	FilteredStreamSearch(ctx context.Context, p *tweetstypes.FilteredStreamSearchParams) (*tweets.FilteredStream, error)
+	// Hides or unhides a reply to a Tweet.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/hide-replies/api-reference/put-tweets-id-hidden
+	HideReplies(ctx context.Context, p *tweetstypes.HideRepliesParams) (*tweetstypes.HideRepliesResponse, error)
+	// Allows you to get information about a Tweet’s liking users.
+	// You will receive the most recent 100 users who liked the specified Tweet.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/likes/api-reference/get-tweets-id-liking_users
+	TweetLikesLikingUsers(ctx context.Context, p *tweetstypes.TweetLikesLikingUsersParams) (*tweetstypes.TweetLikesLikingUsersResponse, error)
+	// Allows you to get information about a user’s liked Tweets.
+	// The Tweets returned by this endpoint count towards the Project-level Tweet cap.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/likes/api-reference/get-users-id-liked_tweets
+	TweetLikesLikedTweets(ctx context.Context, p *tweetstypes.TweetLikesLikedTweetsParams) (*tweetstypes.TweetLikesLikedTweetsResponse, error)
+	// Causes the user ID identified in the path parameter to Like the target Tweet.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/likes/api-reference/post-users-id-likes
+	TweetLikesPost(ctx context.Context, p *tweetstypes.TweetLikesPostParams) (*tweetstypes.TweetLikesPostResponse, error)
+	// Allows a user or authenticated user ID to unlike a Tweet.
+	// The request succeeds with no action when the user sends
+	//  a request to a user they're not liking the Tweet or have already unliked the Tweet.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/likes/api-reference/delete-users-id-likes-tweet_id
+	TweetLikesDelete(ctx context.Context, p *tweetstypes.TweetLikesDeleteParams) (*tweetstypes.TweetLikesDeleteResponse, error)
+	// Creates a Tweet on behalf of an authenticated user.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/manage-tweets/api-reference/post-tweets
+	ManageTweetsPost(ctx context.Context, p *tweetstypes.ManageTweetsPostParams) (*tweetstypes.ManageTweetsPostResponse, error)
+	// Allows a user or authenticated user ID to delete a Tweet.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/manage-tweets/api-reference/delete-tweets-id
+	ManageTweetsDelete(ctx context.Context, p *tweetstypes.ManageTweetsDeleteParams) (*tweetstypes.ManageTweetsDeleteResponse, error)
+	// Allows you to get information about who has Retweeted a Tweet.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/retweets/api-reference/get-tweets-id-retweeted_by
+	TweetRetweetsRetweetedBy(ctx context.Context, p *tweetstypes.TweetRetweetsRetweetedByParams) (*tweetstypes.TweetRetweetsRetweetedByResponse, error)
+	// Causes the user ID identified in the path parameter to Retweet the target Tweet.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/retweets/api-reference/post-users-id-retweets
+	TweetRetweetsPost(ctx context.Context, p *tweetstypes.TweetRetweetsPostParams) (*tweetstypes.TweetRetweetsPostResponse, error)
+	// Allows a user or authenticated user ID to remove the Retweet of a Tweet.
+	// The request succeeds with no action when the user sends a request to a user
+	// they're not Retweeting the Tweet or have already removed the Retweet of.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/retweets/api-reference/delete-users-id-retweets-tweet_id
+	TweetRetweetsDelete(ctx context.Context, p *tweetstypes.TweetRetweetsDeleteParams) (*tweetstypes.TweetRetweetsDeleteResponse, error)
+	// The recent search endpoint returns Tweets from the last seven days that match a search query.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/search/api-reference/get-tweets-search-recent
+	SearchTweetsRecent(ctx context.Context, p *tweetstypes.SearchTweetsRecentParams) (*tweetstypes.SearchTweetsRecentResponse, error)
+	// This endpoint is only available to those users who have been approved for the Academic Research product track.
+	// The full-archive search endpoint returns the complete history of public Tweets matching a search query; since the first Tweet was created March 26, 2006.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/search/api-reference/get-tweets-search-all
+	SearchTweetsAll(ctx context.Context, p *tweetstypes.SearchTweetsAllParams) (*tweetstypes.SearchTweetsAllResponse, error)
+	// The recent Tweet counts endpoint returns count of Tweets from the last seven days that match a search query.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/counts/api-reference/get-tweets-counts-recent
+	TweetCountsRecent(ctx context.Context, p *tweetstypes.TweetCountsRecentParams) (*tweetstypes.TweetCountsRecentResponse, error)
+	// This endpoint is only available to those users who have been approved for the Academic Research product track.
+	// The full-archive search endpoint returns the complete history of public Tweets matching a search query; since the first Tweet was created March 26, 2006.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/counts/api-reference/get-tweets-counts-all
+	TweetCountsAll(ctx context.Context, p *tweetstypes.TweetCountsAllParams) (*tweetstypes.TweetCountsAllResponse, error)
+	// Returns a variety of information about the Tweet specified by the requested ID or list of IDs.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/lookup/api-reference/get-tweets
+	TweetLookup(ctx context.Context, p *tweetstypes.TweetLookupParams) (*tweetstypes.TweetLookupResponse, error)
+	// Returns a variety of information about a single Tweet specified by the requested ID.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/lookup/api-reference/get-tweets-id
+	TweetLookupID(ctx context.Context, p *tweetstypes.TweetLookupIDParams) (*tweetstypes.TweetLookupIDResponse, error)
+	// Returns Tweets composed by a single user, specified by the requested user ID.
+	// By default, the most recent ten Tweets are returned per request. Using pagination, the most recent 3,200 Tweets can be retrieved.
+	// The Tweets returned by this endpoint count towards the Project-level Tweet cap.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/timelines/api-reference/get-users-id-tweets
+	TweetTimelinesTweets(ctx context.Context, p *tweetstypes.TweetTimelinesTweetsParams) (*tweetstypes.TweetTimelinesTweetsResponse, error)
+	// Returns Tweets mentioning a single user specified by the requested user ID.
+	// By default, the most recent ten Tweets are returned per request. Using pagination, up to the most recent 800 Tweets can be retrieved.
+	// The Tweets returned by this endpoint count towards the Project-level Tweet cap.
+	// https://developer.twitter.com/en/docs/twitter-api/tweets/timelines/api-reference/get-users-id-mentions
+	TweetTimelinesMentions(ctx context.Context, p *tweetstypes.TweetTimelinesMentionsParams) (*tweetstypes.TweetTimelinesMentionsResponse, error)
+	// PostThread posts each part of the thread as a reply to the previous one.
+	// On failure, it returns the output with the IDs posted so far and a *ThreadError.
+	PostThread(ctx context.Context, p *tweets.PostThreadInput) (*tweets.PostThreadOutput, error)
+}
+
+// UsersAPI is the API of the users package.
+type UsersAPI interface {
+	// Returns a list of users who are blocked by the specified user ID.
+	// https://developer.twitter.com/en/docs/twitter-api/users/blocks/api-reference/get-users-blocking
+	BlocksBlockingGet(ctx context.Context, p *userstypes.BlocksBlockingGetParams) (*userstypes.BlocksBlockingGetResponse, error)
+	// Causes the user (in the path) to block the target user. The user (in the path) must match the user context authorizing the request.
+	// https://developer.twitter.com/en/docs/twitter-api/users/blocks/api-reference/post-users-user_id-blocking
+	BlocksBlockingPost(ctx context.Context, p *userstypes.BlocksBlockingPostParams) (*userstypes.BlocksBlockingPostResponse, error)
+	// Allows a user or authenticated user ID to unblock another user.
+	// The request succeeds with no action when the user sends a request to a user they're not blocking or have already unblocked.
+	// https://developer.twitter.com/en/docs/twitter-api/users/blocks/api-reference/delete-users-user_id-blocking
+	BlocksBlockingDelete(ctx context.Context, p *userstypes.BlocksBlockingDeleteParams) (*userstypes.BlocksBlockingDeleteResponse, error)
+	// Returns a list of users the specified user ID is following.
+	// https://developer.twitter.com/en/docs/twitter-api/users/follows/api-reference/get-users-id-following
+	FollowsFollowingGet(ctx context.Context, p *userstypes.FollowsFollowingGetParams) (*userstypes.FollowsFollowingGetResponse, error)
+	// Returns a list of users who are followers of the specified user ID.
+	// https://developer.twitter.com/en/docs/twitter-api/users/follows/api-reference/get-users-id-followers
+	FollowsFollowers(ctx context.Context, p *userstypes.FollowsFollowersParams) (*userstypes.FollowsFollowersResponse, error)
+	// Allows a user ID to follow another user.
+	// If the target user does not have public Tweets, this endpoint will send a follow request.
+	// The request succeeds with no action when the authenticated user sends a request to a user
+	// they're already following, or if they're sending a follower request to a user that does not have public Tweets.
+	// https://developer.twitter.com/en/docs/twitter-api/users/follows/api-reference/post-users-source_user_id-following
+	FollowsFollowingPost(ctx context.Context, p *userstypes.FollowsFollowingPostParams) (*userstypes.FollowsFollowingPostResponse, error)
+	// Allows a user ID to unfollow another user.
+	// The request succeeds with no action when the authenticated user sends a request to a user they're not following or have already unfollowed.
+	// https://developer.twitter.com/en/docs/twitter-api/users/follows/api-reference/delete-users-source_id-following
+	FollowsFollowingDelete(ctx context.Context, p *userstypes.FollowsFollowingDeleteParams) (*userstypes.FollowsFollowingDeleteResponse, error)
+	// Returns a list of users who are muted by the specified user ID.
+	// https://developer.twitter.com/en/docs/twitter-api/users/mutes/api-reference/get-users-muting
+	MutesMutingGet(ctx context.Context, p *userstypes.MutesMutingGetParams) (*userstypes.MutesMutingGetResponse, error)
+	// Allows an authenticated user ID to mute the target user.
+	// https://developer.twitter.com/en/docs/twitter-api/users/mutes/api-reference/post-users-user_id-muting
+	MutesMutingPost(ctx context.Context, p *userstypes.MutesMutingPostParams) (*userstypes.MutesMutingPostResponse, error)
+	// Allows an authenticated user ID to unmute the target user.
+	// The request succeeds with no action when the user sends a request to a user they're not muting or have already unmuted.
+	// https://developer.twitter.com/en/docs/twitter-api/users/mutes/api-reference/delete-users-user_id-muting
+	MutesMutingDelete(ctx context.Context, p *userstypes.MutesMutingDeleteParams) (*userstypes.MutesMutingDeleteResponse, error)
+	// Returns a variety of information about one or more users specified by the requested IDs.
+	// https://developer.twitter.com/en/docs/twitter-api/users/lookup/api-reference/get-users
+	UserLookup(ctx context.Context, p *userstypes.UserLookupParams) (*userstypes.UserLookupResponse, error)
+	// Returns a variety of information about a single user specified by the requested ID.
+	// https://developer.twitter.com/en/docs/twitter-api/users/lookup/api-reference/get-users-id
+	UserLookupID(ctx context.Context, p *userstypes.UserLookupIDParams) (*userstypes.UserLookupIDResponse, error)
+	// Returns a variety of information about one or more users specified by their usernames.
+	// https://developer.twitter.com/en/docs/twitter-api/users/lookup/api-reference/get-users-by
+	UserLookupBy(ctx context.Context, p *userstypes.UserLookupByParams) (*userstypes.UserLookupByResponse, error)
+	// Returns a variety of information about one or more users specified by their usernames.
+	// https://developer.twitter.com/en/docs/twitter-api/users/lookup/api-reference/get-users-by-username-username
+	UserLookupByUsername(ctx context.Context, p *userstypes.UserLookupByUsernameParams) (*userstypes.UserLookupByUsernameResponse, error)
+	// Returns information about an authorized user.
+	// https://developer.twitter.com/en/docs/twitter-api/users/lookup/api-reference/get-users-me
+	UserLookupMe(ctx context.Context, p *userstypes.UserLookupMeParams) (*userstypes.UserLookupMeResponse, error)
+}
+
+// ListsAPI is the API of the lists package.
+type ListsAPI interface {
+	// Returns a list of users who are followers of the specified List.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/list-follows/api-reference/get-lists-id-followers
+	ListFollowsFollowers(ctx context.Context, p *liststypes.ListFollowsFollowersParams) (*liststypes.ListFollowsFollowersResponse, error)
+	// Returns all Lists a specified user follows.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/list-follows/api-reference/get-users-id-followed_lists
+	ListFollowsFollowedLists(ctx context.Context, p *liststypes.ListFollowsFollowedListsParams) (*liststypes.ListFollowsFollowedListsResponse, error)
+	// Enables the authenticated user to follow a List.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/manage-lists/api-reference/post-users-id-followed-lists
+	ListFollowsPost(ctx context.Context, p *liststypes.ListFollowsPostParams) (*liststypes.ListFollowsPostResponse, error)
+	// Enables the authenticated user to unfollow a List.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/manage-lists/api-reference/delete-users-id-followed-lists-list_id
+	ListFollowsDelete(ctx context.Context, p *liststypes.ListFollowsDeleteParams) (*liststypes.ListFollowsDeleteResponse, error)
+	// Returns the details of a specified List.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/list-lookup/api-reference/get-lists-id
+	ListLookupID(ctx context.Context, p *liststypes.ListLookupIDParams) (*liststypes.ListLookupIDResponse, error)
+	// Returns all Lists owned by the specified user.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/list-lookup/api-reference/get-users-id-owned_lists
+	ListLookupOwnedLists(ctx context.Context, p *liststypes.ListLookupOwnedListsParams) (*liststypes.ListLookupOwnedListsResponse, error)
+	// Returns all Lists a specified user is a member of.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/list-members/api-reference/get-users-id-list_memberships
+	ListMembersListMemberships(ctx context.Context, p *liststypes.ListMembersListMembershipsParams) (*liststypes.ListMembersListMembershipsResponse, error)
+	// Returns a list of users who are members of the specified List.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/list-members/api-reference/get-lists-id-members
+	ListMembersGet(ctx context.Context, p *liststypes.ListMembersGetParams) (*liststypes.ListMembersGetResponse, error)
+	// Enables the authenticated user to add a member to a List they own.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/manage-lists/api-reference/post-lists-id-members
+	ListMembersPost(ctx context.Context, p *liststypes.ListMembersPostParams) (*liststypes.ListMembersPostResponse, error)
+	// Enables the authenticated user to remove a member from a List they own.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/manage-lists/api-reference/delete-lists-id-members-user_id
+	ListMembersDelete(ctx context.Context, p *liststypes.ListMembersDeleteParams) (*liststypes.ListMembersDeleteResponse, error)
+	// Returns a list of Tweets from the specified List.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/list-tweets/api-reference/get-lists-id-tweets
+	ListTweetsLookup(ctx context.Context, p *liststypes.ListTweetsLookupParams) (*liststypes.ListTweetsLookupResponse, error)
+	// Enables the authenticated user to create a List.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/manage-lists/api-reference/post-lists
+	ManageListsPost(ctx context.Context, p *liststypes.ManageListsPostParams) (*liststypes.ManageListsPostResponse, error)
+	// Enables the authenticated user to update the meta data of a specified List that they own.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/manage-lists/api-reference/put-lists-id
+	ManageListsPut(ctx context.Context, p *liststypes.ManageListsPutParams) (*liststypes.ManageListsPutResponse, error)
+	// Enables the authenticated user to delete a List that they own.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/manage-lists/api-reference/delete-lists-id
+	ManageListsDelete(ctx context.Context, p *liststypes.ManageListsDeleteParams) (*liststypes.ManageListsDeleteResponse, error)
+	// Returns the Lists pinned by a specified user.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/pinned-lists/api-reference/get-users-id-pinned_lists
+	PinnedListsGet(ctx context.Context, p *liststypes.PinnedListsGetParams) (*liststypes.PinnedListsGetResponse, error)
+	// Enables the authenticated user to pin a List.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/manage-lists/api-reference/post-users-id-pinned-lists
+	PinnedListsPost(ctx context.Context, p *liststypes.PinnedListsPostParams) (*liststypes.PinnedListsPostResponse, error)
+	// Enables the authenticated user to unpin a List.
+	// https://developer.twitter.com/en/docs/twitter-api/lists/manage-lists/api-reference/delete-users-id-pinned-lists-list_id
+	PinnedListsDelete(ctx context.Context, p *liststypes.PinnedListsDeleteParams) (*liststypes.PinnedListsDeleteResponse, error)
+}
+
+// SpacesAPI is the API of the spaces package.
+type SpacesAPI interface {
+	// Return live or scheduled Spaces matching your specified search terms.
+	// This endpoint performs a keyword search, meaning that it will return Spaces
+	// that are an exact case-insensitive match of the specified search term. The search term will match the original title of the Space.
+	// https://developer.twitter.com/en/docs/twitter-api/spaces/search/api-reference/get-spaces-search
+	SearchSpaces(ctx context.Context, p *spacestypes.SearchSpacesParams) (*spacestypes.SearchSpacesResponse, error)
+	// Returns a variety of information about a single Space specified by the requested ID.
+	// https://developer.twitter.com/en/docs/twitter-api/spaces/lookup/api-reference/get-spaces-id
+	SpacesLookupID(ctx context.Context, p *spacestypes.SpacesLookupIDParams) (*spacestypes.SpacesLookupIDResponse, error)
+	// Returns details about multiple Spaces. Up to 100 comma-separated Spaces IDs can be looked up using this endpoint
+	// https://developer.twitter.com/en/docs/twitter-api/spaces/lookup/api-reference/get-spaces
+	SpacesLookup(ctx context.Context, p *spacestypes.SpacesLookupParams) (*spacestypes.SpacesLookupResponse, error)
+	// Returns live or scheduled Spaces created by the specified user IDs.
+	// Up to 100 comma-separated IDs can be looked up using this endpoint.
+	// https://developer.twitter.com/en/docs/twitter-api/spaces/lookup/api-reference/get-spaces-by-creator-ids#Optional
+	SpacesLookupByCreatorIDs(ctx context.Context, p *spacestypes.SpacesLookupByCreatorIDsParams) (*spacestypes.SpacesLookupByCreatorIDsResponse, error)
+}
+
+var (
+	_ TweetsAPI = (*Service)(nil)
+	_ UsersAPI  = (*Service)(nil)
+	_ ListsAPI  = (*Service)(nil)
+	_ SpacesAPI = (*Service)(nil)
+)
+
+func (s *Service) FilteredStreamRulesGet(ctx context.Context, p *tweetstypes.FilteredStreamRulesGetParams) (*tweetstypes.FilteredStreamRulesGetResponse, error) {
+	return tweets.FilteredStreamRulesGet(ctx, s.client, p)
+}
+
+func (s *Service) FilteredStreamRulesPost(ctx context.Context, p *tweetstypes.FilteredStreamRulesPostParams) (*tweetstypes.FilteredStreamRulesPostResponse, error) {
+	return tweets.FilteredStreamRulesPost(ctx, s.client, p)
+}
+
+func (s *Service) FilteredStreamSearch(ctx context.Context, p *tweetstypes.FilteredStreamSearchParams) (*tweets.FilteredStream, error) {
+	return tweets.FilteredStreamSearch(ctx, s.client, p)
+}
+
+func (s *Service) HideReplies(ctx context.Context, p *tweetstypes.HideRepliesParams) (*tweetstypes.HideRepliesResponse, error) {
+	return tweets.HideReplies(ctx, s.client, p)
+}
+
+func (s *Service) TweetLikesLikingUsers(ctx context.Context, p *tweetstypes.TweetLikesLikingUsersParams) (*tweetstypes.TweetLikesLikingUsersResponse, error) {
+	return tweets.TweetLikesLikingUsers(ctx, s.client, p)
+}
+
+func (s *Service) TweetLikesLikedTweets(ctx context.Context, p *tweetstypes.TweetLikesLikedTweetsParams) (*tweetstypes.TweetLikesLikedTweetsResponse, error) {
+	return tweets.TweetLikesLikedTweets(ctx, s.client, p)
+}
+
+func (s *Service) TweetLikesPost(ctx context.Context, p *tweetstypes.TweetLikesPostParams) (*tweetstypes.TweetLikesPostResponse, error) {
+	return tweets.TweetLikesPost(ctx, s.client, p)
+}
+
+func (s *Service) TweetLikesDelete(ctx context.Context, p *tweetstypes.TweetLikesDeleteParams) (*tweetstypes.TweetLikesDeleteResponse, error) {
+	return tweets.TweetLikesDelete(ctx, s.client, p)
+}
+
+func (s *Service) ManageTweetsPost(ctx context.Context, p *tweetstypes.ManageTweetsPostParams) (*tweetstypes.ManageTweetsPostResponse, error) {
+	return tweets.ManageTweetsPost(ctx, s.client, p)
+}
+
+func (s *Service) ManageTweetsDelete(ctx context.Context, p *tweetstypes.ManageTweetsDeleteParams) (*tweetstypes.ManageTweetsDeleteResponse, error) {
+	return tweets.ManageTweetsDelete(ctx, s.client, p)
+}
+
+func (s *Service) TweetRetweetsRetweetedBy(ctx context.Context, p *tweetstypes.TweetRetweetsRetweetedByParams) (*tweetstypes.TweetRetweetsRetweetedByResponse, error) {
+	return tweets.TweetRetweetsRetweetedBy(ctx, s.client, p)
+}
+
+func (s *Service) TweetRetweetsPost(ctx context.Context, p *tweetstypes.TweetRetweetsPostParams) (*tweetstypes.TweetRetweetsPostResponse, error) {
+	return tweets.TweetRetweetsPost(ctx, s.client, p)
+}
+
+func (s *Service) TweetRetweetsDelete(ctx context.Context, p *tweetstypes.TweetRetweetsDeleteParams) (*tweetstypes.TweetRetweetsDeleteResponse, error) {
+	return tweets.TweetRetweetsDelete(ctx, s.client, p)
+}
+
+func (s *Service) SearchTweetsRecent(ctx context.Context, p *tweetstypes.SearchTweetsRecentParams) (*tweetstypes.SearchTweetsRecentResponse, error) {
+	return tweets.SearchTweetsRecent(ctx, s.client, p)
+}
+
+func (s *Service) SearchTweetsAll(ctx context.Context, p *tweetstypes.SearchTweetsAllParams) (*tweetstypes.SearchTweetsAllResponse, error) {
+	return tweets.SearchTweetsAll(ctx, s.client, p)
+}
+
+func (s *Service) TweetCountsRecent(ctx context.Context, p *tweetstypes.TweetCountsRecentParams) (*tweetstypes.TweetCountsRecentResponse, error) {
+	return tweets.TweetCountsRecent(ctx, s.client, p)
+}
+
+func (s *Service) TweetCountsAll(ctx context.Context, p *tweetstypes.TweetCountsAllParams) (*tweetstypes.TweetCountsAllResponse, error) {
+	return tweets.TweetCountsAll(ctx, s.client, p)
+}
+
+func (s *Service) TweetLookup(ctx context.Context, p *tweetstypes.TweetLookupParams) (*tweetstypes.TweetLookupResponse, error) {
+	return tweets.TweetLookup(ctx, s.client, p)
+}
+
+func (s *Service) TweetLookupID(ctx context.Context, p *tweetstypes.TweetLookupIDParams) (*tweetstypes.TweetLookupIDResponse, error) {
+	return tweets.TweetLookupID(ctx, s.client, p)
+}
+
+func (s *Service) TweetTimelinesTweets(ctx context.Context, p *tweetstypes.TweetTimelinesTweetsParams) (*tweetstypes.TweetTimelinesTweetsResponse, error) {
+	return tweets.TweetTimelinesTweets(ctx, s.client, p)
+}
+
+func (s *Service) TweetTimelinesMentions(ctx context.Context, p *tweetstypes.TweetTimelinesMentionsParams) (*tweetstypes.TweetTimelinesMentionsResponse, error) {
+	return tweets.TweetTimelinesMentions(ctx, s.client, p)
+}
+
+func (s *Service) PostThread(ctx context.Context, p *tweets.PostThreadInput) (*tweets.PostThreadOutput, error) {
+	return tweets.PostThread(ctx, s.client, p)
+}
+
+func (s *Service) BlocksBlockingGet(ctx context.Context, p *userstypes.BlocksBlockingGetParams) (*userstypes.BlocksBlockingGetResponse, error) {
+	return users.BlocksBlockingGet(ctx, s.client, p)
+}
+
+func (s *Service) BlocksBlockingPost(ctx context.Context, p *userstypes.BlocksBlockingPostParams) (*userstypes.BlocksBlockingPostResponse, error) {
+	return users.BlocksBlockingPost(ctx, s.client, p)
+}
+
+func (s *Service) BlocksBlockingDelete(ctx context.Context, p *userstypes.BlocksBlockingDeleteParams) (*userstypes.BlocksBlockingDeleteResponse, error) {
+	return users.BlocksBlockingDelete(ctx, s.client, p)
+}
+
+func (s *Service) FollowsFollowingGet(ctx context.Context, p *userstypes.FollowsFollowingGetParams) (*userstypes.FollowsFollowingGetResponse, error) {
+	return users.FollowsFollowingGet(ctx, s.client, p)
+}
+
+func (s *Service) FollowsFollowers(ctx context.Context, p *userstypes.FollowsFollowersParams) (*userstypes.FollowsFollowersResponse, error) {
+	return users.FollowsFollowers(ctx, s.client, p)
+}
+
+func (s *Service) FollowsFollowingPost(ctx context.Context, p *userstypes.FollowsFollowingPostParams) (*userstypes.FollowsFollowingPostResponse, error) {
+	return users.FollowsFollowingPost(ctx, s.client, p)
+}
+
+func (s *Service) FollowsFollowingDelete(ctx context.Context, p *userstypes.FollowsFollowingDeleteParams) (*userstypes.FollowsFollowingDeleteResponse, error) {
+	return users.FollowsFollowingDelete(ctx, s.client, p)
+}
+
+func (s *Service) MutesMutingGet(ctx context.Context, p *userstypes.MutesMutingGetParams) (*userstypes.MutesMutingGetResponse, error) {
+	return users.MutesMutingGet(ctx, s.client, p)
+}
+
+func (s *Service) MutesMutingPost(ctx context.Context, p *userstypes.MutesMutingPostParams) (*userstypes.MutesMutingPostResponse, error) {
+	return users.MutesMutingPost(ctx, s.client, p)
+}
+
+func (s *Service) MutesMutingDelete(ctx context.Context, p *userstypes.MutesMutingDeleteParams) (*userstypes.MutesMutingDeleteResponse, error) {
+	return users.MutesMutingDelete(ctx, s.client, p)
+}
+
+func (s *Service) UserLookup(ctx context.Context, p *userstypes.UserLookupParams) (*userstypes.UserLookupResponse, error) {
+	return users.UserLookup(ctx, s.client, p)
+}
+
+func (s *Service) UserLookupID(ctx context.Context, p *userstypes.UserLookupIDParams) (*userstypes.UserLookupIDResponse, error) {
+	return users.UserLookupID(ctx, s.client, p)
+}
+
+func (s *Service) UserLookupBy(ctx context.Context, p *userstypes.UserLookupByParams) (*userstypes.UserLookupByResponse, error) {
+	return users.UserLookupBy(ctx, s.client, p)
+}
+
+func (s *Service) UserLookupByUsername(ctx context.Context, p *userstypes.UserLookupByUsernameParams) (*userstypes.UserLookupByUsernameResponse, error) {
+	return users.UserLookupByUsername(ctx, s.client, p)
+}
+
+func (s *Service) UserLookupMe(ctx context.Context, p *userstypes.UserLookupMeParams) (*userstypes.UserLookupMeResponse, error) {
+	return users.UserLookupMe(ctx, s.client, p)
+}
+
+func (s *Service) ListFollowsFollowers(ctx context.Context, p *liststypes.ListFollowsFollowersParams) (*liststypes.ListFollowsFollowersResponse, error) {
+	return lists.ListFollowsFollowers(ctx, s.client, p)
+}
+
+func (s *Service) ListFollowsFollowedLists(ctx context.Context, p *liststypes.ListFollowsFollowedListsParams) (*liststypes.ListFollowsFollowedListsResponse, error) {
+	return lists.ListFollowsFollowedLists(ctx, s.client, p)
+}
+
+func (s *Service) ListFollowsPost(ctx context.Context, p *liststypes.ListFollowsPostParams) (*liststypes.ListFollowsPostResponse, error) {
+	return lists.ListFollowsPost(ctx, s.client, p)
+}
+
+func (s *Service) ListFollowsDelete(ctx context.Context, p *liststypes.ListFollowsDeleteParams) (*liststypes.ListFollowsDeleteResponse, error) {
+	return lists.ListFollowsDelete(ctx, s.client, p)
+}
+
+func (s *Service) ListLookupID(ctx context.Context, p *liststypes.ListLookupIDParams) (*liststypes.ListLookupIDResponse, error) {
+	return lists.ListLookupID(ctx, s.client, p)
+}
+
+func (s *Service) ListLookupOwnedLists(ctx context.Context, p *liststypes.ListLookupOwnedListsParams) (*liststypes.ListLookupOwnedListsResponse, error) {
+	return lists.ListLookupOwnedLists(ctx, s.client, p)
+}
+
+func (s *Service) ListMembersListMemberships(ctx context.Context, p *liststypes.ListMembersListMembershipsParams) (*liststypes.ListMembersListMembershipsResponse, error) {
+	return lists.ListMembersListMemberships(ctx, s.client, p)
+}
+
+func (s *Service) ListMembersGet(ctx context.Context, p *liststypes.ListMembersGetParams) (*liststypes.ListMembersGetResponse, error) {
+	return lists.ListMembersGet(ctx, s.client, p)
+}
+
+func (s *Service) ListMembersPost(ctx context.Context, p *liststypes.ListMembersPostParams) (*liststypes.ListMembersPostResponse, error) {
+	return lists.ListMembersPost(ctx, s.client, p)
+}
+
+func (s *Service) ListMembersDelete(ctx context.Context, p *liststypes.ListMembersDeleteParams) (*liststypes.ListMembersDeleteResponse, error) {
+	return lists.ListMembersDelete(ctx, s.client, p)
+}
+
+func (s *Service) ListTweetsLookup(ctx context.Context, p *liststypes.ListTweetsLookupParams) (*liststypes.ListTweetsLookupResponse, error) {
+	return lists.ListTweetsLookup(ctx, s.client, p)
+}
+
+func (s *Service) ManageListsPost(ctx context.Context, p *liststypes.ManageListsPostParams) (*liststypes.ManageListsPostResponse, error) {
+	return lists.ManageListsPost(ctx, s.client, p)
+}
+
+func (s *Service) ManageListsPut(ctx context.Context, p *liststypes.ManageListsPutParams) (*liststypes.ManageListsPutResponse, error) {
+	return lists.ManageListsPut(ctx, s.client, p)
+}
+
+func (s *Service) ManageListsDelete(ctx context.Context, p *liststypes.ManageListsDeleteParams) (*liststypes.ManageListsDeleteResponse, error) {
+	return lists.ManageListsDelete(ctx, s.client, p)
+}
+
+func (s *Service) PinnedListsGet(ctx context.Context, p *liststypes.PinnedListsGetParams) (*liststypes.PinnedListsGetResponse, error) {
+	return lists.PinnedListsGet(ctx, s.client, p)
+}
+
+func (s *Service) PinnedListsPost(ctx context.Context, p *liststypes.PinnedListsPostParams) (*liststypes.PinnedListsPostResponse, error) {
+	return lists.PinnedListsPost(ctx, s.client, p)
+}
+
+func (s *Service) PinnedListsDelete(ctx context.Context, p *liststypes.PinnedListsDeleteParams) (*liststypes.PinnedListsDeleteResponse, error) {
+	return lists.PinnedListsDelete(ctx, s.client, p)
+}
+
+func (s *Service) SearchSpaces(ctx context.Context, p *spacestypes.SearchSpacesParams) (*spacestypes.SearchSpacesResponse, error) {
+	return spaces.SearchSpaces(ctx, s.client, p)
+}
+
+func (s *Service) SpacesLookupID(ctx context.Context, p *spacestypes.SpacesLookupIDParams) (*spacestypes.SpacesLookupIDResponse, error) {
+	return spaces.SpacesLookupID(ctx, s.client, p)
+}
+
+func (s *Service) SpacesLookup(ctx context.Context, p *spacestypes.SpacesLookupParams) (*spacestypes.SpacesLookupResponse, error) {
+	return spaces.SpacesLookup(ctx, s.client, p)
+}
+
+func (s *Service) SpacesLookupByCreatorIDs(ctx context.Context, p *spacestypes.SpacesLookupByCreatorIDsParams) (*spacestypes.SpacesLookupByCreatorIDsResponse, error) {
+	return spaces.SpacesLookupByCreatorIDs(ctx, s.client, p)
+}
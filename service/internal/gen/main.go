@@ -0,0 +1,253 @@
+// Command gen generates the interfaces of the API functions of the tweets, users, lists and spaces packages,
+// their implementation by service.Service, and the mock of servicemock.
+// It is run by go generate in the service directory.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+type pkg struct {
+	Name       string // such as tweets
+	Interface  string // such as TweetsAPI
+	TypesAlias string // such as tweetstypes
+	Methods    []method
+	// Exported is true if the types of the methods have the types of the package, such as tweets.FilteredStream.
+	Exported bool
+}
+
+type method struct {
+	Name   string
+	Doc    []string
+	Param  string // such as *tweetstypes.TweetLookupParams
+	Result string // such as *tweetstypes.TweetLookupResponse
+	Pkg    string
+}
+
+var packages = []*pkg{
+	{Name: "tweets", Interface: "TweetsAPI", TypesAlias: "tweetstypes"},
+	{Name: "users", Interface: "UsersAPI", TypesAlias: "userstypes"},
+	{Name: "lists", Interface: "ListsAPI", TypesAlias: "liststypes"},
+	{Name: "spaces", Interface: "SpacesAPI", TypesAlias: "spacestypes"},
+}
+
+func main() {
+	seen := map[string]string{}
+	for _, p := range packages {
+		if err := p.load(filepath.Join("..", p.Name)); err != nil {
+			log.Fatal(err)
+		}
+		for _, m := range p.Methods {
+			if other, ok := seen[m.Name]; ok {
+				log.Fatalf("%s is defined in both %s and %s", m.Name, other, p.Name)
+			}
+			seen[m.Name] = p.Name
+		}
+	}
+
+	if err := generate("api_gen.go", "api", apiTemplate); err != nil {
+		log.Fatal(err)
+	}
+	if err := generate(filepath.Join("servicemock", "mock_gen.go"), "mock", mockTemplate); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// load reads the functions such as `func X(ctx context.Context, c *gotwi.GotwiClient, p P) (R, error)`.
+func (p *pkg) load(dir string) error {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		for _, d := range f.Decls {
+			fn, ok := d.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !fn.Name.IsExported() || !p.isAPI(fn.Type) {
+				continue
+			}
+
+			m := method{
+				Name:   fn.Name.Name,
+				Param:  p.typeString(fn.Type.Params.List[2].Type),
+				Result: p.typeString(fn.Type.Results.List[0].Type),
+				Pkg:    p.Name,
+			}
+			if fn.Doc != nil {
+				m.Doc = strings.Split(strings.TrimSpace(fn.Doc.Text()), "\n")
+			}
+			p.Methods = append(p.Methods, m)
+			p.Exported = p.Exported || strings.Contains(m.Param+m.Result, p.Name+".")
+		}
+	}
+	return nil
+}
+
+func (p *pkg) isAPI(t *ast.FuncType) bool {
+	if len(t.Params.List) != 3 || t.Results == nil || len(t.Results.List) != 2 {
+		return false
+	}
+	for _, f := range t.Params.List {
+		if len(f.Names) != 1 {
+			return false
+		}
+	}
+	return p.typeString(t.Params.List[0].Type) == "context.Context" &&
+		p.typeString(t.Params.List[1].Type) == "*gotwi.GotwiClient" &&
+		p.typeString(t.Results.List[1].Type) == "error"
+}
+
+// typeString returns the type as in the service package, where types.X is such as tweetstypes.X,
+// and X of the package is such as tweets.X.
+func (p *pkg) typeString(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.StarExpr:
+		return "*" + p.typeString(t.X)
+	case *ast.SelectorExpr:
+		x := t.X.(*ast.Ident).Name
+		if x == "types" {
+			x = p.TypesAlias
+		}
+		return x + "." + t.Sel.Name
+	case *ast.Ident:
+		if t.IsExported() {
+			return p.Name + "." + t.Name
+		}
+		return t.Name
+	}
+	panic(fmt.Sprintf("unsupported type %T", e))
+}
+
+func generate(name, kind string, t *template.Template) error {
+	data := struct {
+		Imports  []string
+		Packages []*pkg
+	}{imports(kind), packages}
+
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, data); err != nil {
+		return err
+	}
+	b, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %w", name, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, b, 0o644)
+}
+
+// imports returns the imports of the packages, and the types packages. The mock imports the package only if Exported.
+func imports(kind string) []string {
+	l := []string{""}
+	for _, p := range packages {
+		if kind == "api" || p.Exported {
+			l = append(l, fmt.Sprintf("%q", "github.com/michimani/gotwi/"+p.Name))
+		}
+		l = append(l, fmt.Sprintf("%s %q", p.TypesAlias, "github.com/michimani/gotwi/"+p.Name+"/types"))
+	}
+	if kind == "mock" {
+		l = append(l, fmt.Sprintf("%q", "github.com/michimani/gotwi/service"))
+	}
+	return l
+}
+
+const header = `// Code generated by go run ./internal/gen. DO NOT EDIT.
+`
+
+// importsTemplate is the imports, where the first empty import separates the standard library.
+const importsTemplate = `
+import (
+	"context"
+{{range .Imports}}
+	{{.}}
+{{- end}}
+`
+
+var apiTemplate = template.Must(template.New("api").Parse(header + `
+package service
+` + importsTemplate + `)
+{{range .Packages}}
+// {{.Interface}} is the API of the {{.Name}} package.
+type {{.Interface}} interface {
+{{- range .Methods}}
+	{{- range .Doc}}
+	// {{.}}
+	{{- end}}
+	{{.Name}}(ctx context.Context, p {{.Param}}) ({{.Result}}, error)
+{{- end}}
+}
+{{end}}
+var (
+{{- range .Packages}}
+	_ {{.Interface}} = (*Service)(nil)
+{{- end}}
+)
+{{range .Packages}}{{range .Methods}}
+func (s *Service) {{.Name}}(ctx context.Context, p {{.Param}}) ({{.Result}}, error) {
+	return {{.Pkg}}.{{.Name}}(ctx, s.client, p)
+}
+{{end}}{{end}}`))
+
+var mockTemplate = template.Must(template.New("mock").Parse(header + `
+package servicemock
+` + importsTemplate + `)
+
+var (
+{{- range .Packages}}
+	_ service.{{.Interface}} = (*Mock)(nil)
+{{- end}}
+)
+
+// Funcs are the functions called by the methods of Mock instead of the canned responses, if they are set.
+type Funcs struct {
+{{- range .Packages}}{{range .Methods}}
+	{{.Name}} func(ctx context.Context, p {{.Param}}) ({{.Result}}, error)
+{{- end}}{{end}}
+}
+
+// methods are the names of the methods, which are checked by Return.
+var methods = map[string]bool{
+{{- range .Packages}}{{range .Methods}}
+	"{{.Name}}": true,
+{{- end}}{{end}}
+}
+{{range .Packages}}{{range .Methods}}
+func (m *Mock) {{.Name}}(ctx context.Context, p {{.Param}}) ({{.Result}}, error) {
+	m.record("{{.Name}}", p)
+	if f := m.Funcs.{{.Name}}; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("{{.Name}}")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.({{.Result}})
+	if !ok {
+		return nil, wrongType("{{.Name}}", res, ({{.Result}})(nil))
+	}
+	return r, err
+}
+{{end}}{{end}}`))
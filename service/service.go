@@ -0,0 +1,46 @@
+// Package service provides the interfaces of the API functions of the tweets, users, lists and spaces packages,
+// such as TweetsAPI, and Service, which implements them with GotwiClient.
+//
+// The code that depends on the interfaces instead of the package-level functions can be tested
+// with the mock of the servicemock package, without HTTP.
+//
+//	func CountFollowers(ctx context.Context, api service.UsersAPI, id string) (int, error) {
+//		res, err := api.FollowsFollowers(ctx, &types.FollowsFollowersParams{ID: id})
+//		...
+//	}
+//
+//	n, err := CountFollowers(ctx, service.New(c), "123")
+//
+// The interfaces and the methods are generated from the API functions by go generate.
+package service
+
+//go:generate go run ./internal/gen
+
+import (
+	"github.com/michimani/gotwi"
+)
+
+// API is all of the interfaces.
+type API interface {
+	TweetsAPI
+	UsersAPI
+	ListsAPI
+	SpacesAPI
+}
+
+// Service implements API by calling the API functions with the client.
+type Service struct {
+	client *gotwi.GotwiClient
+}
+
+var _ API = (*Service)(nil)
+
+// New returns Service with the client.
+func New(c *gotwi.GotwiClient) *Service {
+	return &Service{client: c}
+}
+
+// Client returns the client of Service.
+func (s *Service) Client() *gotwi.GotwiClient {
+	return s.client
+}
@@ -0,0 +1,34 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/gotwitest"
+	"github.com/michimani/gotwi/resources"
+	"github.com/michimani/gotwi/service"
+	tweetstypes "github.com/michimani/gotwi/tweets/types"
+	userstypes "github.com/michimani/gotwi/users/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Service(t *testing.T) {
+	s := gotwitest.NewServer()
+	defer s.Close()
+	alice := s.AddUser(resources.User{Username: gotwi.String("alice")})
+	c, err := s.NewUserClient(*alice.ID)
+	require.NoError(t, err)
+
+	var api service.API = service.New(c)
+	ctx := context.Background()
+
+	me, err := api.UserLookupMe(ctx, &userstypes.UserLookupMeParams{})
+	require.NoError(t, err)
+	assert.Equal(t, *alice.ID, gotwi.StringValue(me.Data.ID))
+
+	posted, err := api.ManageTweetsPost(ctx, &tweetstypes.ManageTweetsPostParams{Text: gotwi.String("hello")})
+	require.NoError(t, err)
+	assert.NotNil(t, s.Tweet(gotwi.StringValue(posted.Data.ID)))
+}
@@ -0,0 +1,112 @@
+// Package servicemock provides Mock, which implements the interfaces of the service package
+// with the canned responses and records the calls, for the unit tests of the code that uses them.
+//
+//	m := &servicemock.Mock{}
+//	m.Return("UserLookupMe", &types.UserLookupMeResponse{Data: resources.User{ID: gotwi.String("123")}}, nil)
+//
+//	res, err := m.UserLookupMe(ctx, &types.UserLookupMeParams{})
+//	calls := m.CallsOf("UserLookupMe") // the params of the calls
+//
+// The methods are generated by go generate in the service directory.
+package servicemock
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Call is a call of a method of Mock.
+type Call struct {
+	Method string
+	// Params is the params of the call, such as *types.UserLookupMeParams.
+	Params interface{}
+}
+
+// Mock implements service.API. A method calls the function of Funcs if it is set,
+// otherwise returns the canned responses of Return. The zero value is ready to use, and it is safe for concurrent use
+// while Funcs is not changed.
+type Mock struct {
+	Funcs Funcs
+
+	mu        sync.Mutex
+	calls     []Call
+	responses map[string][]cannedResponse
+}
+
+type cannedResponse struct {
+	res interface{}
+	err error
+}
+
+// Return adds the canned response of the method, such as "UserLookupMe".
+// The responses are returned in the order of the addition, and the last one is repeated.
+// res is the pointer of the response type of the method, such as *types.UserLookupMeResponse, or nil with err.
+// It panics if the method does not exist.
+func (m *Mock) Return(method string, res interface{}, err error) *Mock {
+	if !methods[method] {
+		panic(fmt.Sprintf("servicemock: %s is not a method of service.API", method))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.responses == nil {
+		m.responses = map[string][]cannedResponse{}
+	}
+	m.responses[method] = append(m.responses[method], cannedResponse{res: res, err: err})
+	return m
+}
+
+// Calls returns the calls in the order of the calls.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call{}, m.calls...)
+}
+
+// CallsOf returns the params of the calls of the method.
+func (m *Mock) CallsOf(method string) []interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l := []interface{}{}
+	for _, c := range m.calls {
+		if c.Method == method {
+			l = append(l, c.Params)
+		}
+	}
+	return l
+}
+
+// Reset removes the calls and the canned responses.
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+	m.responses = nil
+}
+
+func (m *Mock) record(method string, p interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Params: p})
+}
+
+// next returns the next canned response of the method, or an error if there is none.
+func (m *Mock) next(method string) (interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l := m.responses[method]
+	if len(l) == 0 {
+		return nil, fmt.Errorf("servicemock: no response for %s. Set it by Return or Funcs.", method)
+	}
+	r := l[0]
+	if len(l) > 1 {
+		m.responses[method] = l[1:]
+	}
+	return r.res, r.err
+}
+
+func wrongType(method string, res, expected interface{}) error {
+	return fmt.Errorf("servicemock: the response for %s is %T, but it must be %T.", method, res, expected)
+}
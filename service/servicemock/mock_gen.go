@@ -0,0 +1,1073 @@
+// Code generated by go run ./internal/gen. DO NOT EDIT.
+
+package servicemock
+
+import (
+	"context"
+
+	liststypes "github.com/michimani/gotwi/lists/types"
+	"github.com/michimani/gotwi/service"
+	spacestypes "github.com/michimani/gotwi/spaces/types"
+	"github.com/michimani/gotwi/tweets"
+	tweetstypes "github.com/michimani/gotwi/tweets/types"
+	userstypes "github.com/michimani/gotwi/users/types"
+)
+
+var (
+	_ service.TweetsAPI = (*Mock)(nil)
+	_ service.UsersAPI  = (*Mock)(nil)
+	_ service.ListsAPI  = (*Mock)(nil)
+	_ service.SpacesAPI = (*Mock)(nil)
+)
+
+// Funcs are the functions called by the methods of Mock instead of the canned responses, if they are set.
+type Funcs struct {
+	FilteredStreamRulesGet     func(ctx context.Context, p *tweetstypes.FilteredStreamRulesGetParams) (*tweetstypes.FilteredStreamRulesGetResponse, error)
+	FilteredStreamRulesPost    func(ctx context.Context, p *tweetstypes.FilteredStreamRulesPostParams) (*tweetstypes.FilteredStreamRulesPostResponse, error)
+	FilteredStreamSearch       func(ctx context.Context, p *tweetstypes.FilteredStreamSearchParams) (*tweets.FilteredStream, error)
+	HideReplies                func(ctx context.Context, p *tweetstypes.HideRepliesParams) (*tweetstypes.HideRepliesResponse, error)
+	TweetLikesLikingUsers      func(ctx context.Context, p *tweetstypes.TweetLikesLikingUsersParams) (*tweetstypes.TweetLikesLikingUsersResponse, error)
+	TweetLikesLikedTweets      func(ctx context.Context, p *tweetstypes.TweetLikesLikedTweetsParams) (*tweetstypes.TweetLikesLikedTweetsResponse, error)
+	TweetLikesPost             func(ctx context.Context, p *tweetstypes.TweetLikesPostParams) (*tweetstypes.TweetLikesPostResponse, error)
+	TweetLikesDelete           func(ctx context.Context, p *tweetstypes.TweetLikesDeleteParams) (*tweetstypes.TweetLikesDeleteResponse, error)
+	ManageTweetsPost           func(ctx context.Context, p *tweetstypes.ManageTweetsPostParams) (*tweetstypes.ManageTweetsPostResponse, error)
+	ManageTweetsDelete         func(ctx context.Context, p *tweetstypes.ManageTweetsDeleteParams) (*tweetstypes.ManageTweetsDeleteResponse, error)
+	TweetRetweetsRetweetedBy   func(ctx context.Context, p *tweetstypes.TweetRetweetsRetweetedByParams) (*tweetstypes.TweetRetweetsRetweetedByResponse, error)
+	TweetRetweetsPost          func(ctx context.Context, p *tweetstypes.TweetRetweetsPostParams) (*tweetstypes.TweetRetweetsPostResponse, error)
+	TweetRetweetsDelete        func(ctx context.Context, p *tweetstypes.TweetRetweetsDeleteParams) (*tweetstypes.TweetRetweetsDeleteResponse, error)
+	SearchTweetsRecent         func(ctx context.Context, p *tweetstypes.SearchTweetsRecentParams) (*tweetstypes.SearchTweetsRecentResponse, error)
+	SearchTweetsAll            func(ctx context.Context, p *tweetstypes.SearchTweetsAllParams) (*tweetstypes.SearchTweetsAllResponse, error)
+	TweetCountsRecent          func(ctx context.Context, p *tweetstypes.TweetCountsRecentParams) (*tweetstypes.TweetCountsRecentResponse, error)
+	TweetCountsAll             func(ctx context.Context, p *tweetstypes.TweetCountsAllParams) (*tweetstypes.TweetCountsAllResponse, error)
+	TweetLookup                func(ctx context.Context, p *tweetstypes.TweetLookupParams) (*tweetstypes.TweetLookupResponse, error)
+	TweetLookupID              func(ctx context.Context, p *tweetstypes.TweetLookupIDParams) (*tweetstypes.TweetLookupIDResponse, error)
+	TweetTimelinesTweets       func(ctx context.Context, p *tweetstypes.TweetTimelinesTweetsParams) (*tweetstypes.TweetTimelinesTweetsResponse, error)
+	TweetTimelinesMentions     func(ctx context.Context, p *tweetstypes.TweetTimelinesMentionsParams) (*tweetstypes.TweetTimelinesMentionsResponse, error)
+	PostThread                 func(ctx context.Context, p *tweets.PostThreadInput) (*tweets.PostThreadOutput, error)
+	BlocksBlockingGet          func(ctx context.Context, p *userstypes.BlocksBlockingGetParams) (*userstypes.BlocksBlockingGetResponse, error)
+	BlocksBlockingPost         func(ctx context.Context, p *userstypes.BlocksBlockingPostParams) (*userstypes.BlocksBlockingPostResponse, error)
+	BlocksBlockingDelete       func(ctx context.Context, p *userstypes.BlocksBlockingDeleteParams) (*userstypes.BlocksBlockingDeleteResponse, error)
+	FollowsFollowingGet        func(ctx context.Context, p *userstypes.FollowsFollowingGetParams) (*userstypes.FollowsFollowingGetResponse, error)
+	FollowsFollowers           func(ctx context.Context, p *userstypes.FollowsFollowersParams) (*userstypes.FollowsFollowersResponse, error)
+	FollowsFollowingPost       func(ctx context.Context, p *userstypes.FollowsFollowingPostParams) (*userstypes.FollowsFollowingPostResponse, error)
+	FollowsFollowingDelete     func(ctx context.Context, p *userstypes.FollowsFollowingDeleteParams) (*userstypes.FollowsFollowingDeleteResponse, error)
+	MutesMutingGet             func(ctx context.Context, p *userstypes.MutesMutingGetParams) (*userstypes.MutesMutingGetResponse, error)
+	MutesMutingPost            func(ctx context.Context, p *userstypes.MutesMutingPostParams) (*userstypes.MutesMutingPostResponse, error)
+	MutesMutingDelete          func(ctx context.Context, p *userstypes.MutesMutingDeleteParams) (*userstypes.MutesMutingDeleteResponse, error)
+	UserLookup                 func(ctx context.Context, p *userstypes.UserLookupParams) (*userstypes.UserLookupResponse, error)
+	UserLookupID               func(ctx context.Context, p *userstypes.UserLookupIDParams) (*userstypes.UserLookupIDResponse, error)
+	UserLookupBy               func(ctx context.Context, p *userstypes.UserLookupByParams) (*userstypes.UserLookupByResponse, error)
+	UserLookupByUsername       func(ctx context.Context, p *userstypes.UserLookupByUsernameParams) (*userstypes.UserLookupByUsernameResponse, error)
+	UserLookupMe               func(ctx context.Context, p *userstypes.UserLookupMeParams) (*userstypes.UserLookupMeResponse, error)
+	ListFollowsFollowers       func(ctx context.Context, p *liststypes.ListFollowsFollowersParams) (*liststypes.ListFollowsFollowersResponse, error)
+	ListFollowsFollowedLists   func(ctx context.Context, p *liststypes.ListFollowsFollowedListsParams) (*liststypes.ListFollowsFollowedListsResponse, error)
+	ListFollowsPost            func(ctx context.Context, p *liststypes.ListFollowsPostParams) (*liststypes.ListFollowsPostResponse, error)
+	ListFollowsDelete          func(ctx context.Context, p *liststypes.ListFollowsDeleteParams) (*liststypes.ListFollowsDeleteResponse, error)
+	ListLookupID               func(ctx context.Context, p *liststypes.ListLookupIDParams) (*liststypes.ListLookupIDResponse, error)
+	ListLookupOwnedLists       func(ctx context.Context, p *liststypes.ListLookupOwnedListsParams) (*liststypes.ListLookupOwnedListsResponse, error)
+	ListMembersListMemberships func(ctx context.Context, p *liststypes.ListMembersListMembershipsParams) (*liststypes.ListMembersListMembershipsResponse, error)
+	ListMembersGet             func(ctx context.Context, p *liststypes.ListMembersGetParams) (*liststypes.ListMembersGetResponse, error)
+	ListMembersPost            func(ctx context.Context, p *liststypes.ListMembersPostParams) (*liststypes.ListMembersPostResponse, error)
+	ListMembersDelete          func(ctx context.Context, p *liststypes.ListMembersDeleteParams) (*liststypes.ListMembersDeleteResponse, error)
+	ListTweetsLookup           func(ctx context.Context, p *liststypes.ListTweetsLookupParams) (*liststypes.ListTweetsLookupResponse, error)
+	ManageListsPost            func(ctx context.Context, p *liststypes.ManageListsPostParams) (*liststypes.ManageListsPostResponse, error)
+	ManageListsPut             func(ctx context.Context, p *liststypes.ManageListsPutParams) (*liststypes.ManageListsPutResponse, error)
+	ManageListsDelete          func(ctx context.Context, p *liststypes.ManageListsDeleteParams) (*liststypes.ManageListsDeleteResponse, error)
+	PinnedListsGet             func(ctx context.Context, p *liststypes.PinnedListsGetParams) (*liststypes.PinnedListsGetResponse, error)
+	PinnedListsPost            func(ctx context.Context, p *liststypes.PinnedListsPostParams) (*liststypes.PinnedListsPostResponse, error)
+	PinnedListsDelete          func(ctx context.Context, p *liststypes.PinnedListsDeleteParams) (*liststypes.PinnedListsDeleteResponse, error)
+	SearchSpaces               func(ctx context.Context, p *spacestypes.SearchSpacesParams) (*spacestypes.SearchSpacesResponse, error)
+	SpacesLookupID             func(ctx context.Context, p *spacestypes.SpacesLookupIDParams) (*spacestypes.SpacesLookupIDResponse, error)
+	SpacesLookup               func(ctx context.Context, p *spacestypes.SpacesLookupParams) (*spacestypes.SpacesLookupResponse, error)
+	SpacesLookupByCreatorIDs   func(ctx context.Context, p *spacestypes.SpacesLookupByCreatorIDsParams) (*spacestypes.SpacesLookupByCreatorIDsResponse, error)
+}
+
+// methods are the names of the methods, which are checked by Return.
+var methods = map[string]bool{
+	"FilteredStreamRulesGet":     true,
+	"FilteredStreamRulesPost":    true,
+	"FilteredStreamSearch":       true,
+	"HideReplies":                true,
+	"TweetLikesLikingUsers":      true,
+	"TweetLikesLikedTweets":      true,
+	"TweetLikesPost":             true,
+	"TweetLikesDelete":           true,
+	"ManageTweetsPost":           true,
+	"ManageTweetsDelete":         true,
+	"TweetRetweetsRetweetedBy":   true,
+	"TweetRetweetsPost":          true,
+	"TweetRetweetsDelete":        true,
+	"SearchTweetsRecent":         true,
+	"SearchTweetsAll":            true,
+	"TweetCountsRecent":          true,
+	"TweetCountsAll":             true,
+	"TweetLookup":                true,
+	"TweetLookupID":              true,
+	"TweetTimelinesTweets":       true,
+	"TweetTimelinesMentions":     true,
+	"PostThread":                 true,
+	"BlocksBlockingGet":          true,
+	"BlocksBlockingPost":         true,
+	"BlocksBlockingDelete":       true,
+	"FollowsFollowingGet":        true,
+	"FollowsFollowers":           true,
+	"FollowsFollowingPost":       true,
+	"FollowsFollowingDelete":     true,
+	"MutesMutingGet":             true,
+	"MutesMutingPost":            true,
+	"MutesMutingDelete":          true,
+	"UserLookup":                 true,
+	"UserLookupID":               true,
+	"UserLookupBy":               true,
+	"UserLookupByUsername":       true,
+	"UserLookupMe":               true,
+	"ListFollowsFollowers":       true,
+	"ListFollowsFollowedLists":   true,
+	"ListFollowsPost":            true,
+	"ListFollowsDelete":          true,
+	"ListLookupID":               true,
+	"ListLookupOwnedLists":       true,
+	"ListMembersListMemberships": true,
+	"ListMembersGet":             true,
+	"ListMembersPost":            true,
+	"ListMembersDelete":          true,
+	"ListTweetsLookup":           true,
+	"ManageListsPost":            true,
+	"ManageListsPut":             true,
+	"ManageListsDelete":          true,
+	"PinnedListsGet":             true,
+	"PinnedListsPost":            true,
+	"PinnedListsDelete":          true,
+	"SearchSpaces":               true,
+	"SpacesLookupID":             true,
+	"SpacesLookup":               true,
+	"SpacesLookupByCreatorIDs":   true,
+}
+
+func (m *Mock) FilteredStreamRulesGet(ctx context.Context, p *tweetstypes.FilteredStreamRulesGetParams) (*tweetstypes.FilteredStreamRulesGetResponse, error) {
+	m.record("FilteredStreamRulesGet", p)
+	if f := m.Funcs.FilteredStreamRulesGet; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("FilteredStreamRulesGet")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.FilteredStreamRulesGetResponse)
+	if !ok {
+		return nil, wrongType("FilteredStreamRulesGet", res, (*tweetstypes.FilteredStreamRulesGetResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) FilteredStreamRulesPost(ctx context.Context, p *tweetstypes.FilteredStreamRulesPostParams) (*tweetstypes.FilteredStreamRulesPostResponse, error) {
+	m.record("FilteredStreamRulesPost", p)
+	if f := m.Funcs.FilteredStreamRulesPost; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("FilteredStreamRulesPost")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.FilteredStreamRulesPostResponse)
+	if !ok {
+		return nil, wrongType("FilteredStreamRulesPost", res, (*tweetstypes.FilteredStreamRulesPostResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) FilteredStreamSearch(ctx context.Context, p *tweetstypes.FilteredStreamSearchParams) (*tweets.FilteredStream, error) {
+	m.record("FilteredStreamSearch", p)
+	if f := m.Funcs.FilteredStreamSearch; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("FilteredStreamSearch")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweets.FilteredStream)
+	if !ok {
+		return nil, wrongType("FilteredStreamSearch", res, (*tweets.FilteredStream)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) HideReplies(ctx context.Context, p *tweetstypes.HideRepliesParams) (*tweetstypes.HideRepliesResponse, error) {
+	m.record("HideReplies", p)
+	if f := m.Funcs.HideReplies; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("HideReplies")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.HideRepliesResponse)
+	if !ok {
+		return nil, wrongType("HideReplies", res, (*tweetstypes.HideRepliesResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) TweetLikesLikingUsers(ctx context.Context, p *tweetstypes.TweetLikesLikingUsersParams) (*tweetstypes.TweetLikesLikingUsersResponse, error) {
+	m.record("TweetLikesLikingUsers", p)
+	if f := m.Funcs.TweetLikesLikingUsers; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("TweetLikesLikingUsers")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.TweetLikesLikingUsersResponse)
+	if !ok {
+		return nil, wrongType("TweetLikesLikingUsers", res, (*tweetstypes.TweetLikesLikingUsersResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) TweetLikesLikedTweets(ctx context.Context, p *tweetstypes.TweetLikesLikedTweetsParams) (*tweetstypes.TweetLikesLikedTweetsResponse, error) {
+	m.record("TweetLikesLikedTweets", p)
+	if f := m.Funcs.TweetLikesLikedTweets; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("TweetLikesLikedTweets")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.TweetLikesLikedTweetsResponse)
+	if !ok {
+		return nil, wrongType("TweetLikesLikedTweets", res, (*tweetstypes.TweetLikesLikedTweetsResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) TweetLikesPost(ctx context.Context, p *tweetstypes.TweetLikesPostParams) (*tweetstypes.TweetLikesPostResponse, error) {
+	m.record("TweetLikesPost", p)
+	if f := m.Funcs.TweetLikesPost; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("TweetLikesPost")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.TweetLikesPostResponse)
+	if !ok {
+		return nil, wrongType("TweetLikesPost", res, (*tweetstypes.TweetLikesPostResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) TweetLikesDelete(ctx context.Context, p *tweetstypes.TweetLikesDeleteParams) (*tweetstypes.TweetLikesDeleteResponse, error) {
+	m.record("TweetLikesDelete", p)
+	if f := m.Funcs.TweetLikesDelete; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("TweetLikesDelete")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.TweetLikesDeleteResponse)
+	if !ok {
+		return nil, wrongType("TweetLikesDelete", res, (*tweetstypes.TweetLikesDeleteResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ManageTweetsPost(ctx context.Context, p *tweetstypes.ManageTweetsPostParams) (*tweetstypes.ManageTweetsPostResponse, error) {
+	m.record("ManageTweetsPost", p)
+	if f := m.Funcs.ManageTweetsPost; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ManageTweetsPost")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.ManageTweetsPostResponse)
+	if !ok {
+		return nil, wrongType("ManageTweetsPost", res, (*tweetstypes.ManageTweetsPostResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ManageTweetsDelete(ctx context.Context, p *tweetstypes.ManageTweetsDeleteParams) (*tweetstypes.ManageTweetsDeleteResponse, error) {
+	m.record("ManageTweetsDelete", p)
+	if f := m.Funcs.ManageTweetsDelete; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ManageTweetsDelete")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.ManageTweetsDeleteResponse)
+	if !ok {
+		return nil, wrongType("ManageTweetsDelete", res, (*tweetstypes.ManageTweetsDeleteResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) TweetRetweetsRetweetedBy(ctx context.Context, p *tweetstypes.TweetRetweetsRetweetedByParams) (*tweetstypes.TweetRetweetsRetweetedByResponse, error) {
+	m.record("TweetRetweetsRetweetedBy", p)
+	if f := m.Funcs.TweetRetweetsRetweetedBy; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("TweetRetweetsRetweetedBy")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.TweetRetweetsRetweetedByResponse)
+	if !ok {
+		return nil, wrongType("TweetRetweetsRetweetedBy", res, (*tweetstypes.TweetRetweetsRetweetedByResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) TweetRetweetsPost(ctx context.Context, p *tweetstypes.TweetRetweetsPostParams) (*tweetstypes.TweetRetweetsPostResponse, error) {
+	m.record("TweetRetweetsPost", p)
+	if f := m.Funcs.TweetRetweetsPost; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("TweetRetweetsPost")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.TweetRetweetsPostResponse)
+	if !ok {
+		return nil, wrongType("TweetRetweetsPost", res, (*tweetstypes.TweetRetweetsPostResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) TweetRetweetsDelete(ctx context.Context, p *tweetstypes.TweetRetweetsDeleteParams) (*tweetstypes.TweetRetweetsDeleteResponse, error) {
+	m.record("TweetRetweetsDelete", p)
+	if f := m.Funcs.TweetRetweetsDelete; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("TweetRetweetsDelete")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.TweetRetweetsDeleteResponse)
+	if !ok {
+		return nil, wrongType("TweetRetweetsDelete", res, (*tweetstypes.TweetRetweetsDeleteResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) SearchTweetsRecent(ctx context.Context, p *tweetstypes.SearchTweetsRecentParams) (*tweetstypes.SearchTweetsRecentResponse, error) {
+	m.record("SearchTweetsRecent", p)
+	if f := m.Funcs.SearchTweetsRecent; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("SearchTweetsRecent")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.SearchTweetsRecentResponse)
+	if !ok {
+		return nil, wrongType("SearchTweetsRecent", res, (*tweetstypes.SearchTweetsRecentResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) SearchTweetsAll(ctx context.Context, p *tweetstypes.SearchTweetsAllParams) (*tweetstypes.SearchTweetsAllResponse, error) {
+	m.record("SearchTweetsAll", p)
+	if f := m.Funcs.SearchTweetsAll; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("SearchTweetsAll")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.SearchTweetsAllResponse)
+	if !ok {
+		return nil, wrongType("SearchTweetsAll", res, (*tweetstypes.SearchTweetsAllResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) TweetCountsRecent(ctx context.Context, p *tweetstypes.TweetCountsRecentParams) (*tweetstypes.TweetCountsRecentResponse, error) {
+	m.record("TweetCountsRecent", p)
+	if f := m.Funcs.TweetCountsRecent; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("TweetCountsRecent")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.TweetCountsRecentResponse)
+	if !ok {
+		return nil, wrongType("TweetCountsRecent", res, (*tweetstypes.TweetCountsRecentResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) TweetCountsAll(ctx context.Context, p *tweetstypes.TweetCountsAllParams) (*tweetstypes.TweetCountsAllResponse, error) {
+	m.record("TweetCountsAll", p)
+	if f := m.Funcs.TweetCountsAll; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("TweetCountsAll")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.TweetCountsAllResponse)
+	if !ok {
+		return nil, wrongType("TweetCountsAll", res, (*tweetstypes.TweetCountsAllResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) TweetLookup(ctx context.Context, p *tweetstypes.TweetLookupParams) (*tweetstypes.TweetLookupResponse, error) {
+	m.record("TweetLookup", p)
+	if f := m.Funcs.TweetLookup; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("TweetLookup")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.TweetLookupResponse)
+	if !ok {
+		return nil, wrongType("TweetLookup", res, (*tweetstypes.TweetLookupResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) TweetLookupID(ctx context.Context, p *tweetstypes.TweetLookupIDParams) (*tweetstypes.TweetLookupIDResponse, error) {
+	m.record("TweetLookupID", p)
+	if f := m.Funcs.TweetLookupID; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("TweetLookupID")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.TweetLookupIDResponse)
+	if !ok {
+		return nil, wrongType("TweetLookupID", res, (*tweetstypes.TweetLookupIDResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) TweetTimelinesTweets(ctx context.Context, p *tweetstypes.TweetTimelinesTweetsParams) (*tweetstypes.TweetTimelinesTweetsResponse, error) {
+	m.record("TweetTimelinesTweets", p)
+	if f := m.Funcs.TweetTimelinesTweets; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("TweetTimelinesTweets")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.TweetTimelinesTweetsResponse)
+	if !ok {
+		return nil, wrongType("TweetTimelinesTweets", res, (*tweetstypes.TweetTimelinesTweetsResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) TweetTimelinesMentions(ctx context.Context, p *tweetstypes.TweetTimelinesMentionsParams) (*tweetstypes.TweetTimelinesMentionsResponse, error) {
+	m.record("TweetTimelinesMentions", p)
+	if f := m.Funcs.TweetTimelinesMentions; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("TweetTimelinesMentions")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweetstypes.TweetTimelinesMentionsResponse)
+	if !ok {
+		return nil, wrongType("TweetTimelinesMentions", res, (*tweetstypes.TweetTimelinesMentionsResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) PostThread(ctx context.Context, p *tweets.PostThreadInput) (*tweets.PostThreadOutput, error) {
+	m.record("PostThread", p)
+	if f := m.Funcs.PostThread; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("PostThread")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*tweets.PostThreadOutput)
+	if !ok {
+		return nil, wrongType("PostThread", res, (*tweets.PostThreadOutput)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) BlocksBlockingGet(ctx context.Context, p *userstypes.BlocksBlockingGetParams) (*userstypes.BlocksBlockingGetResponse, error) {
+	m.record("BlocksBlockingGet", p)
+	if f := m.Funcs.BlocksBlockingGet; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("BlocksBlockingGet")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.BlocksBlockingGetResponse)
+	if !ok {
+		return nil, wrongType("BlocksBlockingGet", res, (*userstypes.BlocksBlockingGetResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) BlocksBlockingPost(ctx context.Context, p *userstypes.BlocksBlockingPostParams) (*userstypes.BlocksBlockingPostResponse, error) {
+	m.record("BlocksBlockingPost", p)
+	if f := m.Funcs.BlocksBlockingPost; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("BlocksBlockingPost")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.BlocksBlockingPostResponse)
+	if !ok {
+		return nil, wrongType("BlocksBlockingPost", res, (*userstypes.BlocksBlockingPostResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) BlocksBlockingDelete(ctx context.Context, p *userstypes.BlocksBlockingDeleteParams) (*userstypes.BlocksBlockingDeleteResponse, error) {
+	m.record("BlocksBlockingDelete", p)
+	if f := m.Funcs.BlocksBlockingDelete; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("BlocksBlockingDelete")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.BlocksBlockingDeleteResponse)
+	if !ok {
+		return nil, wrongType("BlocksBlockingDelete", res, (*userstypes.BlocksBlockingDeleteResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) FollowsFollowingGet(ctx context.Context, p *userstypes.FollowsFollowingGetParams) (*userstypes.FollowsFollowingGetResponse, error) {
+	m.record("FollowsFollowingGet", p)
+	if f := m.Funcs.FollowsFollowingGet; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("FollowsFollowingGet")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.FollowsFollowingGetResponse)
+	if !ok {
+		return nil, wrongType("FollowsFollowingGet", res, (*userstypes.FollowsFollowingGetResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) FollowsFollowers(ctx context.Context, p *userstypes.FollowsFollowersParams) (*userstypes.FollowsFollowersResponse, error) {
+	m.record("FollowsFollowers", p)
+	if f := m.Funcs.FollowsFollowers; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("FollowsFollowers")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.FollowsFollowersResponse)
+	if !ok {
+		return nil, wrongType("FollowsFollowers", res, (*userstypes.FollowsFollowersResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) FollowsFollowingPost(ctx context.Context, p *userstypes.FollowsFollowingPostParams) (*userstypes.FollowsFollowingPostResponse, error) {
+	m.record("FollowsFollowingPost", p)
+	if f := m.Funcs.FollowsFollowingPost; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("FollowsFollowingPost")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.FollowsFollowingPostResponse)
+	if !ok {
+		return nil, wrongType("FollowsFollowingPost", res, (*userstypes.FollowsFollowingPostResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) FollowsFollowingDelete(ctx context.Context, p *userstypes.FollowsFollowingDeleteParams) (*userstypes.FollowsFollowingDeleteResponse, error) {
+	m.record("FollowsFollowingDelete", p)
+	if f := m.Funcs.FollowsFollowingDelete; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("FollowsFollowingDelete")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.FollowsFollowingDeleteResponse)
+	if !ok {
+		return nil, wrongType("FollowsFollowingDelete", res, (*userstypes.FollowsFollowingDeleteResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) MutesMutingGet(ctx context.Context, p *userstypes.MutesMutingGetParams) (*userstypes.MutesMutingGetResponse, error) {
+	m.record("MutesMutingGet", p)
+	if f := m.Funcs.MutesMutingGet; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("MutesMutingGet")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.MutesMutingGetResponse)
+	if !ok {
+		return nil, wrongType("MutesMutingGet", res, (*userstypes.MutesMutingGetResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) MutesMutingPost(ctx context.Context, p *userstypes.MutesMutingPostParams) (*userstypes.MutesMutingPostResponse, error) {
+	m.record("MutesMutingPost", p)
+	if f := m.Funcs.MutesMutingPost; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("MutesMutingPost")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.MutesMutingPostResponse)
+	if !ok {
+		return nil, wrongType("MutesMutingPost", res, (*userstypes.MutesMutingPostResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) MutesMutingDelete(ctx context.Context, p *userstypes.MutesMutingDeleteParams) (*userstypes.MutesMutingDeleteResponse, error) {
+	m.record("MutesMutingDelete", p)
+	if f := m.Funcs.MutesMutingDelete; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("MutesMutingDelete")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.MutesMutingDeleteResponse)
+	if !ok {
+		return nil, wrongType("MutesMutingDelete", res, (*userstypes.MutesMutingDeleteResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) UserLookup(ctx context.Context, p *userstypes.UserLookupParams) (*userstypes.UserLookupResponse, error) {
+	m.record("UserLookup", p)
+	if f := m.Funcs.UserLookup; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("UserLookup")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.UserLookupResponse)
+	if !ok {
+		return nil, wrongType("UserLookup", res, (*userstypes.UserLookupResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) UserLookupID(ctx context.Context, p *userstypes.UserLookupIDParams) (*userstypes.UserLookupIDResponse, error) {
+	m.record("UserLookupID", p)
+	if f := m.Funcs.UserLookupID; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("UserLookupID")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.UserLookupIDResponse)
+	if !ok {
+		return nil, wrongType("UserLookupID", res, (*userstypes.UserLookupIDResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) UserLookupBy(ctx context.Context, p *userstypes.UserLookupByParams) (*userstypes.UserLookupByResponse, error) {
+	m.record("UserLookupBy", p)
+	if f := m.Funcs.UserLookupBy; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("UserLookupBy")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.UserLookupByResponse)
+	if !ok {
+		return nil, wrongType("UserLookupBy", res, (*userstypes.UserLookupByResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) UserLookupByUsername(ctx context.Context, p *userstypes.UserLookupByUsernameParams) (*userstypes.UserLookupByUsernameResponse, error) {
+	m.record("UserLookupByUsername", p)
+	if f := m.Funcs.UserLookupByUsername; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("UserLookupByUsername")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.UserLookupByUsernameResponse)
+	if !ok {
+		return nil, wrongType("UserLookupByUsername", res, (*userstypes.UserLookupByUsernameResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) UserLookupMe(ctx context.Context, p *userstypes.UserLookupMeParams) (*userstypes.UserLookupMeResponse, error) {
+	m.record("UserLookupMe", p)
+	if f := m.Funcs.UserLookupMe; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("UserLookupMe")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*userstypes.UserLookupMeResponse)
+	if !ok {
+		return nil, wrongType("UserLookupMe", res, (*userstypes.UserLookupMeResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ListFollowsFollowers(ctx context.Context, p *liststypes.ListFollowsFollowersParams) (*liststypes.ListFollowsFollowersResponse, error) {
+	m.record("ListFollowsFollowers", p)
+	if f := m.Funcs.ListFollowsFollowers; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ListFollowsFollowers")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ListFollowsFollowersResponse)
+	if !ok {
+		return nil, wrongType("ListFollowsFollowers", res, (*liststypes.ListFollowsFollowersResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ListFollowsFollowedLists(ctx context.Context, p *liststypes.ListFollowsFollowedListsParams) (*liststypes.ListFollowsFollowedListsResponse, error) {
+	m.record("ListFollowsFollowedLists", p)
+	if f := m.Funcs.ListFollowsFollowedLists; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ListFollowsFollowedLists")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ListFollowsFollowedListsResponse)
+	if !ok {
+		return nil, wrongType("ListFollowsFollowedLists", res, (*liststypes.ListFollowsFollowedListsResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ListFollowsPost(ctx context.Context, p *liststypes.ListFollowsPostParams) (*liststypes.ListFollowsPostResponse, error) {
+	m.record("ListFollowsPost", p)
+	if f := m.Funcs.ListFollowsPost; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ListFollowsPost")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ListFollowsPostResponse)
+	if !ok {
+		return nil, wrongType("ListFollowsPost", res, (*liststypes.ListFollowsPostResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ListFollowsDelete(ctx context.Context, p *liststypes.ListFollowsDeleteParams) (*liststypes.ListFollowsDeleteResponse, error) {
+	m.record("ListFollowsDelete", p)
+	if f := m.Funcs.ListFollowsDelete; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ListFollowsDelete")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ListFollowsDeleteResponse)
+	if !ok {
+		return nil, wrongType("ListFollowsDelete", res, (*liststypes.ListFollowsDeleteResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ListLookupID(ctx context.Context, p *liststypes.ListLookupIDParams) (*liststypes.ListLookupIDResponse, error) {
+	m.record("ListLookupID", p)
+	if f := m.Funcs.ListLookupID; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ListLookupID")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ListLookupIDResponse)
+	if !ok {
+		return nil, wrongType("ListLookupID", res, (*liststypes.ListLookupIDResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ListLookupOwnedLists(ctx context.Context, p *liststypes.ListLookupOwnedListsParams) (*liststypes.ListLookupOwnedListsResponse, error) {
+	m.record("ListLookupOwnedLists", p)
+	if f := m.Funcs.ListLookupOwnedLists; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ListLookupOwnedLists")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ListLookupOwnedListsResponse)
+	if !ok {
+		return nil, wrongType("ListLookupOwnedLists", res, (*liststypes.ListLookupOwnedListsResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ListMembersListMemberships(ctx context.Context, p *liststypes.ListMembersListMembershipsParams) (*liststypes.ListMembersListMembershipsResponse, error) {
+	m.record("ListMembersListMemberships", p)
+	if f := m.Funcs.ListMembersListMemberships; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ListMembersListMemberships")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ListMembersListMembershipsResponse)
+	if !ok {
+		return nil, wrongType("ListMembersListMemberships", res, (*liststypes.ListMembersListMembershipsResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ListMembersGet(ctx context.Context, p *liststypes.ListMembersGetParams) (*liststypes.ListMembersGetResponse, error) {
+	m.record("ListMembersGet", p)
+	if f := m.Funcs.ListMembersGet; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ListMembersGet")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ListMembersGetResponse)
+	if !ok {
+		return nil, wrongType("ListMembersGet", res, (*liststypes.ListMembersGetResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ListMembersPost(ctx context.Context, p *liststypes.ListMembersPostParams) (*liststypes.ListMembersPostResponse, error) {
+	m.record("ListMembersPost", p)
+	if f := m.Funcs.ListMembersPost; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ListMembersPost")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ListMembersPostResponse)
+	if !ok {
+		return nil, wrongType("ListMembersPost", res, (*liststypes.ListMembersPostResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ListMembersDelete(ctx context.Context, p *liststypes.ListMembersDeleteParams) (*liststypes.ListMembersDeleteResponse, error) {
+	m.record("ListMembersDelete", p)
+	if f := m.Funcs.ListMembersDelete; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ListMembersDelete")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ListMembersDeleteResponse)
+	if !ok {
+		return nil, wrongType("ListMembersDelete", res, (*liststypes.ListMembersDeleteResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ListTweetsLookup(ctx context.Context, p *liststypes.ListTweetsLookupParams) (*liststypes.ListTweetsLookupResponse, error) {
+	m.record("ListTweetsLookup", p)
+	if f := m.Funcs.ListTweetsLookup; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ListTweetsLookup")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ListTweetsLookupResponse)
+	if !ok {
+		return nil, wrongType("ListTweetsLookup", res, (*liststypes.ListTweetsLookupResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ManageListsPost(ctx context.Context, p *liststypes.ManageListsPostParams) (*liststypes.ManageListsPostResponse, error) {
+	m.record("ManageListsPost", p)
+	if f := m.Funcs.ManageListsPost; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ManageListsPost")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ManageListsPostResponse)
+	if !ok {
+		return nil, wrongType("ManageListsPost", res, (*liststypes.ManageListsPostResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ManageListsPut(ctx context.Context, p *liststypes.ManageListsPutParams) (*liststypes.ManageListsPutResponse, error) {
+	m.record("ManageListsPut", p)
+	if f := m.Funcs.ManageListsPut; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ManageListsPut")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ManageListsPutResponse)
+	if !ok {
+		return nil, wrongType("ManageListsPut", res, (*liststypes.ManageListsPutResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) ManageListsDelete(ctx context.Context, p *liststypes.ManageListsDeleteParams) (*liststypes.ManageListsDeleteResponse, error) {
+	m.record("ManageListsDelete", p)
+	if f := m.Funcs.ManageListsDelete; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("ManageListsDelete")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.ManageListsDeleteResponse)
+	if !ok {
+		return nil, wrongType("ManageListsDelete", res, (*liststypes.ManageListsDeleteResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) PinnedListsGet(ctx context.Context, p *liststypes.PinnedListsGetParams) (*liststypes.PinnedListsGetResponse, error) {
+	m.record("PinnedListsGet", p)
+	if f := m.Funcs.PinnedListsGet; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("PinnedListsGet")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.PinnedListsGetResponse)
+	if !ok {
+		return nil, wrongType("PinnedListsGet", res, (*liststypes.PinnedListsGetResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) PinnedListsPost(ctx context.Context, p *liststypes.PinnedListsPostParams) (*liststypes.PinnedListsPostResponse, error) {
+	m.record("PinnedListsPost", p)
+	if f := m.Funcs.PinnedListsPost; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("PinnedListsPost")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.PinnedListsPostResponse)
+	if !ok {
+		return nil, wrongType("PinnedListsPost", res, (*liststypes.PinnedListsPostResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) PinnedListsDelete(ctx context.Context, p *liststypes.PinnedListsDeleteParams) (*liststypes.PinnedListsDeleteResponse, error) {
+	m.record("PinnedListsDelete", p)
+	if f := m.Funcs.PinnedListsDelete; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("PinnedListsDelete")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*liststypes.PinnedListsDeleteResponse)
+	if !ok {
+		return nil, wrongType("PinnedListsDelete", res, (*liststypes.PinnedListsDeleteResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) SearchSpaces(ctx context.Context, p *spacestypes.SearchSpacesParams) (*spacestypes.SearchSpacesResponse, error) {
+	m.record("SearchSpaces", p)
+	if f := m.Funcs.SearchSpaces; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("SearchSpaces")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*spacestypes.SearchSpacesResponse)
+	if !ok {
+		return nil, wrongType("SearchSpaces", res, (*spacestypes.SearchSpacesResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) SpacesLookupID(ctx context.Context, p *spacestypes.SpacesLookupIDParams) (*spacestypes.SpacesLookupIDResponse, error) {
+	m.record("SpacesLookupID", p)
+	if f := m.Funcs.SpacesLookupID; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("SpacesLookupID")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*spacestypes.SpacesLookupIDResponse)
+	if !ok {
+		return nil, wrongType("SpacesLookupID", res, (*spacestypes.SpacesLookupIDResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) SpacesLookup(ctx context.Context, p *spacestypes.SpacesLookupParams) (*spacestypes.SpacesLookupResponse, error) {
+	m.record("SpacesLookup", p)
+	if f := m.Funcs.SpacesLookup; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("SpacesLookup")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*spacestypes.SpacesLookupResponse)
+	if !ok {
+		return nil, wrongType("SpacesLookup", res, (*spacestypes.SpacesLookupResponse)(nil))
+	}
+	return r, err
+}
+
+func (m *Mock) SpacesLookupByCreatorIDs(ctx context.Context, p *spacestypes.SpacesLookupByCreatorIDsParams) (*spacestypes.SpacesLookupByCreatorIDsResponse, error) {
+	m.record("SpacesLookupByCreatorIDs", p)
+	if f := m.Funcs.SpacesLookupByCreatorIDs; f != nil {
+		return f(ctx, p)
+	}
+	res, err := m.next("SpacesLookupByCreatorIDs")
+	if res == nil {
+		return nil, err
+	}
+	r, ok := res.(*spacestypes.SpacesLookupByCreatorIDsResponse)
+	if !ok {
+		return nil, wrongType("SpacesLookupByCreatorIDs", res, (*spacestypes.SpacesLookupByCreatorIDsResponse)(nil))
+	}
+	return r, err
+}
@@ -0,0 +1,124 @@
+package servicemock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/resources"
+	"github.com/michimani/gotwi/service"
+	"github.com/michimani/gotwi/service/servicemock"
+	"github.com/michimani/gotwi/users/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// followersCount is the code under test, which depends on service.UsersAPI.
+func followersCount(ctx context.Context, api service.UsersAPI, id string) (int, error) {
+	n := 0
+	p := &types.FollowsFollowersParams{ID: id}
+	for {
+		res, err := api.FollowsFollowers(ctx, p)
+		if err != nil {
+			return 0, err
+		}
+		n += len(res.Data)
+		if res.Meta.NextToken == nil {
+			return n, nil
+		}
+		p = &types.FollowsFollowersParams{ID: id, PaginationToken: *res.Meta.NextToken}
+	}
+}
+
+func Test_Mock_Return(t *testing.T) {
+	m := &servicemock.Mock{}
+	m.Return("FollowsFollowers", &types.FollowsFollowersResponse{
+		Data: []resources.User{{ID: gotwi.String("1")}, {ID: gotwi.String("2")}},
+		Meta: resources.PaginationMeta{NextToken: gotwi.String("next")},
+	}, nil).Return("FollowsFollowers", &types.FollowsFollowersResponse{
+		Data: []resources.User{{ID: gotwi.String("3")}},
+	}, nil)
+
+	n, err := followersCount(context.Background(), m, "123")
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	calls := m.CallsOf("FollowsFollowers")
+	require.Len(t, calls, 2)
+	assert.Equal(t, "", calls[0].(*types.FollowsFollowersParams).PaginationToken)
+	assert.Equal(t, "next", calls[1].(*types.FollowsFollowersParams).PaginationToken)
+	assert.Equal(t, []servicemock.Call{{Method: "FollowsFollowers", Params: calls[0]}, {Method: "FollowsFollowers", Params: calls[1]}}, m.Calls())
+}
+
+func Test_Mock_Responses(t *testing.T) {
+	errAPI := errors.New("api error")
+
+	cases := []struct {
+		name      string
+		mock      func() *servicemock.Mock
+		expectID  string
+		expectErr string
+	}{
+		{
+			name: "the last response is repeated",
+			mock: func() *servicemock.Mock {
+				return (&servicemock.Mock{}).Return("UserLookupMe", &types.UserLookupMeResponse{Data: resources.User{ID: gotwi.String("1")}}, nil)
+			},
+			expectID: "1",
+		},
+		{
+			name: "error",
+			mock: func() *servicemock.Mock {
+				return (&servicemock.Mock{}).Return("UserLookupMe", nil, errAPI)
+			},
+			expectErr: "api error",
+		},
+		{
+			name: "func",
+			mock: func() *servicemock.Mock {
+				m := &servicemock.Mock{}
+				m.Funcs.UserLookupMe = func(ctx context.Context, p *types.UserLookupMeParams) (*types.UserLookupMeResponse, error) {
+					return &types.UserLookupMeResponse{Data: resources.User{ID: gotwi.String("2")}}, nil
+				}
+				return m
+			},
+			expectID: "2",
+		},
+		{
+			name:      "no response",
+			mock:      func() *servicemock.Mock { return &servicemock.Mock{} },
+			expectErr: "servicemock: no response for UserLookupMe. Set it by Return or Funcs.",
+		},
+		{
+			name: "wrong type",
+			mock: func() *servicemock.Mock {
+				return (&servicemock.Mock{}).Return("UserLookupMe", &types.UserLookupIDResponse{}, nil)
+			},
+			expectErr: "servicemock: the response for UserLookupMe is *types.UserLookupIDResponse, but it must be *types.UserLookupMeResponse.",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			m := c.mock()
+			for i := 0; i < 2; i++ {
+				res, err := m.UserLookupMe(context.Background(), &types.UserLookupMeParams{})
+				if c.expectErr != "" {
+					assert.EqualError(tt, err, c.expectErr)
+					assert.Nil(tt, res)
+					continue
+				}
+				require.NoError(tt, err)
+				assert.Equal(tt, c.expectID, gotwi.StringValue(res.Data.ID))
+			}
+			assert.Len(tt, m.Calls(), 2)
+		})
+	}
+}
+
+func Test_Mock_ReturnUnknownMethod(t *testing.T) {
+	assert.Panics(t, func() {
+		(&servicemock.Mock{}).Return("UserLookupMee", nil, nil)
+	})
+}
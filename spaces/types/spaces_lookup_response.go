@@ -3,11 +3,9 @@ package types
 import "github.com/michimani/gotwi/resources"
 
 type SpacesLookupIDResponse struct {
-	Data     resources.Space `json:"data"`
-	Includes struct {
-		Users []resources.User `json:"users"`
-	} `json:"includes"`
-	Errors []resources.PartialError `json:"errors"`
+	Data     resources.Space          `json:"data"`
+	Includes resources.Includes       `json:"includes"`
+	Errors   []resources.PartialError `json:"errors"`
 }
 
 func (r *SpacesLookupIDResponse) HasPartialError() bool {
@@ -15,11 +13,9 @@ func (r *SpacesLookupIDResponse) HasPartialError() bool {
 }
 
 type SpacesLookupResponse struct {
-	Data     []resources.Space `json:"data"`
-	Includes struct {
-		Users []resources.User `json:"users"`
-	} `json:"includes"`
-	Errors []resources.PartialError `json:"errors"`
+	Data     []resources.Space        `json:"data"`
+	Includes resources.Includes       `json:"includes"`
+	Errors   []resources.PartialError `json:"errors"`
 }
 
 func (r *SpacesLookupResponse) HasPartialError() bool {
@@ -27,12 +23,10 @@ func (r *SpacesLookupResponse) HasPartialError() bool {
 }
 
 type SpacesLookupByCreatorIDsResponse struct {
-	Data     []resources.Space `json:"data"`
-	Includes struct {
-		Users []resources.User `json:"users"`
-	} `json:"includes"`
-	Meta   resources.SpacesLookupByCreatorsIDsMeta `json:"meta"`
-	Errors []resources.PartialError                `json:"errors"`
+	Data     []resources.Space                       `json:"data"`
+	Includes resources.Includes                      `json:"includes"`
+	Meta     resources.SpacesLookupByCreatorsIDsMeta `json:"meta"`
+	Errors   []resources.PartialError                `json:"errors"`
 }
 
 func (r *SpacesLookupByCreatorIDsResponse) HasPartialError() bool {
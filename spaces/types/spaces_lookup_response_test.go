@@ -1,6 +1,7 @@
 package types_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/michimani/gotwi/resources"
@@ -118,3 +119,41 @@ func Test_SpacesLookupByCreatorIDs_HasPartialError(t *testing.T) {
 		})
 	}
 }
+
+func Test_SpacesLookupID_DecodeIncludes(t *testing.T) {
+	cases := []struct {
+		name        string
+		body        string
+		expectUsers int
+		expectTopic int
+	}{
+		{
+			name: "ok: users and topics",
+			body: `{
+				"data": {"id": "1DXxyRYNejbKM", "state": "live", "host_ids": ["10"], "topic_ids": ["848920371311001600"]},
+				"includes": {
+					"users": [{"id": "10", "name": "test user", "username": "test_user"}],
+					"topics": [{"id": "848920371311001600", "name": "Technology", "description": "All about technology"}]
+				}
+			}`,
+			expectUsers: 1,
+			expectTopic: 1,
+		},
+		{
+			name:        "ok: no includes",
+			body:        `{"data": {"id": "1DXxyRYNejbKM", "state": "live"}}`,
+			expectUsers: 0,
+			expectTopic: 0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			res := &types.SpacesLookupIDResponse{}
+			err := json.Unmarshal([]byte(c.body), res)
+			assert.NoError(tt, err)
+			assert.Len(tt, res.Includes.Users, c.expectUsers)
+			assert.Len(tt, res.Includes.Topics, c.expectTopic)
+		})
+	}
+}
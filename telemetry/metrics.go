@@ -0,0 +1,269 @@
+package telemetry
+
+import (
+	"expvar"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/michimani/gotwi"
+)
+
+// DefaultLatencyBuckets are the upper bounds of the latency histogram buckets of NewMetrics.
+var DefaultLatencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Metrics records the metrics of the calls and the streams per endpoint, keyed by the name of the endpoint template
+// such as "GET /2/users/:id/following". It is safe for concurrent use, and the methods of a nil Metrics do nothing.
+type Metrics struct {
+	buckets []time.Duration
+
+	mu         sync.Mutex
+	latencies  map[string]*Histogram
+	requests   map[RequestCount]int64
+	rateLimits map[string]RateLimit
+	streams    map[string]*streamState
+}
+
+// Histogram is the latency histogram of an endpoint.
+type Histogram struct {
+	// Buckets are the upper bounds of the buckets, and Counts are the counts of the calls in them.
+	// The last count is of the calls longer than the last bucket.
+	Buckets []time.Duration `json:"buckets"`
+	Counts  []int64         `json:"counts"`
+	Count   int64           `json:"count"`
+	Sum     time.Duration   `json:"sum"`
+}
+
+// RequestCount is the count of the calls of an endpoint by the status code and the Twitter error code.
+// The status code is 0 for the calls without a response, such as the cached ones and the failed ones.
+type RequestCount struct {
+	Endpoint   string `json:"endpoint"`
+	StatusCode int    `json:"status_code"`
+	ErrorCode  string `json:"error_code,omitempty"`
+	Count      int64  `json:"count"`
+}
+
+// RateLimit is the last rate limit state of an endpoint.
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+// StreamStats is the connection state of a stream endpoint.
+type StreamStats struct {
+	// Connected is the number of the current connections.
+	Connected int `json:"connected"`
+	// Uptime is how long the oldest current connection has been connected. It is 0 if there is none.
+	Uptime time.Duration `json:"uptime"`
+	// Connections is the number of the connections so far, and ClosedUptime is the total uptime of the closed ones.
+	Connections  int64         `json:"connections"`
+	ClosedUptime time.Duration `json:"closed_uptime"`
+}
+
+type streamState struct {
+	open         map[*gotwi.CallInfo]time.Time
+	connections  int64
+	closedUptime time.Duration
+}
+
+// Snapshot is a copy of the metrics.
+type Snapshot struct {
+	Latencies map[string]Histogram `json:"latencies"`
+	// Requests are sorted by the endpoint, the status code and the error code.
+	Requests   []RequestCount         `json:"requests"`
+	RateLimits map[string]RateLimit   `json:"rate_limits"`
+	Streams    map[string]StreamStats `json:"streams"`
+}
+
+// NewMetrics returns Metrics with the latency histogram buckets. If buckets is empty, DefaultLatencyBuckets is used.
+func NewMetrics(buckets []time.Duration) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	b := append([]time.Duration{}, buckets...)
+	sort.Slice(b, func(i, j int) bool { return b[i] < b[j] })
+
+	return &Metrics{
+		buckets:    b,
+		latencies:  map[string]*Histogram{},
+		requests:   map[RequestCount]int64{},
+		rateLimits: map[string]RateLimit{},
+		streams:    map[string]*streamState{},
+	}
+}
+
+func (m *Metrics) record(call *gotwi.CallInfo, r *gotwi.CallResult) {
+	if m == nil {
+		return
+	}
+	name := call.Name()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.latencies[name]
+	if !ok {
+		h = &Histogram{Buckets: m.buckets, Counts: make([]int64, len(m.buckets)+1)}
+		m.latencies[name] = h
+	}
+	h.Counts[sort.Search(len(m.buckets), func(i int) bool { return r.Duration <= m.buckets[i] })]++
+	h.Count++
+	h.Sum += r.Duration
+
+	m.requests[RequestCount{Endpoint: name, StatusCode: r.StatusCode, ErrorCode: r.ErrorCode}]++
+
+	if r.RateLimit != nil {
+		rl := RateLimit{Limit: r.RateLimit.Limit, Remaining: r.RateLimit.Remaining}
+		if r.RateLimit.ResetAt != nil {
+			rl.ResetAt = *r.RateLimit.ResetAt
+		}
+		m.rateLimits[name] = rl
+	}
+}
+
+func (m *Metrics) stream(name string) *streamState {
+	s, ok := m.streams[name]
+	if !ok {
+		s = &streamState{open: map[*gotwi.CallInfo]time.Time{}}
+		m.streams[name] = s
+	}
+	return s
+}
+
+func (m *Metrics) streamConnected(call *gotwi.CallInfo) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.stream(call.Name())
+	s.open[call] = time.Now()
+	s.connections++
+}
+
+func (m *Metrics) streamClosed(call *gotwi.CallInfo, uptime time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.stream(call.Name())
+	delete(s.open, call)
+	s.closedUptime += uptime
+}
+
+// Latency returns the latency histogram of the endpoint, such as "GET /2/users/:id/following".
+func (m *Metrics) Latency(endpoint string) Histogram {
+	if m == nil {
+		return Histogram{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.latencies[endpoint]
+	if !ok {
+		return Histogram{}
+	}
+	return copyHistogram(h)
+}
+
+// RateLimit returns the last rate limit state of the endpoint, and false if it is unknown.
+func (m *Metrics) RateLimit(endpoint string) (RateLimit, bool) {
+	if m == nil {
+		return RateLimit{}, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rl, ok := m.rateLimits[endpoint]
+	return rl, ok
+}
+
+// StreamUptime returns how long the oldest current connection of the stream endpoint has been connected.
+func (m *Metrics) StreamUptime(endpoint string) time.Duration {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.streams[endpoint]
+	if !ok {
+		return 0
+	}
+	return s.stats(time.Now()).Uptime
+}
+
+func (s *streamState) stats(now time.Time) StreamStats {
+	st := StreamStats{Connected: len(s.open), Connections: s.connections, ClosedUptime: s.closedUptime}
+	for _, since := range s.open {
+		if d := now.Sub(since); d > st.Uptime {
+			st.Uptime = d
+		}
+	}
+	return st
+}
+
+// Snapshot returns a copy of the metrics.
+func (m *Metrics) Snapshot() Snapshot {
+	s := Snapshot{
+		Latencies:  map[string]Histogram{},
+		Requests:   []RequestCount{},
+		RateLimits: map[string]RateLimit{},
+		Streams:    map[string]StreamStats{},
+	}
+	if m == nil {
+		return s
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for name, h := range m.latencies {
+		s.Latencies[name] = copyHistogram(h)
+	}
+	for rc, n := range m.requests {
+		rc.Count = n
+		s.Requests = append(s.Requests, rc)
+	}
+	sort.Slice(s.Requests, func(i, j int) bool {
+		a, b := s.Requests[i], s.Requests[j]
+		if a.Endpoint != b.Endpoint {
+			return a.Endpoint < b.Endpoint
+		}
+		if a.StatusCode != b.StatusCode {
+			return a.StatusCode < b.StatusCode
+		}
+		return a.ErrorCode < b.ErrorCode
+	})
+	for name, rl := range m.rateLimits {
+		s.RateLimits[name] = rl
+	}
+	now := time.Now()
+	for name, st := range m.streams {
+		s.Streams[name] = st.stats(now)
+	}
+	return s
+}
+
+// Var returns the expvar.Var of the snapshot, to publish the metrics by expvar.Publish.
+func (m *Metrics) Var() expvar.Var {
+	return expvar.Func(func() interface{} { return m.Snapshot() })
+}
+
+func copyHistogram(h *Histogram) Histogram {
+	c := *h
+	c.Counts = append([]int64{}, h.Counts...)
+	return c
+}
@@ -0,0 +1,107 @@
+// Package telemetry is an adapter of gotwi.Instrumentation that creates a span per call with a Tracer
+// and records the metrics of the calls and the streams in Metrics, without the dependency on a tracing library.
+//
+//	m := telemetry.NewMetrics(nil)
+//	expvar.Publish("gotwi", m.Var())
+//
+//	c, err := gotwi.NewGotwiClient(&gotwi.NewGotwiClientInput{
+//		AuthenticationMethod: gotwi.AuthenMethodOAuth2BearerToken,
+//		Instrumentation:      telemetry.New(tracer, m),
+//	})
+//
+// The span of a call is named after the endpoint template, such as "GET /2/users/:id/following".
+// Tracer is small enough to be implemented by a few lines over OpenTelemetry or another tracing library.
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"github.com/michimani/gotwi"
+)
+
+// The keys of the span attributes.
+const (
+	AttributeMethod             = "http.method"
+	AttributeRoute              = "http.route"
+	AttributeStatusCode         = "http.status_code"
+	AttributeErrorCode          = "twitter.error_code"
+	AttributeRateLimitRemaining = "twitter.rate_limit.remaining"
+	AttributeCached             = "gotwi.cached"
+	AttributeStream             = "gotwi.stream"
+)
+
+// Tracer starts the spans, such as a wrapper of trace.Tracer of OpenTelemetry.
+type Tracer interface {
+	// Start starts the span of the name and returns the context that carries it.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a started span.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	// RecordError records the error of the call and sets the status of the span to error.
+	RecordError(err error)
+	End()
+}
+
+// Instrumentation implements gotwi.Instrumentation with the Tracer and the Metrics.
+// Either of them can be nil.
+type Instrumentation struct {
+	Tracer  Tracer
+	Metrics *Metrics
+}
+
+var _ gotwi.Instrumentation = (*Instrumentation)(nil)
+
+// New returns Instrumentation of the tracer and the metrics, either of which can be nil.
+func New(tracer Tracer, metrics *Metrics) *Instrumentation {
+	return &Instrumentation{Tracer: tracer, Metrics: metrics}
+}
+
+// StartCall starts the span of the call, and ends it and records the metrics when the call ends.
+func (in *Instrumentation) StartCall(ctx context.Context, call *gotwi.CallInfo) (context.Context, func(*gotwi.CallResult)) {
+	var span Span
+	if in.Tracer != nil {
+		ctx, span = in.Tracer.Start(ctx, call.Name())
+		span.SetAttribute(AttributeMethod, call.Method)
+		span.SetAttribute(AttributeRoute, call.Endpoint)
+		if call.Stream {
+			span.SetAttribute(AttributeStream, true)
+		}
+	}
+
+	return ctx, func(r *gotwi.CallResult) {
+		in.Metrics.record(call, r)
+		if span == nil {
+			return
+		}
+
+		if r.StatusCode > 0 {
+			span.SetAttribute(AttributeStatusCode, r.StatusCode)
+		}
+		if r.ErrorCode != "" {
+			span.SetAttribute(AttributeErrorCode, r.ErrorCode)
+		}
+		if r.RateLimit != nil {
+			span.SetAttribute(AttributeRateLimitRemaining, r.RateLimit.Remaining)
+		}
+		if r.Cached {
+			span.SetAttribute(AttributeCached, true)
+		}
+		if r.Err != nil {
+			span.RecordError(r.Err)
+		}
+		span.End()
+	}
+}
+
+// StreamConnected records the connection of the stream.
+func (in *Instrumentation) StreamConnected(call *gotwi.CallInfo) {
+	in.Metrics.streamConnected(call)
+}
+
+// StreamClosed records the close of the stream.
+func (in *Instrumentation) StreamClosed(call *gotwi.CallInfo, uptime time.Duration) {
+	in.Metrics.streamClosed(call, uptime)
+}
@@ -0,0 +1,166 @@
+package telemetry_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/gotwitest"
+	"github.com/michimani/gotwi/resources"
+	"github.com/michimani/gotwi/telemetry"
+	"github.com/michimani/gotwi/tweets"
+	ttypes "github.com/michimani/gotwi/tweets/types"
+	"github.com/michimani/gotwi/users"
+	"github.com/michimani/gotwi/users/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSpan struct {
+	name  string
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *testSpan) RecordError(err error)                      { s.err = err }
+func (s *testSpan) End()                                       { s.ended = true }
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, telemetry.Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := &testSpan{name: name, attrs: map[string]interface{}{}}
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+func Test_Instrumentation(t *testing.T) {
+	srv := gotwitest.NewServer()
+	t.Cleanup(srv.Close)
+	alice := srv.AddUser(resources.User{Username: gotwi.String("alice")})
+	bob := srv.AddUser(resources.User{Username: gotwi.String("bob")})
+	srv.Relate(gotwitest.RelationFollowing, *bob.ID, *alice.ID)
+	srv.SetRateLimit("GET", "/2/users/:id/followers", 15, time.Hour)
+	srv.AddStreamRule("hello", "")
+
+	tracer := &testTracer{}
+	m := telemetry.NewMetrics(nil)
+	c, err := srv.NewAppClient()
+	require.NoError(t, err)
+	c.Instrumentation = telemetry.New(tracer, m)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err := users.FollowsFollowers(ctx, c, &types.FollowsFollowersParams{ID: *alice.ID})
+		require.NoError(t, err)
+	}
+	srv.InjectFailure(gotwitest.Failure{
+		Method: "GET", Endpoint: "/2/users/:id/followers", StatusCode: http.StatusNotFound, Times: 1,
+		Body: map[string]interface{}{"title": "Not Found Error", "detail": "Could not find user.", "type": "https://api.twitter.com/2/problems/resource-not-found"},
+	})
+	_, err = users.FollowsFollowers(ctx, c, &types.FollowsFollowersParams{ID: *alice.ID})
+	require.Error(t, err)
+	srv.InjectFailure(gotwitest.Failure{
+		Method: "GET", Endpoint: "/2/users/:id/followers", StatusCode: http.StatusTooManyRequests, Times: 1,
+		Body: map[string]interface{}{"errors": []map[string]interface{}{{"message": "Rate limit exceeded", "code": 88}}},
+	})
+	_, err = users.FollowsFollowers(ctx, c, &types.FollowsFollowersParams{ID: *alice.ID})
+	require.Error(t, err)
+
+	stream, err := tweets.FilteredStreamSearch(ctx, c, &ttypes.FilteredStreamSearchParams{})
+	require.NoError(t, err)
+	srv.AddTweet(resources.Tweet{Text: gotwi.String("hello"), AuthorID: alice.ID})
+	require.True(t, stream.Next())
+	assert.Equal(t, 1, m.Snapshot().Streams["GET /2/tweets/search/stream"].Connected)
+	stream.Close()
+	stream.Close()
+
+	const followers = "GET /2/users/:id/followers"
+
+	require.Len(t, tracer.spans, 5)
+	for _, s := range tracer.spans[:4] {
+		assert.Equal(t, followers, s.name)
+		assert.Equal(t, "/2/users/:id/followers", s.attrs[telemetry.AttributeRoute])
+		assert.True(t, s.ended)
+	}
+	assert.Equal(t, 200, tracer.spans[0].attrs[telemetry.AttributeStatusCode])
+	assert.Equal(t, 14, tracer.spans[0].attrs[telemetry.AttributeRateLimitRemaining])
+	assert.NoError(t, tracer.spans[0].err)
+	assert.Equal(t, "https://api.twitter.com/2/problems/resource-not-found", tracer.spans[2].attrs[telemetry.AttributeErrorCode])
+	assert.Error(t, tracer.spans[2].err)
+	assert.Equal(t, "88", tracer.spans[3].attrs[telemetry.AttributeErrorCode])
+	assert.Equal(t, "GET /2/tweets/search/stream", tracer.spans[4].name)
+	assert.Equal(t, true, tracer.spans[4].attrs[telemetry.AttributeStream])
+
+	s := m.Snapshot()
+	assert.Equal(t, []telemetry.RequestCount{
+		{Endpoint: "GET /2/tweets/search/stream", StatusCode: 200, Count: 1},
+		{Endpoint: followers, StatusCode: 200, Count: 2},
+		{Endpoint: followers, StatusCode: 404, ErrorCode: "https://api.twitter.com/2/problems/resource-not-found", Count: 1},
+		{Endpoint: followers, StatusCode: 429, ErrorCode: "88", Count: 1},
+	}, s.Requests)
+
+	h := m.Latency(followers)
+	assert.Equal(t, int64(4), h.Count)
+	assert.Len(t, h.Counts, len(telemetry.DefaultLatencyBuckets)+1)
+	sum := int64(0)
+	for _, n := range h.Counts {
+		sum += n
+	}
+	assert.Equal(t, h.Count, sum)
+
+	rl, ok := m.RateLimit(followers)
+	require.True(t, ok)
+	assert.Equal(t, 15, rl.Limit)
+	// the injected failures do not consume the rate limit
+	assert.Equal(t, 13, rl.Remaining)
+
+	st := s.Streams["GET /2/tweets/search/stream"]
+	assert.Equal(t, 0, st.Connected)
+	assert.Equal(t, int64(1), st.Connections)
+	assert.Greater(t, int64(st.ClosedUptime), int64(0))
+	assert.Equal(t, time.Duration(0), m.StreamUptime("GET /2/tweets/search/stream"))
+
+	var decoded telemetry.Snapshot
+	require.NoError(t, json.Unmarshal([]byte(m.Var().String()), &decoded))
+	assert.Equal(t, s.Requests, decoded.Requests)
+}
+
+func Test_Metrics_buckets(t *testing.T) {
+	m := telemetry.NewMetrics([]time.Duration{time.Second, time.Millisecond})
+	in := telemetry.New(nil, m)
+	call := &gotwi.CallInfo{Method: "GET", Endpoint: "/2/users/me"}
+
+	for _, d := range []time.Duration{time.Millisecond, 10 * time.Millisecond, time.Minute} {
+		_, end := in.StartCall(context.Background(), call)
+		end(&gotwi.CallResult{StatusCode: 200, Duration: d})
+	}
+
+	h := m.Latency("GET /2/users/me")
+	assert.Equal(t, []time.Duration{time.Millisecond, time.Second}, h.Buckets)
+	assert.Equal(t, []int64{1, 1, 1}, h.Counts)
+	assert.Equal(t, time.Minute+11*time.Millisecond, h.Sum)
+}
+
+func Test_Instrumentation_nil(t *testing.T) {
+	in := telemetry.New(nil, nil)
+	call := &gotwi.CallInfo{Method: "GET", Endpoint: "/2/tweets/search/stream", Stream: true}
+	_, end := in.StartCall(context.Background(), call)
+	end(&gotwi.CallResult{})
+	in.StreamConnected(call)
+	in.StreamClosed(call, time.Second)
+
+	var m *telemetry.Metrics
+	assert.Empty(t, m.Snapshot().Requests)
+	assert.Equal(t, time.Duration(0), m.StreamUptime("GET /2/tweets/search/stream"))
+}
@@ -1,13 +1,21 @@
 package tweets
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"io"
 
 	"github.com/michimani/gotwi"
 	"github.com/michimani/gotwi/tweets/types"
 )
 
-const FilteredStreamRulesGetEndpoint = "https://api.twitter.com/2/tweets/search/stream/rules"
+const (
+	FilteredStreamRulesGetEndpoint  = "https://api.twitter.com/2/tweets/search/stream/rules"
+	FilteredStreamRulesPostEndpoint = "https://api.twitter.com/2/tweets/search/stream/rules"
+	FilteredStreamSearchEndpoint    = "https://api.twitter.com/2/tweets/search/stream"
+)
 
 // Return a list of rules currently active on the streaming endpoint, either as a list or individually.
 // https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/get-tweets-search-stream-rules
@@ -19,3 +27,85 @@ func FilteredStreamRulesGet(ctx context.Context, c *gotwi.GotwiClient, p *types.
 
 	return res, nil
 }
+
+// Add or delete rules to your stream.
+// https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/post-tweets-search-stream-rules
+func FilteredStreamRulesPost(ctx context.Context, c *gotwi.GotwiClient, p *types.FilteredStreamRulesPostParams) (*types.FilteredStreamRulesPostResponse, error) {
+	res := &types.FilteredStreamRulesPostResponse{}
+	if err := c.CallAPI(ctx, FilteredStreamRulesPostEndpoint, "POST", p, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Streams Tweets in real-time based on a specific set of filter rules.
+// The stream is read with Next until it returns false, and must be closed.
+// https://developer.twitter.com/en/docs/twitter-api/tweets/filtered-stream/api-reference/get-tweets-search-stream
+func FilteredStreamSearch(ctx context.Context, c *gotwi.GotwiClient, p *types.FilteredStreamSearchParams) (*FilteredStream, error) {
+	res, err := c.CallStreamAPI(ctx, FilteredStreamSearchEndpoint, "GET", p)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFilteredStream(res.Body), nil
+}
+
+// maxStreamMessageSize is the maximum size of a message of the stream, which includes the expansions.
+const maxStreamMessageSize = 10 * 1024 * 1024
+
+// FilteredStream reads the messages of the filtered stream, which are delimited by new lines.
+type FilteredStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+	raw     []byte
+}
+
+// NewFilteredStream returns a FilteredStream that reads the body.
+func NewFilteredStream(body io.ReadCloser) *FilteredStream {
+	s := bufio.NewScanner(body)
+	s.Buffer(make([]byte, 64*1024), maxStreamMessageSize)
+
+	return &FilteredStream{body: body, scanner: s}
+}
+
+// Next reads the next message and reports whether there is one.
+// The keep-alive signals, which are empty lines, are skipped.
+func (s *FilteredStream) Next() bool {
+	for s.scanner.Scan() {
+		line := bytes.TrimSpace(s.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		s.raw = line
+		return true
+	}
+
+	s.raw = nil
+	return false
+}
+
+// Raw returns the JSON of the current message. It is valid until the next call of Next.
+func (s *FilteredStream) Raw() []byte {
+	return s.raw
+}
+
+// Response decodes the current message.
+func (s *FilteredStream) Response() (*types.FilteredStreamSearchResponse, error) {
+	res := &types.FilteredStreamSearchResponse{}
+	if err := json.Unmarshal(s.raw, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Err returns the error that stopped Next, or nil if the server closed the stream.
+func (s *FilteredStream) Err() error {
+	return s.scanner.Err()
+}
+
+func (s *FilteredStream) Close() error {
+	return s.body.Close()
+}
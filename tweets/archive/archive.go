@@ -0,0 +1,431 @@
+// Package archive fetches the full-archive search results concurrently by splitting the time range into windows.
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/job"
+	"github.com/michimani/gotwi/resources"
+	"github.com/michimani/gotwi/tweets"
+	"github.com/michimani/gotwi/tweets/types"
+)
+
+const (
+	// DefaultInterval is the interval between requests of all windows.
+	// The full-archive search allows 1 request per second.
+	DefaultInterval    = time.Second
+	DefaultShards      = 8
+	DefaultConcurrency = 4
+)
+
+type FetchInput struct {
+	// Params is the template of each request. StartTime, EndTime and NextToken are set for each window.
+	Params    *types.SearchTweetsAllParams
+	StartTime time.Time
+	EndTime   time.Time
+	// Shards is the number of windows to split the range into.
+	Shards int
+	// Concurrency is the number of windows fetched at the same time.
+	Concurrency int
+	// Interval is the minimum interval between requests, shared by all windows including the counts requests.
+	Interval time.Duration
+	// Store saves the progress by Key after each page is handled.
+	// If it has the progress of an interrupted run, the run resumes from it without estimating the range again.
+	// Fetch returns an error if the progress is of another Query, or of another range when StartTime and EndTime are set.
+	Store job.CheckpointStore
+	Key   string
+	// OnProgress is called with a snapshot of the progress after each page is handled.
+	OnProgress func(p *Progress) error
+	// OnPage is called with the tweets of each page that are not handled yet. Calls are not concurrent.
+	// The tweets are deduplicated by ID only within a run, because the IDs are not saved in the Store.
+	// So the delivery is at-least-once after a resume: a tweet passed before the interruption can be passed again
+	// if it is returned again after it, and so can the tweets of the page handled right before a crash,
+	// whose progress is not saved yet. Make OnPage idempotent, such as by upserting the tweets by ID.
+	OnPage func(tweets []resources.Tweet, includes resources.Includes) error
+}
+
+type FetchOutput struct {
+	Progress *Progress
+	// Fetched is the number of tweets passed to OnPage.
+	Fetched int
+	// Duplicated is the number of tweets dropped because they were already passed to OnPage in this run.
+	Duplicated int
+}
+
+// Progress is the state of the windows. It can be encoded to JSON.
+type Progress struct {
+	// Query and the range are of the run that created the progress, to be checked on resume.
+	Query     string    `json:"query"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Windows   []Window  `json:"windows"`
+}
+
+// Window is a part of the time range. The range is [Start, End).
+type Window struct {
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+	EstimatedCount int       `json:"estimated_count"`
+	// NextToken is the token of the next page. It is empty for the first page.
+	NextToken string `json:"next_token,omitempty"`
+	Done      bool   `json:"done"`
+}
+
+func (p *Progress) clone() *Progress {
+	c := *p
+	c.Windows = make([]Window, len(p.Windows))
+	copy(c.Windows, p.Windows)
+	return &c
+}
+
+// check returns an error if the progress is not of the query and the range of the input.
+func (p *Progress) check(in *FetchInput) error {
+	if p.Query != in.Params.Query {
+		return fmt.Errorf("Progress of Key '%s' is of another Query '%s'. Use another Key to fetch the new query.", in.Key, p.Query)
+	}
+	if (!in.StartTime.IsZero() && !in.StartTime.Equal(p.StartTime)) || (!in.EndTime.IsZero() && !in.EndTime.Equal(p.EndTime)) {
+		return fmt.Errorf("Progress of Key '%s' is of another range from %s to %s. Use another Key to fetch the new range.",
+			in.Key, p.StartTime.Format(time.RFC3339), p.EndTime.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Done reports whether all windows are fetched.
+func (p *Progress) Done() bool {
+	for _, w := range p.Windows {
+		if !w.Done {
+			return false
+		}
+	}
+	return true
+}
+
+// Fetch estimates the volume of the range by TweetCountsAll, splits it into windows with balanced tweet counts,
+// and fetches the windows by SearchTweetsAll concurrently.
+func Fetch(ctx context.Context, c *gotwi.GotwiClient, in *FetchInput) (*FetchOutput, error) {
+	if in == nil || in.Params == nil {
+		return nil, fmt.Errorf("FetchInput and Params are required.")
+	}
+	if in.Params.Query == "" {
+		return nil, fmt.Errorf("Query is required.")
+	}
+	if in.OnPage == nil {
+		return nil, fmt.Errorf("OnPage is required.")
+	}
+	if in.Store != nil && in.Key == "" {
+		return nil, fmt.Errorf("Key is required to use Store.")
+	}
+
+	f := &fetcher{
+		client:  c,
+		in:      in,
+		limiter: newLimiter(durationOrDefault(in.Interval, DefaultInterval)),
+		seen:    map[string]struct{}{},
+	}
+
+	progress, err := f.loadProgress(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if progress == nil {
+		if !in.StartTime.Before(in.EndTime) {
+			return nil, fmt.Errorf("StartTime must be before EndTime.")
+		}
+		counts, err := f.countDaily(ctx)
+		if err != nil {
+			return nil, err
+		}
+		progress = &Progress{
+			Query:     in.Params.Query,
+			StartTime: in.StartTime,
+			EndTime:   in.EndTime,
+			Windows:   SplitWindows(in.StartTime, in.EndTime, counts, intOrDefault(in.Shards, DefaultShards)),
+		}
+	} else if err := progress.check(in); err != nil {
+		return nil, err
+	}
+	f.progress = progress
+
+	err = f.fetchWindows(ctx, intOrDefault(in.Concurrency, DefaultConcurrency))
+	return &FetchOutput{Progress: progress.clone(), Fetched: f.fetched, Duplicated: f.duplicated}, err
+}
+
+// SplitWindows splits [start, end) at the boundaries of the counts so that each window has about the same number of tweets.
+// The counts must be sorted by time. It returns at most shards windows.
+func SplitWindows(start, end time.Time, counts []resources.TweetCount, shards int) []Window {
+	if shards < 1 {
+		shards = 1
+	}
+
+	inRange := []resources.TweetCount{}
+	total := 0
+	for _, c := range counts {
+		if c.Start == nil || c.End == nil || !c.Start.Before(end) || !c.End.After(start) {
+			continue
+		}
+		inRange = append(inRange, c)
+		total += gotwi.IntValue(c.TweetCount)
+	}
+
+	windows := []Window{}
+	current := Window{Start: start}
+	cumulative := 0
+	for _, c := range inRange {
+		n := gotwi.IntValue(c.TweetCount)
+		current.EstimatedCount += n
+		cumulative += n
+
+		// cut when the cumulative count reaches the share of the windows so far
+		threshold := float64(total) * float64(len(windows)+1) / float64(shards)
+		if len(windows) < shards-1 && total > 0 && float64(cumulative) >= threshold && c.End.Before(end) {
+			current.End = *c.End
+			windows = append(windows, current)
+			current = Window{Start: *c.End}
+		}
+	}
+	current.End = end
+	windows = append(windows, current)
+
+	return windows
+}
+
+type fetcher struct {
+	client  *gotwi.GotwiClient
+	in      *FetchInput
+	limiter *limiter
+
+	// mu guards the fields below and serializes the calls of OnPage and OnProgress.
+	mu         sync.Mutex
+	progress   *Progress
+	seen       map[string]struct{}
+	fetched    int
+	duplicated int
+	pages      int
+}
+
+func (f *fetcher) loadProgress(ctx context.Context) (*Progress, error) {
+	if f.in.Store == nil {
+		return nil, nil
+	}
+
+	cp, err := f.in.Store.Load(ctx, f.in.Key)
+	if err != nil || cp == nil || len(cp.Data) == 0 {
+		return nil, err
+	}
+
+	p := &Progress{}
+	if err := json.Unmarshal(cp.Data, p); err != nil {
+		return nil, err
+	}
+	f.pages = cp.Pages
+	return p, nil
+}
+
+// saveProgress saves the progress to the Store. It must be called with mu held.
+func (f *fetcher) saveProgress(ctx context.Context) error {
+	if f.in.Store == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(f.progress)
+	if err != nil {
+		return err
+	}
+
+	f.pages++
+	start, end := f.progress.Windows[0].Start, f.progress.Windows[len(f.progress.Windows)-1].End
+	return f.in.Store.Save(ctx, f.in.Key, &job.Checkpoint{
+		StartTime: &start,
+		EndTime:   &end,
+		Pages:     f.pages,
+		Done:      f.progress.Done(),
+		Data:      data,
+		UpdatedAt: time.Now(),
+	})
+}
+
+func (f *fetcher) countDaily(ctx context.Context) ([]resources.TweetCount, error) {
+	counts := []resources.TweetCount{}
+	start, end := f.in.StartTime, f.in.EndTime
+	nextToken := ""
+	for {
+		p := &types.TweetCountsAllParams{
+			Query:       f.in.Params.Query,
+			StartTime:   &start,
+			EndTime:     &end,
+			Granularity: types.TweetCountsGranularityDay,
+			NextToken:   nextToken,
+		}
+
+		var res *types.TweetCountsAllResponse
+		err := f.call(ctx, func() error {
+			var err error
+			res, err = tweets.TweetCountsAll(ctx, f.client, p)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, res.Data...)
+		nextToken = gotwi.StringValue(res.Meta.NextToken)
+		if nextToken == "" {
+			break
+		}
+	}
+
+	sortCounts(counts)
+	return counts, nil
+}
+
+func (f *fetcher) fetchWindows(ctx context.Context, concurrency int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	indexes := make(chan int)
+	errs := make(chan error, concurrency)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				if err := f.fetchWindow(ctx, idx); err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+	for i, w := range f.progress.Windows {
+		if w.Done {
+			continue
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	close(errs)
+
+	if err, ok := <-errs; ok {
+		return err
+	}
+	return ctx.Err()
+}
+
+func (f *fetcher) fetchWindow(ctx context.Context, idx int) error {
+	f.mu.Lock()
+	w := f.progress.Windows[idx]
+	f.mu.Unlock()
+
+	for {
+		p := *f.in.Params
+		p.StartTime = &w.Start
+		p.EndTime = &w.End
+		p.NextToken = w.NextToken
+
+		var res *types.SearchTweetsAllResponse
+		err := f.call(ctx, func() error {
+			var err error
+			res, err = tweets.SearchTweetsAll(ctx, f.client, &p)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		w.NextToken = gotwi.StringValue(res.Meta.NextToken)
+		w.Done = w.NextToken == ""
+		if err := f.handlePage(ctx, idx, w, res); err != nil {
+			return err
+		}
+		if w.Done {
+			return nil
+		}
+	}
+}
+
+// handlePage passes the new tweets to OnPage and then records the window, so that a resumed run does not output them again.
+func (f *fetcher) handlePage(ctx context.Context, idx int, w Window, res *types.SearchTweetsAllResponse) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	newTweets := []resources.Tweet{}
+	for _, t := range res.Data {
+		id := gotwi.StringValue(t.ID)
+		if _, ok := f.seen[id]; ok {
+			f.duplicated++
+			continue
+		}
+		f.seen[id] = struct{}{}
+		newTweets = append(newTweets, t)
+	}
+
+	if len(newTweets) > 0 {
+		if err := f.in.OnPage(newTweets, res.Includes); err != nil {
+			return err
+		}
+		f.fetched += len(newTweets)
+	}
+
+	f.progress.Windows[idx] = w
+	if err := f.saveProgress(ctx); err != nil {
+		return err
+	}
+	if f.in.OnProgress != nil {
+		return f.in.OnProgress(f.progress.clone())
+	}
+	return nil
+}
+
+// call calls fn under the rate budget, and retries it after the reset time when it is rate limited.
+func (f *fetcher) call(ctx context.Context, fn func() error) error {
+	for {
+		if err := f.limiter.wait(ctx); err != nil {
+			return err
+		}
+
+		err := fn()
+		wait, limited := job.RateLimitWait(err)
+		if !limited {
+			return err
+		}
+
+		f.client.Debug("gotwi: retry after rate limit", "key", f.in.Key, "wait", wait)
+		f.limiter.pause(wait)
+	}
+}
+
+func sortCounts(counts []resources.TweetCount) {
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Start == nil || counts[j].Start == nil {
+			return counts[j].Start != nil
+		}
+		return counts[i].Start.Before(*counts[j].Start)
+	})
+}
+
+func durationOrDefault(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
+}
+
+func intOrDefault(i, def int) int {
+	if i <= 0 {
+		return def
+	}
+	return i
+}
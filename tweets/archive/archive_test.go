@@ -0,0 +1,298 @@
+package archive_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/internal/testutil"
+	"github.com/michimani/gotwi/job"
+	"github.com/michimani/gotwi/resources"
+	"github.com/michimani/gotwi/tweets/archive"
+	"github.com/michimani/gotwi/tweets/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var day0 = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func day(n int) time.Time {
+	return day0.AddDate(0, 0, n)
+}
+
+func dailyCounts(ns ...int) []resources.TweetCount {
+	counts := []resources.TweetCount{}
+	for i, n := range ns {
+		s, e := day(i), day(i+1)
+		counts = append(counts, resources.TweetCount{Start: &s, End: &e, TweetCount: gotwi.Int(n)})
+	}
+	return counts
+}
+
+func Test_SplitWindows(t *testing.T) {
+	cases := []struct {
+		name   string
+		end    time.Time
+		counts []resources.TweetCount
+		shards int
+		expect []archive.Window
+	}{
+		{
+			name:   "balanced",
+			end:    day(4),
+			counts: dailyCounts(10, 10, 10, 10),
+			shards: 2,
+			expect: []archive.Window{
+				{Start: day(0), End: day(2), EstimatedCount: 20},
+				{Start: day(2), End: day(4), EstimatedCount: 20},
+			},
+		},
+		{
+			name:   "skewed",
+			end:    day(4),
+			counts: dailyCounts(30, 1, 1, 8),
+			shards: 2,
+			expect: []archive.Window{
+				{Start: day(0), End: day(1), EstimatedCount: 30},
+				{Start: day(1), End: day(4), EstimatedCount: 10},
+			},
+		},
+		{
+			name:   "fewer buckets than shards",
+			end:    day(2),
+			counts: dailyCounts(5, 5),
+			shards: 4,
+			expect: []archive.Window{
+				{Start: day(0), End: day(1), EstimatedCount: 5},
+				{Start: day(1), End: day(2), EstimatedCount: 5},
+			},
+		},
+		{
+			name:   "no tweets",
+			end:    day(2),
+			counts: dailyCounts(0, 0),
+			shards: 4,
+			expect: []archive.Window{
+				{Start: day(0), End: day(2)},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			ws := archive.SplitWindows(day(0), c.end, c.counts, c.shards)
+			assert.Equal(tt, c.expect, ws)
+		})
+	}
+}
+
+func Test_Fetch(t *testing.T) {
+	s := &archiveServer{
+		counts: []int{2, 2},
+		// day 0 has two pages, and the tweet "2" is returned again in day 1
+		pages: map[string][]string{
+			day(0).Format(time.RFC3339) + ":":   {"1"},
+			day(0).Format(time.RFC3339) + ":p2": {"2"},
+			day(1).Format(time.RFC3339) + ":":   {"2", "3"},
+		},
+		next:         map[string]string{day(0).Format(time.RFC3339) + ":": "p2"},
+		rateLimitAt:  2,
+		requestTimes: []time.Time{},
+	}
+	c := testutil.NewClient(t, s)
+	l := &testutil.Logger{}
+	c.Logger = l
+
+	got := []string{}
+	progresses := []*archive.Progress{}
+	out, err := archive.Fetch(context.Background(), c, &archive.FetchInput{
+		Params:    &types.SearchTweetsAllParams{Query: "gotwi"},
+		StartTime: day(0),
+		EndTime:   day(2),
+		Shards:    2,
+		Interval:  10 * time.Millisecond,
+		OnPage: func(ts []resources.Tweet, _ resources.Includes) error {
+			for _, t := range ts {
+				got = append(got, gotwi.StringValue(t.ID))
+			}
+			return nil
+		},
+		OnProgress: func(p *archive.Progress) error {
+			progresses = append(progresses, p)
+			return nil
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1", "2", "3"}, got)
+	assert.Equal(t, 3, out.Fetched)
+	assert.Equal(t, 1, out.Duplicated)
+	assert.True(t, out.Progress.Done())
+	assert.Len(t, out.Progress.Windows, 2)
+	assert.Len(t, progresses, 3)
+	assert.Contains(t, l.Messages(), "gotwi: retry after rate limit")
+
+	// requests are not sent more often than the interval, even after a 429 response
+	n := len(s.requestTimes)
+	span := s.requestTimes[n-1].Sub(s.requestTimes[0])
+	assert.GreaterOrEqual(t, span, time.Duration(n-1)*8*time.Millisecond)
+}
+
+func Test_Fetch_resume(t *testing.T) {
+	s := &archiveServer{
+		pages: map[string][]string{
+			day(0).Format(time.RFC3339) + ":p2": {"2"},
+		},
+	}
+	c := testutil.NewClient(t, s)
+
+	store := job.NewMemoryCheckpointStore()
+	data, _ := json.Marshal(&archive.Progress{Query: "gotwi", StartTime: day(0), EndTime: day(2), Windows: []archive.Window{
+		{Start: day(0), End: day(1), NextToken: "p2"},
+		{Start: day(1), End: day(2), Done: true},
+	}})
+	store.Save(context.Background(), "archive", &job.Checkpoint{Pages: 2, Data: data})
+
+	got := []string{}
+	out, err := archive.Fetch(context.Background(), c, &archive.FetchInput{
+		Params:   &types.SearchTweetsAllParams{Query: "gotwi"},
+		Interval: time.Millisecond,
+		Store:    store,
+		Key:      "archive",
+		OnPage: func(ts []resources.Tweet, _ resources.Includes) error {
+			for _, t := range ts {
+				got = append(got, gotwi.StringValue(t.ID))
+			}
+			return nil
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2"}, got)
+	assert.True(t, out.Progress.Done())
+	assert.Equal(t, 0, s.countRequests)
+
+	cp, err := store.Load(context.Background(), "archive")
+	assert.NoError(t, err)
+	assert.True(t, cp.Done)
+	assert.Equal(t, 3, cp.Pages)
+	assert.Equal(t, day(0), *cp.StartTime)
+	assert.Equal(t, day(2), *cp.EndTime)
+}
+
+func Test_Fetch_resumeMismatch(t *testing.T) {
+	cases := []struct {
+		name      string
+		query     string
+		startTime time.Time
+		endTime   time.Time
+		expect    string
+	}{
+		{
+			name:   "another query",
+			query:  "golang",
+			expect: "Progress of Key 'archive' is of another Query 'gotwi'. Use another Key to fetch the new query.",
+		},
+		{
+			name:      "another start time",
+			query:     "gotwi",
+			startTime: day(1),
+			endTime:   day(2),
+			expect:    "Progress of Key 'archive' is of another range from 2020-01-01T00:00:00Z to 2020-01-03T00:00:00Z. Use another Key to fetch the new range.",
+		},
+		{
+			name:    "another end time",
+			query:   "gotwi",
+			endTime: day(3),
+			expect:  "Progress of Key 'archive' is of another range from 2020-01-01T00:00:00Z to 2020-01-03T00:00:00Z. Use another Key to fetch the new range.",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			s := &archiveServer{}
+			client := testutil.NewClient(tt, s)
+
+			store := job.NewMemoryCheckpointStore()
+			data, _ := json.Marshal(&archive.Progress{Query: "gotwi", StartTime: day(0), EndTime: day(2), Windows: []archive.Window{
+				{Start: day(0), End: day(2), NextToken: "p2"},
+			}})
+			store.Save(context.Background(), "archive", &job.Checkpoint{Pages: 1, Data: data})
+
+			out, err := archive.Fetch(context.Background(), client, &archive.FetchInput{
+				Params:    &types.SearchTweetsAllParams{Query: c.query},
+				StartTime: c.startTime,
+				EndTime:   c.endTime,
+				Interval:  time.Millisecond,
+				Store:     store,
+				Key:       "archive",
+				OnPage:    func([]resources.Tweet, resources.Includes) error { return nil },
+			})
+			assert.Nil(tt, out)
+			assert.EqualError(tt, err, c.expect)
+			assert.Equal(tt, 0, s.searches+s.countRequests)
+		})
+	}
+}
+
+type archiveServer struct {
+	mu            sync.Mutex
+	counts        []int
+	pages         map[string][]string // "start_time:next_token" -> tweet IDs
+	next          map[string]string
+	rateLimitAt   int // the n-th search request returns 429
+	searches      int
+	countRequests int
+	requestTimes  []time.Time
+}
+
+func (s *archiveServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requestTimes = append(s.requestTimes, time.Now())
+	w.Header().Set("Content-Type", "application/json")
+	q := r.URL.Query()
+
+	if r.URL.Path == "/2/tweets/counts/all" {
+		s.countRequests++
+		data := ""
+		for i, n := range s.counts {
+			if i > 0 {
+				data += ","
+			}
+			data += fmt.Sprintf(`{"start":"%s","end":"%s","tweet_count":%d}`, day(i).Format(time.RFC3339), day(i+1).Format(time.RFC3339), n)
+		}
+		fmt.Fprintf(w, `{"data":[%s],"meta":{"total_tweet_count":0}}`, data)
+		return
+	}
+
+	s.searches++
+	if s.searches == s.rateLimitAt {
+		w.Header().Set("X-Rate-Limit-Limit", "300")
+		w.Header().Set("X-Rate-Limit-Remaining", "0")
+		w.Header().Set("X-Rate-Limit-Reset", strconv.FormatInt(time.Now().Unix(), 10))
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"title":"Too Many Requests","detail":"Too Many Requests","type":"about:blank","status":429}`)
+		return
+	}
+
+	key := q.Get("start_time") + ":" + q.Get("next_token")
+	data := ""
+	for i, id := range s.pages[key] {
+		if i > 0 {
+			data += ","
+		}
+		data += fmt.Sprintf(`{"id":"%s","text":"text"}`, id)
+	}
+	meta := fmt.Sprintf(`"result_count":%d`, len(s.pages[key]))
+	if next, ok := s.next[key]; ok {
+		meta += fmt.Sprintf(`,"next_token":"%s"`, next)
+	}
+	fmt.Fprintf(w, `{"data":[%s],"meta":{%s}}`, data, meta)
+}
@@ -0,0 +1,129 @@
+package query
+
+import "strings"
+
+func And(nodes ...Node) Group {
+	return Group{Op: BoolOpAnd, Children: nodes}
+}
+
+func Or(nodes ...Node) Group {
+	return Group{Op: BoolOpOr, Children: nodes}
+}
+
+func Negate(n Node) Not {
+	return Not{Node: n}
+}
+
+func Word(s string) Keyword {
+	return Keyword{Value: s}
+}
+
+func ExactPhrase(s string) Phrase {
+	return Phrase{Value: s}
+}
+
+// Mention matches tweets that mention the user. A leading "@" of the username is ignored.
+func Mention(username string) Entity {
+	return Entity{Symbol: EntitySymbolMention, Value: strings.TrimPrefix(username, "@")}
+}
+
+// Hashtag matches tweets with the hashtag. A leading "#" of the tag is ignored.
+func Hashtag(tag string) Entity {
+	return Entity{Symbol: EntitySymbolHashtag, Value: strings.TrimPrefix(tag, "#")}
+}
+
+// Cashtag matches tweets with the cashtag. A leading "$" of the tag is ignored.
+func Cashtag(tag string) Entity {
+	return Entity{Symbol: EntitySymbolCashtag, Value: strings.TrimPrefix(tag, "$")}
+}
+
+// Op returns an operator clause for the operators that have no dedicated constructor.
+func Op(name, value string) Operator {
+	return Operator{Name: name, Value: value}
+}
+
+func From(username string) Operator {
+	return Op("from", strings.TrimPrefix(username, "@"))
+}
+
+func To(username string) Operator {
+	return Op("to", strings.TrimPrefix(username, "@"))
+}
+
+func RetweetsOf(username string) Operator {
+	return Op("retweets_of", strings.TrimPrefix(username, "@"))
+}
+
+// Lang matches tweets classified as the BCP 47 language, like "en" or "ja".
+func Lang(code string) Operator {
+	return Op("lang", code)
+}
+
+func URL(u string) Operator {
+	return Op("url", u)
+}
+
+func ConversationID(id string) Operator {
+	return Op("conversation_id", id)
+}
+
+// PlaceCountry matches tweets tagged with a place in the country of the ISO alpha-2 code.
+func PlaceCountry(code string) Operator {
+	return Op("place_country", code)
+}
+
+func Radius(longitude, latitude, radius float64, unit DistanceUnit) PointRadius {
+	return PointRadius{Longitude: longitude, Latitude: latitude, Radius: radius, Unit: unit}
+}
+
+func Is(name string) Operator {
+	return Op("is", name)
+}
+
+func IsRetweet() Operator {
+	return Is("retweet")
+}
+
+func IsReply() Operator {
+	return Is("reply")
+}
+
+func IsQuote() Operator {
+	return Is("quote")
+}
+
+func IsVerified() Operator {
+	return Is("verified")
+}
+
+func Has(name string) Operator {
+	return Op("has", name)
+}
+
+func HasMedia() Operator {
+	return Has("media")
+}
+
+func HasLinks() Operator {
+	return Has("links")
+}
+
+func HasImages() Operator {
+	return Has("images")
+}
+
+func HasVideos() Operator {
+	return Has("videos")
+}
+
+func HasHashtags() Operator {
+	return Has("hashtags")
+}
+
+func HasMentions() Operator {
+	return Has("mentions")
+}
+
+func HasGeo() Operator {
+	return Has("geo")
+}
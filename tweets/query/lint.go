@@ -0,0 +1,202 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+func (s Severity) String() string {
+	return string(s)
+}
+
+// Issue is a problem of a query found by Lint.
+type Issue struct {
+	// Rule is the name of the lint rule, like "syntax" or "duplicate-clause".
+	Rule     string
+	Severity Severity
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", i.Severity, i.Message, i.Rule)
+}
+
+const (
+	RuleSyntax             = "syntax"
+	RuleInvalidClause      = "invalid-clause"
+	RuleStandaloneNegation = "standalone-negation"
+	RuleAcademicOperator   = "academic-operator"
+	RuleUnknownOperator    = "unknown-operator"
+	RuleOrPrecedence       = "or-precedence"
+	RuleDuplicateClause    = "duplicate-clause"
+	RuleTooLong            = "too-long"
+	RuleInvalidTrack       = "invalid-track"
+)
+
+var knownOperators = map[string]struct{}{
+	"from": {}, "to": {}, "url": {}, "retweets_of": {}, "context": {}, "entity": {},
+	"conversation_id": {}, "in_reply_to_tweet_id": {}, "retweets_of_tweet_id": {}, "quotes_of_tweet_id": {},
+	"lang": {}, "is": {}, "has": {}, "sample": {}, "list": {}, "followers_count": {}, "url_title": {},
+	"url_description": {}, "url_contains": {}, "source": {},
+	"place": {}, "place_country": {}, "point_radius": {}, "bounding_box": {},
+	"bio": {}, "bio_name": {}, "bio_location": {},
+}
+
+var academicOperators = map[string]struct{}{
+	"place": {}, "place_country": {}, "point_radius": {}, "bounding_box": {},
+	"bio": {}, "bio_name": {}, "bio_location": {},
+}
+
+var knownIsValues = map[string]struct{}{
+	"retweet": {}, "reply": {}, "quote": {}, "verified": {}, "nullcast": {},
+}
+
+var knownHasValues = map[string]struct{}{
+	"hashtags": {}, "cashtags": {}, "links": {}, "mentions": {}, "media": {}, "images": {}, "videos": {}, "geo": {},
+}
+
+// Lint parses the query and reports the problems for the track.
+// Issues with SeverityError make the API reject the query, and issues with SeverityWarning are likely mistakes.
+func Lint(q string, t Track) []Issue {
+	issues := []Issue{}
+	if !t.Valid() {
+		return append(issues, Issue{Rule: RuleInvalidTrack, Severity: SeverityError, Message: fmt.Sprintf("Track '%s' is invalid.", t)})
+	}
+
+	n, info, err := parse(q)
+	if err != nil {
+		return append(issues, Issue{Rule: RuleSyntax, Severity: SeverityError, Message: err.Error()})
+	}
+
+	if err := validateNode(n); err != nil {
+		issues = append(issues, Issue{Rule: RuleInvalidClause, Severity: SeverityError, Message: err.Error()})
+	}
+	if !hasPositive(n) {
+		issues = append(issues, Issue{
+			Rule:     RuleStandaloneNegation,
+			Severity: SeverityError,
+			Message:  "Query must have at least one clause that is not negated.",
+		})
+	}
+
+	walk(n, func(c Node) {
+		issues = append(issues, lintClause(c, t)...)
+	})
+
+	sort.Ints(info.implicitAndInOr)
+	for _, pos := range info.implicitAndInOr {
+		issues = append(issues, Issue{
+			Rule:     RuleOrPrecedence,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("Clauses AND-ed without parentheses are evaluated before OR (at %d). Add parentheses to make the precedence explicit.", pos),
+		})
+	}
+
+	if l := utf8.RuneCountInString(q); l > t.MaxLength() {
+		issues = append(issues, Issue{
+			Rule:     RuleTooLong,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("Query is too long. length=%d maxLength=%d track=%s", l, t.MaxLength(), t),
+		})
+	}
+
+	return issues
+}
+
+// HasError reports whether the issues have an issue with SeverityError.
+func HasError(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func lintClause(n Node, t Track) []Issue {
+	issues := []Issue{}
+	academic := t == TrackAcademic || t == TrackEnterprise
+
+	switch v := n.(type) {
+	case Operator:
+		if _, ok := knownOperators[v.Name]; !ok {
+			issues = append(issues, Issue{
+				Rule:     RuleUnknownOperator,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("Operator '%s' is unknown. Quote the value if it is a keyword.", v.Name),
+			})
+		}
+		if v.Name == "is" {
+			if _, ok := knownIsValues[v.Value]; !ok {
+				issues = append(issues, Issue{Rule: RuleUnknownOperator, Severity: SeverityWarning, Message: fmt.Sprintf("Operator 'is:%s' is unknown.", v.Value)})
+			}
+		}
+		if v.Name == "has" {
+			if _, ok := knownHasValues[v.Value]; !ok {
+				issues = append(issues, Issue{Rule: RuleUnknownOperator, Severity: SeverityWarning, Message: fmt.Sprintf("Operator 'has:%s' is unknown.", v.Value)})
+			}
+		}
+		if _, ok := academicOperators[v.Name]; ok && !academic {
+			issues = append(issues, academicIssue(v.Name, t))
+		}
+	case PointRadius:
+		if !academic {
+			issues = append(issues, academicIssue("point_radius", t))
+		}
+	case BoundingBox:
+		if !academic {
+			issues = append(issues, academicIssue("bounding_box", t))
+		}
+	case Group:
+		seen := map[string]struct{}{}
+		for _, c := range v.Children {
+			if c == nil {
+				continue
+			}
+			s := c.String()
+			if _, ok := seen[s]; ok {
+				issues = append(issues, Issue{
+					Rule:     RuleDuplicateClause,
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("Clause '%s' is duplicated in the same %s group.", s, v.Op),
+				})
+			}
+			seen[s] = struct{}{}
+		}
+	}
+
+	return issues
+}
+
+func academicIssue(name string, t Track) Issue {
+	return Issue{
+		Rule:     RuleAcademicOperator,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("Operator '%s' is not available in the %s track. It needs the academic track.", name, t),
+	}
+}
+
+// walk calls fn for the node and its descendants in depth-first order.
+func walk(n Node, fn func(Node)) {
+	if n == nil {
+		return
+	}
+	fn(n)
+
+	switch v := n.(type) {
+	case Group:
+		for _, c := range v.Children {
+			walk(c, fn)
+		}
+	case Not:
+		walk(v.Node, fn)
+	}
+}
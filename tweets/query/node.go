@@ -0,0 +1,205 @@
+// Package query builds and validates queries of the v2 search and filtered stream rules.
+package query
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Node is a clause of a query.
+type Node interface {
+	// String renders the clause as a query string.
+	String() string
+}
+
+// Keyword matches a keyword in the text of tweets. It is quoted if it contains characters that have a meaning in queries.
+type Keyword struct {
+	Value string
+}
+
+// Phrase matches an exact phrase, and is always quoted.
+type Phrase struct {
+	Value string
+}
+
+// Entity matches a mention, a hashtag or a cashtag.
+type Entity struct {
+	Symbol EntitySymbol
+	Value  string
+}
+
+type EntitySymbol string
+
+const (
+	EntitySymbolMention EntitySymbol = "@"
+	EntitySymbolHashtag EntitySymbol = "#"
+	EntitySymbolCashtag EntitySymbol = "$"
+)
+
+func (s EntitySymbol) String() string {
+	return string(s)
+}
+
+func (s EntitySymbol) Valid() bool {
+	return s == EntitySymbolMention || s == EntitySymbolHashtag || s == EntitySymbolCashtag
+}
+
+// Operator is a "name:value" clause like "from:TwitterDev" or "is:retweet".
+type Operator struct {
+	Name  string
+	Value string
+}
+
+// PointRadius is a "point_radius:[longitude latitude radius]" clause.
+type PointRadius struct {
+	Longitude float64
+	Latitude  float64
+	Radius    float64
+	Unit      DistanceUnit
+}
+
+// BoundingBox is a "bounding_box:[west_long south_lat east_long north_lat]" clause.
+type BoundingBox struct {
+	WestLongitude float64
+	SouthLatitude float64
+	EastLongitude float64
+	NorthLatitude float64
+}
+
+type DistanceUnit string
+
+const (
+	DistanceUnitMiles      DistanceUnit = "mi"
+	DistanceUnitKilometers DistanceUnit = "km"
+)
+
+func (u DistanceUnit) String() string {
+	return string(u)
+}
+
+func (u DistanceUnit) Valid() bool {
+	return u == DistanceUnitMiles || u == DistanceUnitKilometers
+}
+
+// Group is a conjunction (AND) or a disjunction (OR) of clauses.
+type Group struct {
+	Op       BoolOp
+	Children []Node
+}
+
+type BoolOp string
+
+const (
+	BoolOpAnd BoolOp = "AND"
+	BoolOpOr  BoolOp = "OR"
+)
+
+func (o BoolOp) String() string {
+	return string(o)
+}
+
+func (o BoolOp) Valid() bool {
+	return o == BoolOpAnd || o == BoolOpOr
+}
+
+// Not negates a clause.
+type Not struct {
+	Node Node
+}
+
+func (k Keyword) String() string {
+	if needsQuote(k.Value) {
+		return quote(k.Value)
+	}
+	return k.Value
+}
+
+func (p Phrase) String() string {
+	return quote(p.Value)
+}
+
+func (e Entity) String() string {
+	return e.Symbol.String() + e.Value
+}
+
+func (o Operator) String() string {
+	if needsQuote(o.Value) {
+		return o.Name + ":" + quote(o.Value)
+	}
+	return o.Name + ":" + o.Value
+}
+
+func (p PointRadius) String() string {
+	return "point_radius:[" +
+		strconv.FormatFloat(p.Longitude, 'f', -1, 64) + " " +
+		strconv.FormatFloat(p.Latitude, 'f', -1, 64) + " " +
+		strconv.FormatFloat(p.Radius, 'f', -1, 64) + p.Unit.String() + "]"
+}
+
+func (b BoundingBox) String() string {
+	return "bounding_box:[" +
+		strconv.FormatFloat(b.WestLongitude, 'f', -1, 64) + " " +
+		strconv.FormatFloat(b.SouthLatitude, 'f', -1, 64) + " " +
+		strconv.FormatFloat(b.EastLongitude, 'f', -1, 64) + " " +
+		strconv.FormatFloat(b.NorthLatitude, 'f', -1, 64) + "]"
+}
+
+// String renders the group without the surrounding parentheses.
+// A nested group with a different operator is parenthesized, so that the precedence does not depend on the implicit AND.
+func (g Group) String() string {
+	parts := []string{}
+	for _, c := range g.Children {
+		if c == nil {
+			continue
+		}
+		parts = append(parts, g.renderChild(c))
+	}
+
+	sep := " "
+	if g.Op == BoolOpOr {
+		sep = " OR "
+	}
+	return strings.Join(parts, sep)
+}
+
+func (g Group) renderChild(c Node) string {
+	child, ok := c.(Group)
+	if !ok || child.Op == g.Op || child.len() <= 1 {
+		return c.String()
+	}
+	return "(" + child.String() + ")"
+}
+
+func (g Group) len() int {
+	n := 0
+	for _, c := range g.Children {
+		if c != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func (n Not) String() string {
+	if n.Node == nil {
+		return "-"
+	}
+	if g, ok := n.Node.(Group); ok && g.len() > 1 {
+		return "-(" + g.String() + ")"
+	}
+	return "-" + n.Node.String()
+}
+
+func needsQuote(s string) bool {
+	if s == "" || s == "OR" || s == "AND" {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-@#$") {
+		return true
+	}
+	return strings.ContainsAny(s, " \t\n\"():[]")
+}
+
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,367 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseError is returned by Parse when the query has a syntax error.
+type ParseError struct {
+	// Pos is the code point offset in the query where the error is found.
+	Pos int
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s (at %d)", e.Msg, e.Pos)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenLParen
+	tokenRParen
+	tokenNegation
+	tokenOr
+	tokenClause
+)
+
+type token struct {
+	kind tokenKind
+	pos  int
+	node Node // for tokenClause
+}
+
+// Parse parses a query string of the search or a filtered stream rule.
+// Clauses joined by spaces are AND-ed, and AND takes precedence over OR, the same as the API.
+func Parse(q string) (Node, error) {
+	n, _, err := parse(q)
+	return n, err
+}
+
+// parseInfo is what the parser notices beyond the AST, for the linter.
+type parseInfo struct {
+	// implicitAndInOr are the positions of the AND-ed clauses that are an operand of OR without parentheses.
+	implicitAndInOr []int
+}
+
+func parse(q string) (Node, *parseInfo, error) {
+	tokens, err := tokenize(q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := &parser{tokens: tokens, info: &parseInfo{}}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		if t.kind == tokenRParen {
+			return nil, nil, &ParseError{Pos: t.pos, Msg: "Unbalanced parentheses: unexpected ')'."}
+		}
+		return nil, nil, &ParseError{Pos: t.pos, Msg: "Unexpected token."}
+	}
+
+	return n, p.info, nil
+}
+
+type parser struct {
+	tokens []token
+	i      int
+	info   *parseInfo
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (Node, error) {
+	start := p.peek().pos
+	first, implicit, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	alts := []Node{first}
+	implicitAnds := []int{}
+	if implicit {
+		implicitAnds = append(implicitAnds, start)
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		start := p.peek().pos
+		n, implicit, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if implicit {
+			implicitAnds = append(implicitAnds, start)
+		}
+		alts = append(alts, n)
+	}
+
+	if len(alts) == 1 {
+		return first, nil
+	}
+	p.info.implicitAndInOr = append(p.info.implicitAndInOr, implicitAnds...)
+	return Or(alts...), nil
+}
+
+// parseAnd parses AND-ed clauses. implicit is true if there are multiple clauses.
+func (p *parser) parseAnd() (n Node, implicit bool, err error) {
+	items := []Node{}
+	for {
+		t := p.peek()
+		if t.kind == tokenEOF || t.kind == tokenRParen || t.kind == tokenOr {
+			break
+		}
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, n)
+	}
+
+	switch len(items) {
+	case 0:
+		t := p.peek()
+		switch t.kind {
+		case tokenOr:
+			return nil, false, &ParseError{Pos: t.pos, Msg: "OR has no clause on its left."}
+		case tokenRParen:
+			return nil, false, &ParseError{Pos: t.pos, Msg: "Parentheses have no clause."}
+		default:
+			return nil, false, &ParseError{Pos: t.pos, Msg: "Clause is expected."}
+		}
+	case 1:
+		return items[0], false, nil
+	default:
+		return And(items...), true, nil
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.peek().kind != tokenNegation {
+		return p.parsePrimary()
+	}
+
+	t := p.next()
+	if k := p.peek().kind; k == tokenEOF || k == tokenRParen || k == tokenOr || k == tokenNegation {
+		return nil, &ParseError{Pos: t.pos, Msg: "Negation has no clause."}
+	}
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return Negate(n), nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenClause:
+		return t.node, nil
+	case tokenLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRParen {
+			return nil, &ParseError{Pos: t.pos, Msg: "Unbalanced parentheses: missing ')'."}
+		}
+		p.next()
+		return n, nil
+	default:
+		return nil, &ParseError{Pos: t.pos, Msg: "Clause is expected."}
+	}
+}
+
+func tokenize(q string) ([]token, error) {
+	rs := []rune(q)
+	tokens := []token{}
+
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, pos: i})
+			i++
+		case r == '-':
+			if i+1 >= len(rs) || unicode.IsSpace(rs[i+1]) || rs[i+1] == ')' {
+				return nil, &ParseError{Pos: i, Msg: "Negation has no clause."}
+			}
+			tokens = append(tokens, token{kind: tokenNegation, pos: i})
+			i++
+		case r == '"':
+			s, end, err := readQuoted(rs, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenClause, pos: i, node: ExactPhrase(s)})
+			i = end
+		default:
+			t, end, err := readWord(rs, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, t)
+			i = end
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(rs)}), nil
+}
+
+// readQuoted reads the quoted string starting at rs[start], and returns the unescaped string and the offset after it.
+func readQuoted(rs []rune, start int) (string, int, error) {
+	b := strings.Builder{}
+	for i := start + 1; i < len(rs); i++ {
+		switch {
+		case rs[i] == '\\' && i+1 < len(rs) && rs[i+1] == '"':
+			b.WriteRune('"')
+			i++
+		case rs[i] == '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteRune(rs[i])
+		}
+	}
+	return "", 0, &ParseError{Pos: start, Msg: "Quote is not closed."}
+}
+
+func readWord(rs []rune, start int) (token, int, error) {
+	i := start
+	for i < len(rs) && !unicode.IsSpace(rs[i]) && rs[i] != '(' && rs[i] != ')' {
+		if rs[i] == ':' && isOperatorName(string(rs[start:i])) {
+			return readOperator(rs, start, i)
+		}
+		i++
+	}
+
+	word := string(rs[start:i])
+	t := token{kind: tokenClause, pos: start}
+	switch {
+	case word == "OR":
+		t.kind = tokenOr
+	case len(word) > 1 && EntitySymbol(word[:1]).Valid():
+		t.node = Entity{Symbol: EntitySymbol(word[:1]), Value: word[1:]}
+	default:
+		t.node = Word(word)
+	}
+	return t, i, nil
+}
+
+// readOperator reads the value of the operator whose ':' is at rs[colon].
+func readOperator(rs []rune, start, colon int) (token, int, error) {
+	name := string(rs[start:colon])
+	t := token{kind: tokenClause, pos: start}
+	i := colon + 1
+
+	if i < len(rs) && rs[i] == '"' {
+		s, end, err := readQuoted(rs, i)
+		if err != nil {
+			return t, 0, err
+		}
+		t.node = Op(name, s)
+		return t, end, nil
+	}
+
+	if i < len(rs) && rs[i] == '[' {
+		end := i
+		for end < len(rs) && rs[end] != ']' {
+			end++
+		}
+		if end == len(rs) {
+			return t, 0, &ParseError{Pos: i, Msg: "Bracket is not closed."}
+		}
+		n, err := parseBracket(name, string(rs[i+1:end]))
+		if err != nil {
+			return t, 0, &ParseError{Pos: start, Msg: err.Error()}
+		}
+		t.node = n
+		return t, end + 1, nil
+	}
+
+	end := i
+	for end < len(rs) && !unicode.IsSpace(rs[end]) && rs[end] != '(' && rs[end] != ')' {
+		end++
+	}
+	t.node = Op(name, string(rs[i:end]))
+	return t, end, nil
+}
+
+func parseBracket(name, s string) (Node, error) {
+	f := strings.Fields(s)
+	switch name {
+	case "point_radius":
+		if len(f) != 3 {
+			return nil, fmt.Errorf("point_radius needs longitude, latitude and radius.")
+		}
+		unit := DistanceUnit("")
+		for _, u := range []DistanceUnit{DistanceUnitMiles, DistanceUnitKilometers} {
+			if strings.HasSuffix(f[2], u.String()) {
+				unit = u
+			}
+		}
+		if unit == "" {
+			return nil, fmt.Errorf("Radius of point_radius needs the unit 'mi' or 'km'.")
+		}
+		v, err := parseFloats(f[0], f[1], strings.TrimSuffix(f[2], unit.String()))
+		if err != nil {
+			return nil, err
+		}
+		return Radius(v[0], v[1], v[2], unit), nil
+	case "bounding_box":
+		if len(f) != 4 {
+			return nil, fmt.Errorf("bounding_box needs 4 coordinates.")
+		}
+		v, err := parseFloats(f...)
+		if err != nil {
+			return nil, err
+		}
+		return BoundingBox{WestLongitude: v[0], SouthLatitude: v[1], EastLongitude: v[2], NorthLatitude: v[3]}, nil
+	default:
+		return Op(name, "["+s+"]"), nil
+	}
+}
+
+func parseFloats(ss ...string) ([]float64, error) {
+	fs := []float64{}
+	for _, s := range ss {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number.", s)
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+func isOperatorName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r == '_' || ('a' <= r && r <= 'z')) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,204 @@
+package query_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/michimani/gotwi/tweets/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse(t *testing.T) {
+	cases := []struct {
+		name      string
+		query     string
+		expect    query.Node
+		expectPos int
+		wantErr   bool
+	}{
+		{
+			name:   "ok: implicit and",
+			query:  "from:TwitterDev #go -is:retweet",
+			expect: query.And(query.From("TwitterDev"), query.Hashtag("go"), query.Negate(query.IsRetweet())),
+		},
+		{
+			name:   "ok: and takes precedence over or",
+			query:  "a b OR c",
+			expect: query.Or(query.And(query.Word("a"), query.Word("b")), query.Word("c")),
+		},
+		{
+			name:  "ok: groups and quotes",
+			query: `(cat OR "hot dog") -(bird OR @fish) url:"https://go.dev"`,
+			expect: query.And(
+				query.Or(query.Word("cat"), query.ExactPhrase("hot dog")),
+				query.Negate(query.Or(query.Word("bird"), query.Mention("fish"))),
+				query.URL("https://go.dev"),
+			),
+		},
+		{
+			name:   "ok: point radius",
+			query:  "point_radius:[-105.27 40.01 16km]",
+			expect: query.Radius(-105.27, 40.01, 16, query.DistanceUnitKilometers),
+		},
+		{
+			name:   "ok: bounding box",
+			query:  "bounding_box:[-105.3 39.9 -105.1 40.1]",
+			expect: query.BoundingBox{WestLongitude: -105.3, SouthLatitude: 39.9, EastLongitude: -105.1, NorthLatitude: 40.1},
+		},
+		{
+			name:      "ng: missing closing parenthesis",
+			query:     "a (b OR c",
+			expectPos: 2,
+			wantErr:   true,
+		},
+		{
+			name:      "ng: unexpected closing parenthesis",
+			query:     "a b) c",
+			expectPos: 3,
+			wantErr:   true,
+		},
+		{
+			name:      "ng: standalone negation",
+			query:     "a - b",
+			expectPos: 2,
+			wantErr:   true,
+		},
+		{
+			name:      "ng: or without left clause",
+			query:     "OR a",
+			expectPos: 0,
+			wantErr:   true,
+		},
+		{
+			name:      "ng: or without right clause",
+			query:     "a OR",
+			expectPos: 4,
+			wantErr:   true,
+		},
+		{
+			name:      "ng: unclosed quote",
+			query:     `a "b c`,
+			expectPos: 2,
+			wantErr:   true,
+		},
+		{
+			name:      "ng: point radius without unit",
+			query:     "point_radius:[1 2 3]",
+			expectPos: 0,
+			wantErr:   true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			n, err := query.Parse(c.query)
+			if c.wantErr {
+				pe := &query.ParseError{}
+				if assert.True(tt, errors.As(err, &pe)) {
+					assert.Equal(tt, c.expectPos, pe.Pos)
+				}
+				assert.Nil(tt, n)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, n)
+		})
+	}
+}
+
+func Test_Parse_roundTrip(t *testing.T) {
+	q := `from:TwitterDev (cat OR "hot dog") -(bird OR @fish) ((a b) OR c) point_radius:[1.5 2 3mi]`
+	n, err := query.Parse(q)
+	assert.NoError(t, err)
+	assert.Equal(t, q, n.String())
+}
+
+func Test_Lint(t *testing.T) {
+	cases := []struct {
+		name        string
+		query       string
+		track       query.Track
+		expectRules []string
+		expectError bool
+	}{
+		{
+			name:        "ok",
+			query:       "from:TwitterDev (#go OR #golang) -is:retweet",
+			track:       query.TrackEssential,
+			expectRules: []string{},
+		},
+		{
+			name:        "syntax error",
+			query:       "(a OR b",
+			track:       query.TrackEssential,
+			expectRules: []string{query.RuleSyntax},
+			expectError: true,
+		},
+		{
+			name:        "only negations",
+			query:       "-a -is:retweet",
+			track:       query.TrackEssential,
+			expectRules: []string{query.RuleStandaloneNegation},
+			expectError: true,
+		},
+		{
+			name:        "academic operators",
+			query:       "a place_country:JP bio:gopher point_radius:[0 0 1mi]",
+			track:       query.TrackElevated,
+			expectRules: []string{query.RuleAcademicOperator, query.RuleAcademicOperator, query.RuleAcademicOperator},
+			expectError: true,
+		},
+		{
+			name:        "academic operators in the academic track",
+			query:       "a place_country:JP bio:gopher",
+			track:       query.TrackAcademic,
+			expectRules: []string{},
+		},
+		{
+			name:        "or precedence",
+			query:       "a b OR c",
+			track:       query.TrackEssential,
+			expectRules: []string{query.RuleOrPrecedence},
+		},
+		{
+			name:        "duplicated clause",
+			query:       "a #go b #go",
+			track:       query.TrackEssential,
+			expectRules: []string{query.RuleDuplicateClause},
+		},
+		{
+			name:        "unknown operators",
+			query:       "https://go.dev is:pinned",
+			track:       query.TrackEssential,
+			expectRules: []string{query.RuleUnknownOperator, query.RuleUnknownOperator},
+		},
+		{
+			name:        "invalid clause",
+			query:       "a from:",
+			track:       query.TrackEssential,
+			expectRules: []string{query.RuleInvalidClause},
+			expectError: true,
+		},
+		{
+			name:        "too long",
+			query:       strings.Repeat("a", 513),
+			track:       query.TrackEssential,
+			expectRules: []string{query.RuleTooLong},
+			expectError: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			issues := query.Lint(c.query, c.track)
+			rules := []string{}
+			for _, i := range issues {
+				rules = append(rules, i.Rule)
+			}
+			assert.Equal(tt, c.expectRules, rules)
+			assert.Equal(tt, c.expectError, query.HasError(issues))
+		})
+	}
+}
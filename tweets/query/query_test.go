@@ -0,0 +1,135 @@
+package query_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michimani/gotwi/tweets/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Build(t *testing.T) {
+	cases := []struct {
+		name    string
+		node    query.Node
+		track   query.Track
+		expect  string
+		wantErr bool
+	}{
+		{
+			name: "ok: operators",
+			node: query.And(
+				query.From("@TwitterDev"),
+				query.To("gopher"),
+				query.Mention("golang"),
+				query.Hashtag("#go"),
+				query.Cashtag("GOOG"),
+				query.Negate(query.IsRetweet()),
+				query.HasMedia(),
+				query.Lang("en"),
+			),
+			track:  query.TrackEssential,
+			expect: "from:TwitterDev to:gopher @golang #go $GOOG -is:retweet has:media lang:en",
+		},
+		{
+			name: "ok: quoting",
+			node: query.And(
+				query.ExactPhrase(`say "hi"`),
+				query.Word("OR"),
+				query.Word("-x"),
+				query.URL("https://go.dev"),
+				query.ConversationID("1234"),
+				query.PlaceCountry("JP"),
+			),
+			track:  query.TrackAcademic,
+			expect: `"say \"hi\"" "OR" "-x" url:"https://go.dev" conversation_id:1234 place_country:JP`,
+		},
+		{
+			name: "ok: grouping",
+			node: query.And(
+				query.Or(query.Word("cat"), query.Word("dog")),
+				query.Negate(query.Or(query.Word("bird"), query.Word("fish"))),
+				query.Or(query.And(query.Word("a"), query.Word("b")), query.Word("c")),
+			),
+			track:  query.TrackEssential,
+			expect: "(cat OR dog) -(bird OR fish) ((a b) OR c)",
+		},
+		{
+			name: "ok: nested and in or",
+			node: query.Or(
+				query.And(query.Word("a"), query.Word("b")),
+				query.Or(query.Word("c"), query.Word("d")),
+			),
+			track:  query.TrackEssential,
+			expect: "(a b) OR c OR d",
+		},
+		{
+			name:   "ok: point radius",
+			node:   query.Radius(-105.27346517, 40.01924738, 10, query.DistanceUnitKilometers),
+			track:  query.TrackEssential,
+			expect: "point_radius:[-105.27346517 40.01924738 10km]",
+		},
+		{
+			name:   "ok: length within the academic track",
+			node:   query.Word(strings.Repeat("a", 1024)),
+			track:  query.TrackAcademic,
+			expect: strings.Repeat("a", 1024),
+		},
+		{
+			name:    "ng: too long for the essential track",
+			node:    query.Word(strings.Repeat("a", 513)),
+			track:   query.TrackEssential,
+			wantErr: true,
+		},
+		{
+			name:    "ng: only negations",
+			node:    query.And(query.Negate(query.IsRetweet()), query.Negate(query.Word("a"))),
+			track:   query.TrackEssential,
+			wantErr: true,
+		},
+		{
+			name:    "ng: negation in or",
+			node:    query.Or(query.Word("a"), query.Negate(query.Word("b"))),
+			track:   query.TrackEssential,
+			wantErr: true,
+		},
+		{
+			name:    "ng: empty value",
+			node:    query.From(""),
+			track:   query.TrackEssential,
+			wantErr: true,
+		},
+		{
+			name:    "ng: radius too large",
+			node:    query.Radius(0, 0, 26, query.DistanceUnitMiles),
+			track:   query.TrackEssential,
+			wantErr: true,
+		},
+		{
+			name:    "ng: empty group",
+			node:    query.And(),
+			track:   query.TrackEssential,
+			wantErr: true,
+		},
+		{
+			name:    "ng: invalid track",
+			node:    query.Word("a"),
+			track:   "free",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			s, err := query.Build(c.node, c.track)
+			if c.wantErr {
+				assert.Error(tt, err)
+				assert.Empty(tt, s)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, s)
+		})
+	}
+}
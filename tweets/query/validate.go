@@ -0,0 +1,181 @@
+package query
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Track is the product track of the Twitter API, which limits the length of queries and stream rules.
+type Track string
+
+const (
+	TrackEssential  Track = "essential"
+	TrackElevated   Track = "elevated"
+	TrackAcademic   Track = "academic"
+	TrackEnterprise Track = "enterprise"
+)
+
+func (t Track) String() string {
+	return string(t)
+}
+
+func (t Track) Valid() bool {
+	return t == TrackEssential || t == TrackElevated || t == TrackAcademic || t == TrackEnterprise
+}
+
+// MaxLength returns the maximum number of characters of a query in the track.
+func (t Track) MaxLength() int {
+	switch t {
+	case TrackAcademic:
+		return 1024
+	case TrackEnterprise:
+		return 4096
+	default:
+		return 512
+	}
+}
+
+const maxPointRadiusMiles = 25
+
+// Build renders the query and validates it for the track.
+func Build(n Node, t Track) (string, error) {
+	if err := Validate(n, t); err != nil {
+		return "", err
+	}
+	return n.String(), nil
+}
+
+// Validate validates the clauses of the query and the length of the rendered query for the track.
+func Validate(n Node, t Track) error {
+	if !t.Valid() {
+		return fmt.Errorf("Track '%s' is invalid.", t)
+	}
+	if isEmpty(n) {
+		return fmt.Errorf("Query is empty.")
+	}
+	if err := validateNode(n); err != nil {
+		return err
+	}
+	if !hasPositive(n) {
+		return fmt.Errorf("Query must have at least one clause that is not negated.")
+	}
+
+	if l := utf8.RuneCountInString(n.String()); l > t.MaxLength() {
+		return fmt.Errorf("Query is too long. length=%d maxLength=%d track=%s", l, t.MaxLength(), t)
+	}
+
+	return nil
+}
+
+func validateNode(n Node) error {
+	switch v := n.(type) {
+	case Keyword:
+		if v.Value == "" {
+			return fmt.Errorf("Keyword is empty.")
+		}
+	case Phrase:
+		if v.Value == "" {
+			return fmt.Errorf("Phrase is empty.")
+		}
+	case Entity:
+		if !v.Symbol.Valid() {
+			return fmt.Errorf("EntitySymbol '%s' is invalid.", v.Symbol)
+		}
+		if v.Value == "" {
+			return fmt.Errorf("Value of '%s' is empty.", v.Symbol)
+		}
+	case Operator:
+		if v.Name == "" {
+			return fmt.Errorf("Operator name is empty.")
+		}
+		if v.Value == "" {
+			return fmt.Errorf("Value of operator '%s' is empty.", v.Name)
+		}
+	case PointRadius:
+		return validatePointRadius(v)
+	case BoundingBox:
+		if v.WestLongitude < -180 || v.EastLongitude > 180 || v.WestLongitude > v.EastLongitude ||
+			v.SouthLatitude < -90 || v.NorthLatitude > 90 || v.SouthLatitude > v.NorthLatitude {
+			return fmt.Errorf("Coordinates of bounding_box are invalid. box=%s", v)
+		}
+	case Group:
+		if !v.Op.Valid() {
+			return fmt.Errorf("BoolOp '%s' is invalid.", v.Op)
+		}
+		if v.len() == 0 {
+			return fmt.Errorf("Group is empty.")
+		}
+		for _, c := range v.Children {
+			if c == nil {
+				continue
+			}
+			if err := validateNode(c); err != nil {
+				return err
+			}
+		}
+	case Not:
+		if isEmpty(v.Node) {
+			return fmt.Errorf("Negation has no clause.")
+		}
+		return validateNode(v.Node)
+	case nil:
+		return fmt.Errorf("Query is empty.")
+	}
+
+	return nil
+}
+
+func validatePointRadius(p PointRadius) error {
+	if p.Longitude < -180 || p.Longitude > 180 {
+		return fmt.Errorf("Longitude of point_radius is invalid. longitude=%v", p.Longitude)
+	}
+	if p.Latitude < -90 || p.Latitude > 90 {
+		return fmt.Errorf("Latitude of point_radius is invalid. latitude=%v", p.Latitude)
+	}
+	if !p.Unit.Valid() {
+		return fmt.Errorf("DistanceUnit '%s' is invalid.", p.Unit)
+	}
+
+	miles := p.Radius
+	if p.Unit == DistanceUnitKilometers {
+		miles = p.Radius / 1.609344
+	}
+	if p.Radius <= 0 || miles > maxPointRadiusMiles {
+		return fmt.Errorf("Radius of point_radius must be greater than 0 and up to %d miles. radius=%v%s", maxPointRadiusMiles, p.Radius, p.Unit)
+	}
+
+	return nil
+}
+
+func isEmpty(n Node) bool {
+	if n == nil {
+		return true
+	}
+	g, ok := n.(Group)
+	return ok && g.len() == 0
+}
+
+// hasPositive reports whether the node matches something without negations,
+// because the API rejects queries that consist of negated clauses only.
+func hasPositive(n Node) bool {
+	switch v := n.(type) {
+	case Not:
+		return false
+	case Group:
+		for _, c := range v.Children {
+			if c == nil {
+				continue
+			}
+			p := hasPositive(c)
+			if v.Op == BoolOpAnd && p {
+				return true
+			}
+			if v.Op == BoolOpOr && !p {
+				return false
+			}
+		}
+		return v.Op == BoolOpOr
+	default:
+		return true
+	}
+}
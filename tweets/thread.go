@@ -0,0 +1,272 @@
+package tweets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/resources"
+	"github.com/michimani/gotwi/tweets/types"
+	"github.com/michimani/gotwi/twittertext"
+)
+
+const ThreadMaxWeightedLength = 280
+
+type PostThreadInput struct {
+	// Text is split into parts by SplitThreadText. It is ignored if Parts is not empty.
+	Text string
+	// Parts are posted as they are, without splitting and numbering.
+	Parts []string
+	// Numbering appends " 1/n" style numbering to each part of Text.
+	Numbering bool
+	// InReplyToTweetID is the tweet that the first part replies to. If empty, the first part is a new tweet.
+	InReplyToTweetID string
+	// ReplySettings of each part. If empty, the reply settings are not set.
+	ReplySettings resources.ReplySettings
+	// Rollback deletes the already posted parts through ManageTweetsDelete when posting a part fails.
+	Rollback bool
+}
+
+type PostThreadOutput struct {
+	TweetIDs []string
+}
+
+// ThreadError is returned by PostThread when posting a part of the thread fails.
+type ThreadError struct {
+	// PostedTweetIDs are the IDs of the parts posted before the failure.
+	// If RolledBack is true, they have been deleted.
+	PostedTweetIDs []string
+	RolledBack     bool
+	// RollbackErr is the first error occurred while deleting the posted parts.
+	RollbackErr error
+	Err         error
+}
+
+func (e *ThreadError) Error() string {
+	msg := fmt.Sprintf("failed to post part %d of the thread: %s", len(e.PostedTweetIDs)+1, e.Err)
+	if e.RollbackErr != nil {
+		msg = msg + fmt.Sprintf(" (rollback failed: %s)", e.RollbackErr)
+	}
+	return msg
+}
+
+func (e *ThreadError) Unwrap() error {
+	return e.Err
+}
+
+// PostThread posts each part of the thread as a reply to the previous one.
+// On failure, it returns the output with the IDs posted so far and a *ThreadError.
+func PostThread(ctx context.Context, c *gotwi.GotwiClient, in *PostThreadInput) (*PostThreadOutput, error) {
+	if in == nil {
+		return nil, fmt.Errorf("PostThreadInput is nil.")
+	}
+
+	parts := in.Parts
+	if len(parts) == 0 {
+		parts = SplitThreadText(in.Text, in.Numbering)
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("Text or Parts is required.")
+	}
+
+	out := &PostThreadOutput{TweetIDs: []string{}}
+	replyTo := in.InReplyToTweetID
+	for _, part := range parts {
+		p := &types.ManageTweetsPostParams{
+			Text: gotwi.String(part),
+		}
+		if in.ReplySettings != "" {
+			rs := in.ReplySettings
+			p.ReplySettings = &rs
+		}
+		if replyTo != "" {
+			p.Reply = &types.ManageTweetsPostParamsReply{InReplyToTweetID: replyTo}
+		}
+
+		res, err := ManageTweetsPost(ctx, c, p)
+		if err != nil {
+			te := &ThreadError{
+				PostedTweetIDs: out.TweetIDs,
+				Err:            err,
+			}
+			if in.Rollback {
+				te.RollbackErr = rollbackThread(ctx, c, out.TweetIDs)
+				te.RolledBack = te.RollbackErr == nil
+			}
+			return out, te
+		}
+
+		replyTo = gotwi.StringValue(res.Data.ID)
+		out.TweetIDs = append(out.TweetIDs, replyTo)
+	}
+
+	return out, nil
+}
+
+// rollbackThread deletes the posted tweets from the last one.
+func rollbackThread(ctx context.Context, c *gotwi.GotwiClient, ids []string) error {
+	var firstErr error
+	for i := len(ids) - 1; i >= 0; i-- {
+		if _, err := ManageTweetsDelete(ctx, c, &types.ManageTweetsDeleteParams{ID: ids[i]}); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// SplitThreadText splits the text into parts whose weighted length is within ThreadMaxWeightedLength.
+// The text is split at sentence boundaries if possible, otherwise at word boundaries.
+// If numbering is true, " 1/n" style numbering is appended to each part.
+func SplitThreadText(text string, numbering bool) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return []string{}
+	}
+
+	if !numbering {
+		return splitThreadText(text, ThreadMaxWeightedLength)
+	}
+
+	// The width of the numbering depends on the number of parts,
+	// so split again with more room until the number of digits is stable.
+	digits := 1
+	for {
+		reserved := len(fmt.Sprintf(" %s/%s", strings.Repeat("9", digits), strings.Repeat("9", digits)))
+		parts := splitThreadText(text, ThreadMaxWeightedLength-reserved)
+		if len(fmt.Sprintf("%d", len(parts))) <= digits {
+			for i := range parts {
+				parts[i] = fmt.Sprintf("%s %d/%d", parts[i], i+1, len(parts))
+			}
+			return parts
+		}
+		digits++
+	}
+}
+
+func splitThreadText(text string, limit int) []string {
+	parts := []string{}
+	current := ""
+	flush := func() {
+		if s := strings.TrimSpace(current); s != "" {
+			parts = append(parts, s)
+		}
+		current = ""
+	}
+
+	for _, sentence := range splitSentences(text) {
+		if weightedLength(current+sentence) <= limit {
+			current += sentence
+			continue
+		}
+
+		flush()
+		if weightedLength(sentence) <= limit {
+			current = sentence
+			continue
+		}
+
+		// The sentence itself is too long, split it at word boundaries.
+		for _, word := range splitWords(sentence) {
+			if weightedLength(current+word) <= limit {
+				current += word
+				continue
+			}
+
+			flush()
+			for weightedLength(word) > limit {
+				head, tail := cutAtWeightedLength(word, limit)
+				parts = append(parts, head)
+				word = tail
+			}
+			current = word
+		}
+	}
+	flush()
+
+	return parts
+}
+
+// splitSentences splits the text after sentence terminators and line breaks.
+// Each sentence keeps its trailing spaces, so that joining them restores the text.
+func splitSentences(text string) []string {
+	sentences := []string{}
+	rs := []rune(text)
+	start := 0
+	for i := 0; i < len(rs); i++ {
+		if !isSentenceTerminator(rs[i]) {
+			continue
+		}
+		j := i + 1
+		if rs[i] != '\n' && (j >= len(rs) || !unicode.IsSpace(rs[j])) && !isFullwidthTerminator(rs[i]) {
+			continue
+		}
+		for j < len(rs) && unicode.IsSpace(rs[j]) {
+			j++
+		}
+		sentences = append(sentences, string(rs[start:j]))
+		start = j
+		i = j - 1
+	}
+	if start < len(rs) {
+		sentences = append(sentences, string(rs[start:]))
+	}
+	return sentences
+}
+
+func isSentenceTerminator(r rune) bool {
+	return r == '.' || r == '!' || r == '?' || r == '\n' || isFullwidthTerminator(r)
+}
+
+func isFullwidthTerminator(r rune) bool {
+	return r == '。' || r == '！' || r == '？'
+}
+
+// splitWords splits the text after each run of spaces, keeping the spaces with the preceding word.
+func splitWords(text string) []string {
+	words := []string{}
+	rs := []rune(text)
+	start := 0
+	for i := 0; i < len(rs); i++ {
+		if !unicode.IsSpace(rs[i]) {
+			continue
+		}
+		j := i
+		for j < len(rs) && unicode.IsSpace(rs[j]) {
+			j++
+		}
+		words = append(words, string(rs[start:j]))
+		start = j
+		i = j - 1
+	}
+	if start < len(rs) {
+		words = append(words, string(rs[start:]))
+	}
+	return words
+}
+
+// cutAtWeightedLength cuts s at the end of the part that fits in the limit.
+// If not even the first character fits, it is cut after the first character, so that the split goes on.
+func cutAtWeightedLength(s string, limit int) (string, string) {
+	r := twittertext.ParseTweetWithConfiguration(s, threadConfiguration(limit))
+	rs := []rune(s)
+	end := r.ValidRangeEnd + 1
+	if end < 1 {
+		end = 1
+	}
+	if r.Valid || end >= len(rs) {
+		return s, ""
+	}
+	return string(rs[:end]), string(rs[end:])
+}
+
+func weightedLength(s string) int {
+	return twittertext.ParseTweet(s).WeightedLength
+}
+
+func threadConfiguration(limit int) *twittertext.Configuration {
+	c := twittertext.DefaultConfiguration
+	c.MaxWeightedTweetLength = limit
+	return &c
+}
@@ -0,0 +1,196 @@
+package tweets_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/michimani/gotwi/internal/testutil"
+	"github.com/michimani/gotwi/tweets"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SplitThreadText(t *testing.T) {
+	long := strings.Repeat("word ", 100)
+	cases := []struct {
+		name      string
+		text      string
+		numbering bool
+		expect    []string
+	}{
+		{
+			name:   "short text",
+			text:   "Hello, world.",
+			expect: []string{"Hello, world."},
+		},
+		{
+			name:   "empty text",
+			text:   "  ",
+			expect: []string{},
+		},
+		{
+			name:      "short text with numbering",
+			text:      "Hello, world.",
+			numbering: true,
+			expect:    []string{"Hello, world. 1/1"},
+		},
+		{
+			name: "split at sentence boundary",
+			text: strings.Repeat("a", 200) + ". " + strings.Repeat("b", 200) + ".",
+			expect: []string{
+				strings.Repeat("a", 200) + ".",
+				strings.Repeat("b", 200) + ".",
+			},
+		},
+		{
+			name:      "split at sentence boundary with numbering",
+			text:      strings.Repeat("a", 200) + ". " + strings.Repeat("b", 200) + ".",
+			numbering: true,
+			expect: []string{
+				strings.Repeat("a", 200) + ". 1/2",
+				strings.Repeat("b", 200) + ". 2/2",
+			},
+		},
+		{
+			name: "split at word boundary",
+			text: long,
+			expect: []string{
+				strings.TrimSpace(strings.Repeat("word ", 56)),
+				strings.TrimSpace(strings.Repeat("word ", 44)),
+			},
+		},
+		{
+			name: "URL counts as 23",
+			text: strings.Repeat("a", 250) + " https://example.com/" + strings.Repeat("x", 100),
+			expect: []string{
+				strings.Repeat("a", 250) + " https://example.com/" + strings.Repeat("x", 100),
+			},
+		},
+		{
+			name: "CJK characters count as 2",
+			text: strings.Repeat("あ", 141),
+			expect: []string{
+				strings.Repeat("あ", 140),
+				"あ",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			parts := tweets.SplitThreadText(c.text, c.numbering)
+			assert.Equal(tt, c.expect, parts)
+		})
+	}
+}
+
+func Test_PostThread(t *testing.T) {
+	cases := []struct {
+		name             string
+		in               *tweets.PostThreadInput
+		failAt           int
+		expectIDs        []string
+		expectDeleted    []string
+		expectReplyTo    []string
+		wantErr          bool
+		expectRolledBack bool
+	}{
+		{
+			name:          "ok",
+			in:            &tweets.PostThreadInput{Parts: []string{"one", "two", "three"}},
+			expectIDs:     []string{"1", "2", "3"},
+			expectReplyTo: []string{"", "1", "2"},
+		},
+		{
+			name:          "ok: reply to existing tweet",
+			in:            &tweets.PostThreadInput{Parts: []string{"one", "two"}, InReplyToTweetID: "100"},
+			expectIDs:     []string{"1", "2"},
+			expectReplyTo: []string{"100", "1"},
+		},
+		{
+			name:          "ng: failed without rollback",
+			in:            &tweets.PostThreadInput{Parts: []string{"one", "two", "three"}},
+			failAt:        3,
+			expectIDs:     []string{"1", "2"},
+			expectReplyTo: []string{"", "1", "2"},
+			wantErr:       true,
+		},
+		{
+			name:             "ng: failed with rollback",
+			in:               &tweets.PostThreadInput{Parts: []string{"one", "two", "three"}, Rollback: true},
+			failAt:           3,
+			expectIDs:        []string{"1", "2"},
+			expectDeleted:    []string{"2", "1"},
+			expectReplyTo:    []string{"", "1", "2"},
+			wantErr:          true,
+			expectRolledBack: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			s := &threadServer{failAt: c.failAt}
+			client := testutil.NewClient(tt, s)
+
+			out, err := tweets.PostThread(context.Background(), client, c.in)
+			assert.Equal(tt, c.expectIDs, out.TweetIDs)
+			assert.Equal(tt, c.expectReplyTo, s.replyTo)
+			assert.Equal(tt, c.expectDeleted, s.deleted)
+			if !c.wantErr {
+				assert.NoError(tt, err)
+				return
+			}
+
+			te := &tweets.ThreadError{}
+			if assert.True(tt, errors.As(err, &te)) {
+				assert.Equal(tt, c.expectIDs, te.PostedTweetIDs)
+				assert.Equal(tt, c.expectRolledBack, te.RolledBack)
+			}
+		})
+	}
+}
+
+type threadServer struct {
+	mu      sync.Mutex
+	failAt  int
+	posted  int
+	replyTo []string
+	deleted []string
+}
+
+func (s *threadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch r.Method {
+	case http.MethodPost:
+		body := struct {
+			Reply *struct {
+				InReplyToTweetID string `json:"in_reply_to_tweet_id"`
+			} `json:"reply"`
+		}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		replyTo := ""
+		if body.Reply != nil {
+			replyTo = body.Reply.InReplyToTweetID
+		}
+		s.replyTo = append(s.replyTo, replyTo)
+
+		s.posted++
+		if s.posted == s.failAt {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"title":"Forbidden","detail":"You are not allowed to create a Tweet with duplicate content.","type":"about:blank","status":403}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"id":"%d","text":"text"}}`, s.posted)
+	case http.MethodDelete:
+		s.deleted = append(s.deleted, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+		fmt.Fprint(w, `{"data":{"deleted":true}}`)
+	}
+}
@@ -1,8 +1,13 @@
 package types
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
+	"github.com/michimani/gotwi/fields"
 	"github.com/michimani/gotwi/internal/util"
 )
 
@@ -50,3 +55,131 @@ func (p *FilteredStreamRulesGetParams) ParameterMap() map[string]string {
 
 	return m
 }
+
+type FilteredStreamRulesPostParams struct {
+	accessToken string
+
+	// Query parameters
+	DryRun bool `json:"-"` // default false
+
+	// JSON body parameter
+	Add    []FilteredStreamRulesPostParamsAdd   `json:"add,omitempty"`
+	Delete *FilteredStreamRulesPostParamsDelete `json:"delete,omitempty"`
+}
+
+type FilteredStreamRulesPostParamsAdd struct {
+	Value *string `json:"value,omitempty"`
+	Tag   *string `json:"tag,omitempty"`
+}
+
+type FilteredStreamRulesPostParamsDelete struct {
+	IDs []string `json:"ids,omitempty"`
+}
+
+var FilteredStreamRulesPostQueryParams = map[string]struct{}{
+	"dry_run": {},
+}
+
+func (p *FilteredStreamRulesPostParams) SetAccessToken(token string) {
+	p.accessToken = token
+}
+
+func (p *FilteredStreamRulesPostParams) AccessToken() string {
+	return p.accessToken
+}
+
+func (p *FilteredStreamRulesPostParams) ResolveEndpoint(endpointBase string) string {
+	endpoint := endpointBase
+	pm := p.ParameterMap()
+	qs := util.QueryString(pm, FilteredStreamRulesPostQueryParams)
+
+	if qs == "" {
+		return endpoint
+	}
+
+	return endpoint + "?" + qs
+}
+
+func (p *FilteredStreamRulesPostParams) Body() (io.Reader, error) {
+	if len(p.Add) == 0 && (p.Delete == nil || len(p.Delete.IDs) == 0) {
+		return nil, fmt.Errorf("Add or Delete is required.")
+	}
+	if len(p.Add) > 0 && p.Delete != nil {
+		return nil, fmt.Errorf("Add and Delete cannot be set at the same time.")
+	}
+
+	json, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.NewReader(string(json)), nil
+}
+
+func (p *FilteredStreamRulesPostParams) ParameterMap() map[string]string {
+	m := map[string]string{}
+
+	if p.DryRun {
+		m["dry_run"] = "true"
+	}
+
+	return m
+}
+
+type FilteredStreamSearchParams struct {
+	accessToken string
+
+	// Query parameters
+	BackfillMinutes int
+	Expansions      fields.ExpansionList
+	MediaFields     fields.MediaFieldList
+	PlaceFields     fields.PlaceFieldList
+	PollFields      fields.PollFieldList
+	TweetFields     fields.TweetFieldList
+	UserFields      fields.UserFieldList
+}
+
+var FilteredStreamSearchQueryParams = map[string]struct{}{
+	"backfill_minutes": {},
+	"expansions":       {},
+	"media.fields":     {},
+	"place.fields":     {},
+	"poll.fields":      {},
+	"tweet.fields":     {},
+	"user.fields":      {},
+}
+
+func (p *FilteredStreamSearchParams) SetAccessToken(token string) {
+	p.accessToken = token
+}
+
+func (p *FilteredStreamSearchParams) AccessToken() string {
+	return p.accessToken
+}
+
+func (p *FilteredStreamSearchParams) ResolveEndpoint(endpointBase string) string {
+	endpoint := endpointBase
+	pm := p.ParameterMap()
+	qs := util.QueryString(pm, FilteredStreamSearchQueryParams)
+
+	if qs == "" {
+		return endpoint
+	}
+
+	return endpoint + "?" + qs
+}
+
+func (p *FilteredStreamSearchParams) Body() (io.Reader, error) {
+	return nil, nil
+}
+
+func (p *FilteredStreamSearchParams) ParameterMap() map[string]string {
+	m := map[string]string{}
+	m = fields.SetFieldsParams(m, p.Expansions, p.MediaFields, p.PlaceFields, p.PollFields, p.TweetFields, p.UserFields)
+
+	if p.BackfillMinutes > 0 {
+		m["backfill_minutes"] = strconv.Itoa(p.BackfillMinutes)
+	}
+
+	return m
+}
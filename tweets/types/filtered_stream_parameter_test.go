@@ -1,8 +1,12 @@
 package types_test
 
 import (
+	"io"
+	"strings"
 	"testing"
 
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/fields"
 	"github.com/michimani/gotwi/tweets/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -87,3 +91,118 @@ func Test_FilteredStreamRulesGet_Body(t *testing.T) {
 		})
 	}
 }
+
+func Test_FilteredStreamRulesPost_ResolveEndpoint(t *testing.T) {
+	const endpointBase = "test/endpoint"
+
+	cases := []struct {
+		name   string
+		params *types.FilteredStreamRulesPostParams
+		expect string
+	}{
+		{
+			name:   "has no parameter",
+			params: &types.FilteredStreamRulesPostParams{},
+			expect: endpointBase,
+		},
+		{
+			name:   "with dry_run",
+			params: &types.FilteredStreamRulesPostParams{DryRun: true},
+			expect: endpointBase + "?dry_run=true",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			ep := c.params.ResolveEndpoint(endpointBase)
+			assert.Equal(tt, c.expect, ep)
+		})
+	}
+}
+
+func Test_FilteredStreamRulesPost_Body(t *testing.T) {
+	cases := []struct {
+		name    string
+		params  *types.FilteredStreamRulesPostParams
+		expect  io.Reader
+		wantErr bool
+	}{
+		{
+			name: "ok: add",
+			params: &types.FilteredStreamRulesPostParams{
+				DryRun: true,
+				Add: []types.FilteredStreamRulesPostParamsAdd{
+					{Value: gotwi.String("cat has:images"), Tag: gotwi.String("cats")},
+					{Value: gotwi.String("dog")},
+				},
+			},
+			expect: strings.NewReader(`{"add":[{"value":"cat has:images","tag":"cats"},{"value":"dog"}]}`),
+		},
+		{
+			name: "ok: delete",
+			params: &types.FilteredStreamRulesPostParams{
+				Delete: &types.FilteredStreamRulesPostParamsDelete{IDs: []string{"rid1", "rid2"}},
+			},
+			expect: strings.NewReader(`{"delete":{"ids":["rid1","rid2"]}}`),
+		},
+		{
+			name:    "ng: empty",
+			params:  &types.FilteredStreamRulesPostParams{},
+			wantErr: true,
+		},
+		{
+			name: "ng: add and delete",
+			params: &types.FilteredStreamRulesPostParams{
+				Add:    []types.FilteredStreamRulesPostParamsAdd{{Value: gotwi.String("dog")}},
+				Delete: &types.FilteredStreamRulesPostParamsDelete{IDs: []string{"rid1"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			r, err := c.params.Body()
+			if c.wantErr {
+				assert.Error(tt, err)
+				assert.Nil(tt, r)
+				return
+			}
+
+			assert.NoError(tt, err)
+			assert.Equal(tt, c.expect, r)
+		})
+	}
+}
+
+func Test_FilteredStreamSearch_ResolveEndpoint(t *testing.T) {
+	const endpointBase = "test/endpoint"
+
+	cases := []struct {
+		name   string
+		params *types.FilteredStreamSearchParams
+		expect string
+	}{
+		{
+			name:   "has no parameter",
+			params: &types.FilteredStreamSearchParams{},
+			expect: endpointBase,
+		},
+		{
+			name: "with fields and backfill",
+			params: &types.FilteredStreamSearchParams{
+				BackfillMinutes: 5,
+				Expansions:      fields.ExpansionList{fields.ExpansionAuthorID},
+				TweetFields:     fields.TweetFieldList{fields.TweetFieldCreatedAt},
+			},
+			expect: endpointBase + "?backfill_minutes=5&expansions=author_id&tweet.fields=created_at",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			ep := c.params.ResolveEndpoint(endpointBase)
+			assert.Equal(tt, c.expect, ep)
+		})
+	}
+}
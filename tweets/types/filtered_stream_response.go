@@ -3,11 +3,32 @@ package types
 import "github.com/michimani/gotwi/resources"
 
 type FilteredStreamRulesGetResponse struct {
-	Data   resources.FilterdStreamRule `json:"data"`
-	Meta   resources.FilterdStreamRulesGetMeta
-	Errors []resources.PartialError `json:"errors"`
+	Data   []resources.FilterdStreamRule       `json:"data"`
+	Meta   resources.FilterdStreamRulesGetMeta `json:"meta"`
+	Errors []resources.PartialError            `json:"errors"`
 }
 
 func (r *FilteredStreamRulesGetResponse) HasPartialError() bool {
 	return !(r.Errors == nil || len(r.Errors) == 0)
 }
+
+type FilteredStreamRulesPostResponse struct {
+	Data   []resources.FilterdStreamRule        `json:"data"`
+	Meta   resources.FilterdStreamRulesPostMeta `json:"meta"`
+	Errors []resources.PartialError             `json:"errors"`
+}
+
+func (r *FilteredStreamRulesPostResponse) HasPartialError() bool {
+	return !(r.Errors == nil || len(r.Errors) == 0)
+}
+
+type FilteredStreamSearchResponse struct {
+	Data          resources.Tweet                       `json:"data"`
+	Includes      resources.Includes                    `json:"includes"`
+	MatchingRules []resources.FilterdStreamMatchingRule `json:"matching_rules"`
+	Errors        []resources.PartialError              `json:"errors"`
+}
+
+func (r *FilteredStreamSearchResponse) HasPartialError() bool {
+	return !(r.Errors == nil || len(r.Errors) == 0)
+}
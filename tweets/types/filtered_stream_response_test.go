@@ -1,11 +1,14 @@
 package types_test
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/michimani/gotwi/resources"
 	"github.com/michimani/gotwi/tweets/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_FilteredStreamRulesGet_HasPartialError(t *testing.T) {
@@ -44,3 +47,37 @@ func Test_FilteredStreamRulesGet_HasPartialError(t *testing.T) {
 		})
 	}
 }
+
+func Test_FilteredStreamRulesGetResponse_Decode(t *testing.T) {
+	// the response of GET /2/tweets/search/stream/rules
+	body := `{
+  "data": [
+    {
+      "id": "1273026480692322304",
+      "value": "dog has:images",
+      "tag": "dog pictures"
+    },
+    {
+      "id": "1273028376882589696",
+      "value": "cat has:images -grumpy"
+    }
+  ],
+  "meta": {
+    "sent": "2020-06-16T22:55:39.356Z",
+    "result_count": 2
+  }
+}`
+
+	res := &types.FilteredStreamRulesGetResponse{}
+	require.NoError(t, json.Unmarshal([]byte(body), res))
+
+	require.Len(t, res.Data, 2)
+	assert.Equal(t, "1273026480692322304", *res.Data[0].ID)
+	assert.Equal(t, "dog has:images", *res.Data[0].Value)
+	assert.Equal(t, "dog pictures", *res.Data[0].Tag)
+	assert.Equal(t, "cat has:images -grumpy", *res.Data[1].Value)
+	assert.Nil(t, res.Data[1].Tag)
+	assert.Equal(t, time.Date(2020, 6, 16, 22, 55, 39, 356000000, time.UTC), *res.Meta.Sent)
+	assert.Equal(t, 2, *res.Meta.ResultCount)
+	assert.False(t, res.HasPartialError())
+}
@@ -2,9 +2,13 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/url"
 	"strings"
+
+	"github.com/michimani/gotwi/resources"
+	"github.com/michimani/gotwi/twittertext"
 )
 
 type ManageTweetsPostParams struct {
@@ -17,7 +21,7 @@ type ManageTweetsPostParams struct {
 	Media                 *ManageTweetsPostParamsMedia `json:"media,omitempty"`
 	Poll                  *ManageTweetsPostParamsPoll  `json:"poll,omitempty"`
 	Reply                 *ManageTweetsPostParamsReply `json:"reply,omitempty"`
-	ReplySettings         *string                      `json:"reply_settings,omitempty"`
+	ReplySettings         *resources.ReplySettings     `json:"reply_settings,omitempty"`
 	Text                  *string                      `json:"text,omitempty"`
 }
 
@@ -53,6 +57,17 @@ func (p *ManageTweetsPostParams) ResolveEndpoint(endpointBase string) string {
 }
 
 func (p *ManageTweetsPostParams) Body() (io.Reader, error) {
+	if p.ReplySettings != nil && !p.ReplySettings.Valid() {
+		return nil, fmt.Errorf("ReplySettings '%s' is invalid.", *p.ReplySettings)
+	}
+
+	if p.Text != nil && *p.Text != "" {
+		r := twittertext.ParseTweet(*p.Text)
+		if !r.Valid {
+			return nil, fmt.Errorf("Text is invalid. weightedLength=%d maxWeightedLength=%d validRangeEnd=%d", r.WeightedLength, twittertext.DefaultConfiguration.MaxWeightedTweetLength, r.ValidRangeEnd)
+		}
+	}
+
 	json, err := json.Marshal(p)
 	if err != nil {
 		return nil, err
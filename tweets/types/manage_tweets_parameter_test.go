@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/resources"
 	"github.com/michimani/gotwi/tweets/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -39,9 +40,10 @@ func Test_ManageTweetsPostParams_ResolveEndpoint(t *testing.T) {
 
 func Test_ManageTweetsPostParams_Body(t *testing.T) {
 	cases := []struct {
-		name   string
-		params *types.ManageTweetsPostParams
-		expect io.Reader
+		name    string
+		params  *types.ManageTweetsPostParams
+		expect  io.Reader
+		wantErr bool
 	}{
 		{
 			name: "ok: has some json parameters",
@@ -59,17 +61,59 @@ func Test_ManageTweetsPostParams_Body(t *testing.T) {
 			params: &types.ManageTweetsPostParams{},
 			expect: strings.NewReader(`{}`),
 		},
+		{
+			name: "ok: has reply settings",
+			params: &types.ManageTweetsPostParams{
+				Text:          gotwi.String("test text"),
+				ReplySettings: replySettings(resources.ReplySettingsMentionedUsers),
+			},
+			expect: strings.NewReader(`{"reply_settings":"mentionedUsers","text":"test text"}`),
+		},
+		{
+			name: "ok: 280 weighted characters",
+			params: &types.ManageTweetsPostParams{
+				Text: gotwi.String(strings.Repeat("あ", 140)),
+			},
+			expect: strings.NewReader(`{"text":"` + strings.Repeat("あ", 140) + `"}`),
+		},
+		{
+			name: "ng: too long text",
+			params: &types.ManageTweetsPostParams{
+				Text: gotwi.String(strings.Repeat("あ", 141)),
+			},
+			expect:  nil,
+			wantErr: true,
+		},
+		{
+			name: "ng: invalid reply settings",
+			params: &types.ManageTweetsPostParams{
+				Text:          gotwi.String("test text"),
+				ReplySettings: replySettings("nobody"),
+			},
+			expect:  nil,
+			wantErr: true,
+		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(tt *testing.T) {
 			r, err := c.params.Body()
+			if c.wantErr {
+				assert.Error(tt, err)
+				assert.Nil(tt, r)
+				return
+			}
+
 			assert.NoError(tt, err)
 			assert.Equal(tt, c.expect, r)
 		})
 	}
 }
 
+func replySettings(r resources.ReplySettings) *resources.ReplySettings {
+	return &r
+}
+
 func Test_ManageTweetsDeleteParams_ResolveEndpoint(t *testing.T) {
 	const endpointRoot = "test/endpoint/"
 	const endpointBase = "test/endpoint/:id"
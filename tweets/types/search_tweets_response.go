@@ -5,10 +5,8 @@ import "github.com/michimani/gotwi/resources"
 type SearchTweetsRecentResponse struct {
 	Data     []resources.Tweet        `json:"data"`
 	Meta     resources.PaginationMeta `json:"meta"`
-	Includes struct {
-		Users []resources.User `json:"users"`
-	} `json:"includes"`
-	Errors []resources.PartialError `json:"errors"`
+	Includes resources.Includes       `json:"includes"`
+	Errors   []resources.PartialError `json:"errors"`
 }
 
 func (r *SearchTweetsRecentResponse) HasPartialError() bool {
@@ -18,10 +16,8 @@ func (r *SearchTweetsRecentResponse) HasPartialError() bool {
 type SearchTweetsAllResponse struct {
 	Data     []resources.Tweet        `json:"data"`
 	Meta     resources.PaginationMeta `json:"meta"`
-	Includes struct {
-		Users []resources.User `json:"users"`
-	} `json:"includes"`
-	Errors []resources.PartialError `json:"errors"`
+	Includes resources.Includes       `json:"includes"`
+	Errors   []resources.PartialError `json:"errors"`
 }
 
 func (r *SearchTweetsAllResponse) HasPartialError() bool {
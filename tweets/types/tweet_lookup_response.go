@@ -3,11 +3,9 @@ package types
 import "github.com/michimani/gotwi/resources"
 
 type TweetLookupResponse struct {
-	Data     []resources.Tweet `json:"data"`
-	Includes struct {
-		Users []resources.User `json:"users"`
-	} `json:"includes"`
-	Errors []resources.PartialError `json:"errors"`
+	Data     []resources.Tweet        `json:"data"`
+	Includes resources.Includes       `json:"includes"`
+	Errors   []resources.PartialError `json:"errors"`
 }
 
 func (r *TweetLookupResponse) HasPartialError() bool {
@@ -15,11 +13,9 @@ func (r *TweetLookupResponse) HasPartialError() bool {
 }
 
 type TweetLookupIDResponse struct {
-	Data     resources.Tweet `json:"data"`
-	Includes struct {
-		Users []resources.User `json:"users"`
-	} `json:"includes"`
-	Errors []resources.PartialError `json:"errors"`
+	Data     resources.Tweet          `json:"data"`
+	Includes resources.Includes       `json:"includes"`
+	Errors   []resources.PartialError `json:"errors"`
 }
 
 func (r *TweetLookupIDResponse) HasPartialError() bool {
@@ -1,6 +1,7 @@
 package types_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/michimani/gotwi/resources"
@@ -81,3 +82,56 @@ func Test_TweetLookupID_HasPartialError(t *testing.T) {
 		})
 	}
 }
+
+func Test_TweetLookup_DecodeIncludes(t *testing.T) {
+	cases := []struct {
+		name       string
+		body       string
+		expectUser int
+		expectMed  int
+		expectPlc  int
+		expectPoll int
+		expectTwt  int
+	}{
+		{
+			name: "ok: all include types",
+			body: `{
+				"data": [{"id": "1", "text": "test", "author_id": "10", "geo": {"place_id": "p1"}}],
+				"includes": {
+					"users": [{"id": "10", "name": "test user", "username": "test_user"}],
+					"tweets": [{"id": "2", "text": "referenced"}],
+					"media": [{"media_key": "3_1", "type": "photo"}],
+					"places": [{"id": "p1", "full_name": "Tokyo, Japan"}],
+					"polls": [{"id": "poll1", "options": [{"position": 1, "label": "yes", "votes": 0}]}]
+				}
+			}`,
+			expectUser: 1,
+			expectMed:  1,
+			expectPlc:  1,
+			expectPoll: 1,
+			expectTwt:  1,
+		},
+		{
+			name:       "ok: no includes",
+			body:       `{"data": [{"id": "1", "text": "test"}]}`,
+			expectUser: 0,
+			expectMed:  0,
+			expectPlc:  0,
+			expectPoll: 0,
+			expectTwt:  0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			res := &types.TweetLookupResponse{}
+			err := json.Unmarshal([]byte(c.body), res)
+			assert.NoError(tt, err)
+			assert.Len(tt, res.Includes.Users, c.expectUser)
+			assert.Len(tt, res.Includes.Media, c.expectMed)
+			assert.Len(tt, res.Includes.Places, c.expectPlc)
+			assert.Len(tt, res.Includes.Polls, c.expectPoll)
+			assert.Len(tt, res.Includes.Tweets, c.expectTwt)
+		})
+	}
+}
@@ -3,16 +3,10 @@ package types
 import "github.com/michimani/gotwi/resources"
 
 type TweetTimelinesTweetsResponse struct {
-	Data     []resources.Tweet `json:"data"`
-	Includes struct {
-		Users  []resources.User  `json:"users,omitempty"`
-		Tweets []resources.Tweet `json:"tweets,omitempty"`
-		Places []resources.Place `json:"places,omitempty"`
-		Media  []resources.Media `json:"media,omitempty"`
-		Polls  []resources.Poll  `json:"polls,omitempty"`
-	} `json:"includes,omitempty"`
-	Meta   resources.TweetTimelineMeta `json:"meta"`
-	Errors []resources.PartialError    `json:"errors,omitempty"`
+	Data     []resources.Tweet           `json:"data"`
+	Includes resources.Includes          `json:"includes,omitempty"`
+	Meta     resources.TweetTimelineMeta `json:"meta"`
+	Errors   []resources.PartialError    `json:"errors,omitempty"`
 }
 
 func (r *TweetTimelinesTweetsResponse) HasPartialError() bool {
@@ -20,16 +14,10 @@ func (r *TweetTimelinesTweetsResponse) HasPartialError() bool {
 }
 
 type TweetTimelinesMentionsResponse struct {
-	Data     []resources.Tweet `json:"data"`
-	Includes struct {
-		Users  []resources.User  `json:"users,omitempty"`
-		Tweets []resources.Tweet `json:"tweets,omitempty"`
-		Places []resources.Place `json:"places,omitempty"`
-		Media  []resources.Media `json:"media,omitempty"`
-		Polls  []resources.Poll  `json:"polls,omitempty"`
-	} `json:"includes,omitempty"`
-	Meta   resources.TweetTimelineMeta `json:"meta"`
-	Errors []resources.PartialError    `json:"errors,omitempty"`
+	Data     []resources.Tweet           `json:"data"`
+	Includes resources.Includes          `json:"includes,omitempty"`
+	Meta     resources.TweetTimelineMeta `json:"meta"`
+	Errors   []resources.PartialError    `json:"errors,omitempty"`
 }
 
 func (r *TweetTimelinesMentionsResponse) HasPartialError() bool {
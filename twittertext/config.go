@@ -0,0 +1,50 @@
+package twittertext
+
+// Configuration is the configuration of the weighted length counting.
+// https://github.com/twitter/twitter-text/tree/master/config
+type Configuration struct {
+	Version                int
+	MaxWeightedTweetLength int
+	Scale                  int
+	DefaultWeight          int
+	TransformedURLLength   int
+	Ranges                 []WeightedRange
+	EmojiParsingEnabled    bool
+}
+
+// WeightedRange is a range of code points with the weight.
+type WeightedRange struct {
+	Start  rune
+	End    rune
+	Weight int
+}
+
+// ConfigurationV3 is the configuration used by the Twitter since version 3 of twitter-text.
+// Characters in the ranges count as 1, other characters (CJK characters, emoji and so on) count as 2,
+// and URLs count as 23 regardless of their length.
+var ConfigurationV3 = Configuration{
+	Version:                3,
+	MaxWeightedTweetLength: 280,
+	Scale:                  100,
+	DefaultWeight:          200,
+	TransformedURLLength:   23,
+	Ranges: []WeightedRange{
+		{Start: 0, End: 4351, Weight: 100},
+		{Start: 8192, End: 8205, Weight: 100},
+		{Start: 8208, End: 8223, Weight: 100},
+		{Start: 8242, End: 8247, Weight: 100},
+	},
+	EmojiParsingEnabled: true,
+}
+
+// DefaultConfiguration is the configuration used by ParseTweet.
+var DefaultConfiguration = ConfigurationV3
+
+func (c *Configuration) weight(r rune) int {
+	for _, wr := range c.Ranges {
+		if wr.Start <= r && r <= wr.End {
+			return wr.Weight
+		}
+	}
+	return c.DefaultWeight
+}
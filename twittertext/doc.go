@@ -0,0 +1,3 @@
+// Package twittertext counts the weighted length of a tweet text and extracts entities from it,
+// following the rules of twitter-text (https://github.com/twitter/twitter-text) version 3.
+package twittertext
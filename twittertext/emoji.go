@@ -0,0 +1,118 @@
+package twittertext
+
+const (
+	zeroWidthJoiner   = 0x200D
+	variationSelector = 0xFE0F
+	combiningKeycap   = 0x20E3
+)
+
+// emojiLength returns the number of code points of the emoji sequence at the beginning of rs,
+// or 0 if rs does not start with an emoji.
+// An emoji sequence is a regional indicator pair (flag), a keycap sequence,
+// or an emoji with optional variation selector, skin tone modifier and tags, joined by zero width joiners.
+func emojiLength(rs []rune) int {
+	if len(rs) == 0 {
+		return 0
+	}
+
+	if isRegionalIndicator(rs[0]) {
+		if len(rs) > 1 && isRegionalIndicator(rs[1]) {
+			return 2
+		}
+		return 1
+	}
+
+	if isKeycapBase(rs[0]) {
+		i := 1
+		if i < len(rs) && rs[i] == variationSelector {
+			i++
+		}
+		if i < len(rs) && rs[i] == combiningKeycap {
+			return i + 1
+		}
+		return 0
+	}
+
+	i := emojiElementLength(rs)
+	if i == 0 {
+		return 0
+	}
+	for i+1 < len(rs) && rs[i] == zeroWidthJoiner {
+		n := emojiElementLength(rs[i+1:])
+		if n == 0 {
+			break
+		}
+		i += 1 + n
+	}
+	return i
+}
+
+// emojiElementLength returns the length of an emoji with its modifiers at the beginning of rs.
+func emojiElementLength(rs []rune) int {
+	if len(rs) == 0 {
+		return 0
+	}
+
+	i := 1
+	switch {
+	case isEmojiPresentation(rs[0]):
+	case isTextPresentation(rs[0]) && len(rs) > 1 && rs[1] == variationSelector:
+	default:
+		return 0
+	}
+
+	if i < len(rs) && rs[i] == variationSelector {
+		i++
+	}
+	if i < len(rs) && isSkinToneModifier(rs[i]) {
+		i++
+	}
+	for i < len(rs) && isTag(rs[i]) {
+		i++
+	}
+	return i
+}
+
+func isRegionalIndicator(r rune) bool {
+	return 0x1F1E6 <= r && r <= 0x1F1FF
+}
+
+func isKeycapBase(r rune) bool {
+	return r == '#' || r == '*' || ('0' <= r && r <= '9')
+}
+
+func isSkinToneModifier(r rune) bool {
+	return 0x1F3FB <= r && r <= 0x1F3FF
+}
+
+func isTag(r rune) bool {
+	return 0xE0020 <= r && r <= 0xE007F
+}
+
+// isEmojiPresentation reports whether r is displayed as an emoji by default.
+func isEmojiPresentation(r rune) bool {
+	switch {
+	case 0x1F000 <= r && r <= 0x1FAFF,
+		0x2600 <= r && r <= 0x27BF,
+		0x2B00 <= r && r <= 0x2BFF,
+		0x2300 <= r && r <= 0x23FF,
+		r == 0x3030, r == 0x303D, r == 0x3297, r == 0x3299:
+		return true
+	default:
+		return false
+	}
+}
+
+// isTextPresentation reports whether r is an emoji only when followed by the variation selector.
+func isTextPresentation(r rune) bool {
+	switch {
+	case r == 0x00A9, r == 0x00AE,
+		r == 0x203C, r == 0x2049, r == 0x2122, r == 0x2139,
+		0x2194 <= r && r <= 0x21AA,
+		0x25AA <= r && r <= 0x25FE,
+		0x2934 <= r && r <= 0x2935:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,247 @@
+package twittertext
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/michimani/gotwi/resources"
+)
+
+const maxUsernameLength = 20
+
+// ExtractEntities extracts hashtags, cashtags, mentions and URLs from the text.
+// The Start and End of each entity are code point offsets of the text, the same as the entities returned by the API.
+// Hashtags, cashtags and mentions in a URL are not extracted.
+func ExtractEntities(text string) *resources.TweetEntities {
+	return &resources.TweetEntities{
+		HashTags: ExtractHashtags(text),
+		CashTags: ExtractCashtags(text),
+		Mentions: ExtractMentions(text),
+		URLs:     ExtractURLs(text),
+	}
+}
+
+// ExtractURLs extracts URLs from the text.
+// Only URL, Start and End are set, because the t.co and expanded URLs are resolved by the Twitter.
+func ExtractURLs(text string) []resources.URL {
+	urls := []resources.URL{}
+	for _, m := range extractURLs(text) {
+		urls = append(urls, resources.URL{
+			Start: intPtr(m.start),
+			End:   intPtr(m.end),
+			URL:   stringPtr(m.url),
+		})
+	}
+	return urls
+}
+
+// ExtractHashtags extracts hashtags like "#golang" from the text. The Tag does not include the "#".
+func ExtractHashtags(text string) []resources.TweetEntityTag {
+	tags := []resources.TweetEntityTag{}
+	rs := []rune(text)
+	urls := extractURLs(text)
+
+	for i := 0; i < len(rs); i++ {
+		if rs[i] != '#' && rs[i] != '＃' {
+			continue
+		}
+		if i > 0 && (isHashtagChar(rs[i-1]) || rs[i-1] == '&') {
+			continue
+		}
+
+		j := i + 1
+		hasLetter := false
+		for j < len(rs) && isHashtagChar(rs[j]) {
+			if !unicode.IsDigit(rs[j]) {
+				hasLetter = true
+			}
+			j++
+		}
+		if j == i+1 || !hasLetter {
+			continue
+		}
+		if j < len(rs) && (rs[j] == '#' || rs[j] == '＃' || hasPrefixAt(rs, j, "://")) {
+			i = j
+			continue
+		}
+		if overlapsURL(urls, i, j) {
+			i = j - 1
+			continue
+		}
+
+		tags = append(tags, newTag(i, j, string(rs[i+1:j])))
+		i = j - 1
+	}
+
+	return tags
+}
+
+// ExtractCashtags extracts cashtags like "$TWTR" from the text. The Tag does not include the "$".
+func ExtractCashtags(text string) []resources.TweetEntityTag {
+	tags := []resources.TweetEntityTag{}
+	rs := []rune(text)
+
+	for i := 0; i < len(rs); i++ {
+		if rs[i] != '$' {
+			continue
+		}
+		if i > 0 && !unicode.IsSpace(rs[i-1]) {
+			continue
+		}
+
+		j := i + 1
+		for j < len(rs) && j-i-1 < 6 && isASCIILetter(rs[j]) {
+			j++
+		}
+		if j == i+1 {
+			continue
+		}
+		// optional suffix like "$BRK.A" or "$VOD_L"
+		if j+1 < len(rs) && (rs[j] == '.' || rs[j] == '_') && isASCIILetter(rs[j+1]) {
+			k := j + 1
+			for k < len(rs) && k-j-1 < 2 && isASCIILetter(rs[k]) {
+				k++
+			}
+			j = k
+		}
+		if j < len(rs) && (isAlnum(rs[j]) || rs[j] == '$') {
+			continue
+		}
+
+		tags = append(tags, newTag(i, j, string(rs[i+1:j])))
+		i = j - 1
+	}
+
+	return tags
+}
+
+// ExtractMentions extracts mentions like "@TwitterDev" from the text. The Username does not include the "@".
+func ExtractMentions(text string) []resources.TweetEntityTag {
+	mentions := []resources.TweetEntityTag{}
+	rs := []rune(text)
+	urls := extractURLs(text)
+
+	for i := 0; i < len(rs); i++ {
+		if rs[i] != '@' && rs[i] != '＠' {
+			continue
+		}
+		if i > 0 && !validMentionPrecedingChar(rs, i) {
+			continue
+		}
+
+		j := i + 1
+		for j < len(rs) && isUsernameChar(rs[j]) {
+			j++
+		}
+		if j == i+1 {
+			continue
+		}
+		if j-i-1 > maxUsernameLength {
+			i = j - 1
+			continue
+		}
+		if j < len(rs) && (rs[j] == '@' || rs[j] == '＠' || isLatinAccent(rs[j]) || hasPrefixAt(rs, j, "://")) {
+			i = j - 1
+			continue
+		}
+		if overlapsURL(urls, i, j) {
+			i = j - 1
+			continue
+		}
+
+		mentions = append(mentions, resources.TweetEntityTag{
+			Start:    intPtr(i),
+			End:      intPtr(j),
+			Username: stringPtr(string(rs[i+1 : j])),
+		})
+		i = j - 1
+	}
+
+	return mentions
+}
+
+// ExtractProfileEntities extracts hashtags, cashtags, mentions and URLs from the description of a user profile.
+func ExtractProfileEntities(description string) *resources.UserDescription {
+	d := &resources.UserDescription{
+		URLs:     []resources.UserEntityURL{},
+		HashTags: []resources.UserEntityTag{},
+		Mentions: []resources.UserEntityMention{},
+		CashTags: []resources.UserEntityTag{},
+	}
+
+	for _, u := range ExtractURLs(description) {
+		d.URLs = append(d.URLs, resources.UserEntityURL{Start: u.Start, End: u.End, URL: u.URL})
+	}
+	for _, h := range ExtractHashtags(description) {
+		d.HashTags = append(d.HashTags, resources.UserEntityTag{Start: h.Start, End: h.End, Tag: h.Tag})
+	}
+	for _, m := range ExtractMentions(description) {
+		d.Mentions = append(d.Mentions, resources.UserEntityMention{Start: m.Start, End: m.End, Username: m.Username})
+	}
+	for _, c := range ExtractCashtags(description) {
+		d.CashTags = append(d.CashTags, resources.UserEntityTag{Start: c.Start, End: c.End, Tag: c.Tag})
+	}
+
+	return d
+}
+
+func validMentionPrecedingChar(rs []rune, i int) bool {
+	prev := rs[i-1]
+	if isUsernameChar(prev) || strings.ContainsRune("!#$%&*@＠", prev) {
+		// "RT@user" is a mention in a retweet text
+		return i >= 2 && (rs[i-2] == 'R' || rs[i-2] == 'r') && (prev == 'T' || prev == 't') && (i == 2 || !isUsernameChar(rs[i-3]))
+	}
+	return true
+}
+
+func isHashtagChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.M, r) || r == '_' || r == 0x200C || r == 0x200D || r == 0x30FB
+}
+
+func isUsernameChar(r rune) bool {
+	return r == '_' || isASCIILetter(r) || ('0' <= r && r <= '9')
+}
+
+func isASCIILetter(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+}
+
+func isLatinAccent(r rune) bool {
+	return (0x00C0 <= r && r <= 0x024F && r != 0x00D7 && r != 0x00F7) || (0x1E00 <= r && r <= 0x1EFF)
+}
+
+func hasPrefixAt(rs []rune, i int, prefix string) bool {
+	return strings.HasPrefix(string(rs[i:min(len(rs), i+len(prefix))]), prefix)
+}
+
+func overlapsURL(urls []urlMatch, start, end int) bool {
+	for _, u := range urls {
+		if start < u.end && u.start < end {
+			return true
+		}
+	}
+	return false
+}
+
+func newTag(start, end int, tag string) resources.TweetEntityTag {
+	return resources.TweetEntityTag{
+		Start: intPtr(start),
+		End:   intPtr(end),
+		Tag:   stringPtr(tag),
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
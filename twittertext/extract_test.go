@@ -0,0 +1,149 @@
+package twittertext_test
+
+import (
+	"testing"
+
+	"github.com/michimani/gotwi/twittertext"
+	"github.com/stretchr/testify/assert"
+)
+
+type entity struct {
+	start int
+	end   int
+	value string
+}
+
+func Test_ExtractHashtags(t *testing.T) {
+	cases := []struct {
+		name   string
+		text   string
+		expect []entity
+	}{
+		{name: "simple", text: "#golang is fun", expect: []entity{{0, 7, "golang"}}},
+		{name: "some hashtags", text: "go #go#no and #twitter_api", expect: []entity{{14, 26, "twitter_api"}}},
+		{name: "full width hash", text: "＃ハッシュタグ です", expect: []entity{{0, 7, "ハッシュタグ"}}},
+		{name: "after emoji", text: "😀 #emoji", expect: []entity{{2, 8, "emoji"}}},
+		{name: "numeric only", text: "#123", expect: []entity{}},
+		{name: "preceded by letter", text: "abc#def", expect: []entity{}},
+		{name: "preceded by ampersand", text: "&#39;", expect: []entity{}},
+		{name: "in URL", text: "https://example.com/#anchor", expect: []entity{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			tags := twittertext.ExtractHashtags(c.text)
+			actual := []entity{}
+			for _, tag := range tags {
+				actual = append(actual, entity{*tag.Start, *tag.End, *tag.Tag})
+			}
+			assert.Equal(tt, c.expect, actual)
+		})
+	}
+}
+
+func Test_ExtractCashtags(t *testing.T) {
+	cases := []struct {
+		name   string
+		text   string
+		expect []entity
+	}{
+		{name: "simple", text: "$TWTR is up", expect: []entity{{0, 5, "TWTR"}}},
+		{name: "with suffix", text: "buy $BRK.A now", expect: []entity{{4, 10, "BRK.A"}}},
+		{name: "price", text: "it costs $100", expect: []entity{}},
+		{name: "too long", text: "$ABCDEFG", expect: []entity{}},
+		{name: "preceded by letter", text: "a$TWTR", expect: []entity{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			tags := twittertext.ExtractCashtags(c.text)
+			actual := []entity{}
+			for _, tag := range tags {
+				actual = append(actual, entity{*tag.Start, *tag.End, *tag.Tag})
+			}
+			assert.Equal(tt, c.expect, actual)
+		})
+	}
+}
+
+func Test_ExtractMentions(t *testing.T) {
+	cases := []struct {
+		name   string
+		text   string
+		expect []entity
+	}{
+		{name: "simple", text: "@TwitterDev hello", expect: []entity{{0, 11, "TwitterDev"}}},
+		{name: "some mentions", text: "cc @a_b, ＠c1", expect: []entity{{3, 7, "a_b"}, {9, 12, "c1"}}},
+		{name: "after CJK", text: "こんにちは@user", expect: []entity{{5, 10, "user"}}},
+		{name: "retweet", text: "RT@user: hi", expect: []entity{{2, 7, "user"}}},
+		{name: "email", text: "mail to user@example.com", expect: []entity{}},
+		{name: "too long", text: "@abcdefghijklmnopqrstu", expect: []entity{}},
+		{name: "followed by at", text: "@user@host", expect: []entity{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			mentions := twittertext.ExtractMentions(c.text)
+			actual := []entity{}
+			for _, m := range mentions {
+				actual = append(actual, entity{*m.Start, *m.End, *m.Username})
+			}
+			assert.Equal(tt, c.expect, actual)
+		})
+	}
+}
+
+func Test_ExtractURLs(t *testing.T) {
+	cases := []struct {
+		name   string
+		text   string
+		expect []entity
+	}{
+		{name: "with protocol", text: "see https://t.co/abc123", expect: []entity{{4, 23, "https://t.co/abc123"}}},
+		{name: "without protocol", text: "visit example.com/path.", expect: []entity{{6, 22, "example.com/path"}}},
+		{name: "after emoji", text: "😀😀 https://example.com", expect: []entity{{3, 22, "https://example.com"}}},
+		{name: "balanced parenthesis", text: "(https://en.wikipedia.org/wiki/Go_(programming_language))", expect: []entity{{1, 56, "https://en.wikipedia.org/wiki/Go_(programming_language)"}}},
+		{name: "unknown TLD", text: "file.txt", expect: []entity{}},
+		{name: "email", text: "user@example.com", expect: []entity{}},
+		{name: "some urls", text: "a.com b.jp", expect: []entity{{0, 5, "a.com"}, {6, 10, "b.jp"}}},
+		{name: "gTLD without protocol", text: "go.agency my.cloud", expect: []entity{{0, 9, "go.agency"}, {10, 18, "my.cloud"}}},
+		{name: "unknown TLD with protocol", text: "https://example.barbaz/path", expect: []entity{}},
+		{name: "gTLD with protocol", text: "https://example.cloud/path", expect: []entity{{0, 26, "https://example.cloud/path"}}},
+		{name: "IDN TLD with protocol", text: "https://例え.中国", expect: []entity{{0, 13, "https://例え.中国"}}},
+		{name: "IDN TLD without protocol", text: "例え.中国", expect: []entity{}},
+		{name: "punycode TLD", text: "example.xn--fiqs8s", expect: []entity{{0, 18, "example.xn--fiqs8s"}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			urls := twittertext.ExtractURLs(c.text)
+			actual := []entity{}
+			for _, u := range urls {
+				actual = append(actual, entity{*u.Start, *u.End, *u.URL})
+			}
+			assert.Equal(tt, c.expect, actual)
+		})
+	}
+}
+
+func Test_ExtractEntities(t *testing.T) {
+	text := "Hi @TwitterDev, #v2API $TWTR https://developer.twitter.com 🎉"
+	e := twittertext.ExtractEntities(text)
+
+	assert.Len(t, e.Mentions, 1)
+	assert.Len(t, e.HashTags, 1)
+	assert.Len(t, e.CashTags, 1)
+	assert.Len(t, e.URLs, 1)
+	assert.Equal(t, 29, *e.URLs[0].Start)
+	assert.Equal(t, 58, *e.URLs[0].End)
+}
+
+func Test_ExtractProfileEntities(t *testing.T) {
+	d := twittertext.ExtractProfileEntities("The voice of the #TwitterDev team. Follow @TwitterAPI for $TWTR news https://t.co/3ZX3TNiZCY")
+
+	assert.Equal(t, "TwitterDev", *d.HashTags[0].Tag)
+	assert.Equal(t, "TwitterAPI", *d.Mentions[0].Username)
+	assert.Equal(t, "TWTR", *d.CashTags[0].Tag)
+	assert.Equal(t, "https://t.co/3ZX3TNiZCY", *d.URLs[0].URL)
+	assert.Equal(t, 69, *d.URLs[0].Start)
+}
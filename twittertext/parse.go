@@ -0,0 +1,95 @@
+package twittertext
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// invalidChars are the characters that can not be included in a tweet.
+var invalidChars = map[rune]struct{}{
+	0xFFFE: {},
+	0xFEFF: {},
+	0xFFFF: {},
+}
+
+type ParseResults struct {
+	// WeightedLength is the length of the text counted with the weight of each character.
+	WeightedLength int
+	// Permillage is the ratio of WeightedLength to the max weighted length, in per mille.
+	Permillage int
+	Valid      bool
+	// The ranges are inclusive code point offsets of the text.
+	// The valid range is the part of the text that fits in the max weighted length.
+	// ValidRangeEnd is -1 if not even the first character fits.
+	DisplayRangeStart int
+	DisplayRangeEnd   int
+	ValidRangeStart   int
+	ValidRangeEnd     int
+}
+
+// ParseTweet parses the text with DefaultConfiguration.
+func ParseTweet(text string) *ParseResults {
+	return ParseTweetWithConfiguration(text, &DefaultConfiguration)
+}
+
+// ParseTweetWithConfiguration returns the weighted length and the validity of the text.
+// The text is normalized in Unicode NFC before counting, as the Twitter does.
+func ParseTweetWithConfiguration(text string, c *Configuration) *ParseResults {
+	if c == nil {
+		c = &DefaultConfiguration
+	}
+
+	if text == "" {
+		return &ParseResults{}
+	}
+
+	normalized := norm.NFC.String(text)
+	rs := []rune(normalized)
+
+	urlEnds := map[int]int{}
+	for _, u := range extractURLs(normalized) {
+		urlEnds[u.start] = u.end
+	}
+
+	max := c.MaxWeightedTweetLength * c.Scale
+	weighted := 0
+	// validEnd stays -1 if not even the first character fits, as twitter-text does.
+	validEnd := -1
+	hasInvalidChar := false
+	for i := 0; i < len(rs); {
+		w, n := c.weight(rs[i]), 1
+		if end, ok := urlEnds[i]; ok {
+			w, n = c.TransformedURLLength*c.Scale, end-i
+		} else if c.EmojiParsingEnabled {
+			if l := emojiLength(rs[i:]); l > 0 {
+				w, n = c.DefaultWeight, l
+			}
+		}
+
+		if _, ok := invalidChars[rs[i]]; ok {
+			hasInvalidChar = true
+		}
+
+		weighted += w
+		if weighted <= max {
+			validEnd = i + n - 1
+		}
+		i += n
+	}
+
+	textLength := len([]rune(text))
+	normalizationOffset := textLength - len(rs)
+	weightedLength := weighted / c.Scale
+	if validEnd >= 0 {
+		validEnd += normalizationOffset
+	}
+
+	return &ParseResults{
+		WeightedLength:    weightedLength,
+		Permillage:        weightedLength * 1000 / c.MaxWeightedTweetLength,
+		Valid:             !hasInvalidChar && weighted <= max,
+		DisplayRangeStart: 0,
+		DisplayRangeEnd:   textLength - 1,
+		ValidRangeStart:   0,
+		ValidRangeEnd:     validEnd,
+	}
+}
@@ -0,0 +1,214 @@
+package twittertext_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/michimani/gotwi/twittertext"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseTweet(t *testing.T) {
+	cases := []struct {
+		name          string
+		text          string
+		expectLength  int
+		expectValid   bool
+		expectDisplay int
+		expectValidTo int
+	}{
+		{
+			name:          "empty",
+			text:          "",
+			expectLength:  0,
+			expectValid:   false,
+			expectDisplay: 0,
+			expectValidTo: 0,
+		},
+		{
+			name:          "ascii",
+			text:          "Hello, world!",
+			expectLength:  13,
+			expectValid:   true,
+			expectDisplay: 12,
+			expectValidTo: 12,
+		},
+		{
+			name:          "280 ascii characters",
+			text:          strings.Repeat("a", 280),
+			expectLength:  280,
+			expectValid:   true,
+			expectDisplay: 279,
+			expectValidTo: 279,
+		},
+		{
+			name:          "281 ascii characters",
+			text:          strings.Repeat("a", 281),
+			expectLength:  281,
+			expectValid:   false,
+			expectDisplay: 280,
+			expectValidTo: 279,
+		},
+		{
+			name:          "140 CJK characters",
+			text:          strings.Repeat("日", 140),
+			expectLength:  280,
+			expectValid:   true,
+			expectDisplay: 139,
+			expectValidTo: 139,
+		},
+		{
+			name:          "141 CJK characters",
+			text:          strings.Repeat("日", 141),
+			expectLength:  282,
+			expectValid:   false,
+			expectDisplay: 140,
+			expectValidTo: 139,
+		},
+		{
+			name:          "URL with protocol",
+			text:          "https://example.com/" + strings.Repeat("path", 20),
+			expectLength:  23,
+			expectValid:   true,
+			expectDisplay: 99,
+			expectValidTo: 99,
+		},
+		{
+			name:          "URL without protocol",
+			text:          "see example.com.",
+			expectLength:  4 + 23 + 1,
+			expectValid:   true,
+			expectDisplay: 15,
+			expectValidTo: 15,
+		},
+		{
+			name:          "URL of a gTLD without protocol",
+			text:          "see example.agency",
+			expectLength:  4 + 23,
+			expectValid:   true,
+			expectDisplay: 17,
+			expectValidTo: 17,
+		},
+		{
+			name:          "unknown TLD with protocol is not a URL",
+			text:          "https://example.barbaz/" + strings.Repeat("a", 267),
+			expectLength:  290,
+			expectValid:   false,
+			expectDisplay: 289,
+			expectValidTo: 279,
+		},
+		{
+			name:          "unknown TLD is not a URL",
+			text:          "foo.barbaz",
+			expectLength:  10,
+			expectValid:   true,
+			expectDisplay: 9,
+			expectValidTo: 9,
+		},
+		{
+			name:          "emoji",
+			text:          "😀",
+			expectLength:  2,
+			expectValid:   true,
+			expectDisplay: 0,
+			expectValidTo: 0,
+		},
+		{
+			name:          "emoji ZWJ sequence",
+			text:          "👨‍👩‍👧‍👦",
+			expectLength:  2,
+			expectValid:   true,
+			expectDisplay: 6,
+			expectValidTo: 6,
+		},
+		{
+			name:          "emoji with skin tone",
+			text:          "👍🏽",
+			expectLength:  2,
+			expectValid:   true,
+			expectDisplay: 1,
+			expectValidTo: 1,
+		},
+		{
+			name:          "flag",
+			text:          "🇯🇵",
+			expectLength:  2,
+			expectValid:   true,
+			expectDisplay: 1,
+			expectValidTo: 1,
+		},
+		{
+			name:          "keycap",
+			text:          "1️⃣",
+			expectLength:  2,
+			expectValid:   true,
+			expectDisplay: 2,
+			expectValidTo: 2,
+		},
+		{
+			name:          "NFC normalization",
+			text:          "cafe\u0301",
+			expectLength:  4,
+			expectValid:   true,
+			expectDisplay: 4,
+			expectValidTo: 4,
+		},
+		{
+			name:          "invalid character",
+			text:          "abc\ufffe",
+			expectLength:  5,
+			expectValid:   false,
+			expectDisplay: 3,
+			expectValidTo: 3,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			r := twittertext.ParseTweet(c.text)
+			assert.Equal(tt, c.expectLength, r.WeightedLength)
+			assert.Equal(tt, c.expectValid, r.Valid)
+			assert.Equal(tt, 0, r.DisplayRangeStart)
+			assert.Equal(tt, c.expectDisplay, r.DisplayRangeEnd)
+			assert.Equal(tt, 0, r.ValidRangeStart)
+			assert.Equal(tt, c.expectValidTo, r.ValidRangeEnd)
+			assert.Equal(tt, c.expectLength*1000/280, r.Permillage)
+		})
+	}
+}
+
+func Test_ParseTweetWithConfiguration(t *testing.T) {
+	c := twittertext.ConfigurationV3
+	c.MaxWeightedTweetLength = 10
+
+	r := twittertext.ParseTweetWithConfiguration("abcdefghijk", &c)
+	assert.Equal(t, 11, r.WeightedLength)
+	assert.False(t, r.Valid)
+	assert.Equal(t, 9, r.ValidRangeEnd)
+	assert.Equal(t, 1100, r.Permillage)
+}
+
+func Test_ParseTweetWithConfiguration_firstCharacterOverLimit(t *testing.T) {
+	c := twittertext.ConfigurationV3
+	c.MaxWeightedTweetLength = 1
+
+	cases := []struct {
+		name   string
+		text   string
+		expect int
+	}{
+		{name: "CJK", text: "日本", expect: -1},
+		{name: "emoji", text: "🎉a", expect: -1},
+		{name: "URL", text: "https://go.dev", expect: -1},
+		{name: "fits", text: "ab", expect: 0},
+	}
+
+	for _, cc := range cases {
+		t.Run(cc.name, func(tt *testing.T) {
+			r := twittertext.ParseTweetWithConfiguration(cc.text, &c)
+			assert.False(tt, r.Valid)
+			assert.Equal(tt, 0, r.ValidRangeStart)
+			assert.Equal(tt, cc.expect, r.ValidRangeEnd)
+		})
+	}
+}
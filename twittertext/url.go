@@ -0,0 +1,154 @@
+package twittertext
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// urlCandidateRegexp matches an optional protocol, a domain and an optional port and path.
+// Candidates are validated in extractURLs, because the Go regexp does not support lookbehind.
+var urlCandidateRegexp = regexp.MustCompile(`(?i)(https?://)?((?:[\p{L}\p{N}](?:[\p{L}\p{N}_-]*[\p{L}\p{N}])?\.)+(?:xn--[a-z0-9-]+|[\p{L}]{2,}))(:[0-9]{1,5})?(/[^\s]*)?`)
+
+// invalidURLPrecedingChars are the characters that must not precede a URL without a protocol.
+const invalidURLPrecedingChars = "@＠$＄#＃.-/_"
+
+type urlMatch struct {
+	start int // code point offset
+	end   int // code point offset, exclusive
+	url   string
+}
+
+// extractURLs returns the URLs in the text with their code point offsets.
+// A URL is extracted only if its top level domain is a known one.
+func extractURLs(text string) []urlMatch {
+	matches := []urlMatch{}
+	if !strings.Contains(text, ".") {
+		return matches
+	}
+
+	offsets := runeOffsets(text)
+	for _, loc := range urlCandidateRegexp.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		hasProtocol := loc[2] >= 0
+		domain := text[loc[4]:loc[5]]
+
+		if start > 0 {
+			prev := lastRune(text[:start])
+			if hasProtocol {
+				if isAlnum(prev) {
+					continue
+				}
+			} else if isAlnum(prev) || strings.ContainsRune(invalidURLPrecedingChars, prev) {
+				continue
+			}
+		}
+
+		if !validDomain(domain, hasProtocol) {
+			continue
+		}
+
+		u := trimURLTail(text[start:end])
+		if !hasProtocol && loc[8] < 0 && strings.HasPrefix(text[start+len(u):], "@") {
+			// "example.com@" is a part of an email address.
+			continue
+		}
+
+		matches = append(matches, urlMatch{
+			start: offsets[start],
+			end:   offsets[start+len(u)],
+			url:   u,
+		})
+	}
+
+	return matches
+}
+
+// validDomain reports whether the domain ends with a known top level domain.
+// A domain without a protocol must be in ASCII.
+func validDomain(domain string, hasProtocol bool) bool {
+	if !hasProtocol && !isASCII(domain) {
+		return false
+	}
+
+	labels := strings.Split(domain, ".")
+	return validTLD(strings.ToLower(labels[len(labels)-1]))
+}
+
+// validTLD reports whether the top level domain is a known one, or an internationalized one in Punycode.
+func validTLD(tld string) bool {
+	if strings.HasPrefix(tld, "xn--") {
+		return true
+	}
+	for _, tlds := range []map[string]struct{}{gTLDs, ccTLDs, idnTLDs} {
+		if _, ok := tlds[tld]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// trimURLTail removes the trailing punctuation that is unlikely a part of the URL.
+// A closing parenthesis is kept if it is balanced in the URL, like "https://en.wikipedia.org/wiki/Go_(programming_language)".
+func trimURLTail(u string) string {
+	for len(u) > 0 {
+		r := lastRune(u)
+		switch {
+		case strings.ContainsRune(`.,:;!?'"“”‘’…`, r):
+			u = u[:len(u)-len(string(r))]
+		case r == ')' && strings.Count(u, "(") < strings.Count(u, ")"),
+			r == ']' && strings.Count(u, "[") < strings.Count(u, "]"),
+			r == '}' && strings.Count(u, "{") < strings.Count(u, "}"):
+			u = u[:len(u)-1]
+		default:
+			return u
+		}
+	}
+	return u
+}
+
+// runeOffsets returns a slice that maps the byte offset to the code point offset.
+func runeOffsets(s string) []int {
+	offsets := make([]int, len(s)+1)
+	n := 0
+	for i := 0; i < len(s); {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		for j := 0; j < size; j++ {
+			offsets[i+j] = n
+		}
+		i += size
+		n++
+	}
+	offsets[len(s)] = n
+	return offsets
+}
+
+func lastRune(s string) rune {
+	rs := []rune(s)
+	if len(rs) == 0 {
+		return 0
+	}
+	return rs[len(rs)-1]
+}
+
+func isAlnum(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+func toSet(words string) map[string]struct{} {
+	m := map[string]struct{}{}
+	for _, w := range strings.Fields(words) {
+		m[w] = struct{}{}
+	}
+	return m
+}
@@ -12,6 +12,7 @@ const (
 	UserLookupIDEndpoint         = "https://api.twitter.com/2/users/:id"
 	UserLookupByEndpoint         = "https://api.twitter.com/2/users/by"
 	UserLookupByUsernameEndpoint = "https://api.twitter.com/2/users/by/username/:username"
+	UserLookupMeEndpoint         = "https://api.twitter.com/2/users/me"
 )
 
 // Returns a variety of information about one or more users specified by the requested IDs.
@@ -57,3 +58,14 @@ func UserLookupByUsername(ctx context.Context, c *gotwi.GotwiClient, p *types.Us
 
 	return res, nil
 }
+
+// Returns information about an authorized user.
+// https://developer.twitter.com/en/docs/twitter-api/users/lookup/api-reference/get-users-me
+func UserLookupMe(ctx context.Context, c *gotwi.GotwiClient, p *types.UserLookupMeParams) (*types.UserLookupMeResponse, error) {
+	res := &types.UserLookupMeResponse{}
+	if err := c.CallAPI(ctx, UserLookupMeEndpoint, "GET", p, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -5,10 +5,8 @@ import "github.com/michimani/gotwi/resources"
 type FollowsFollowingGetResponse struct {
 	Data     []resources.User         `json:"data"`
 	Meta     resources.PaginationMeta `json:"meta"`
-	Includes struct {
-		Tweets []resources.Tweet `json:"tweets"`
-	} `json:"includes"`
-	Errors []resources.PartialError `json:"errors"`
+	Includes resources.Includes       `json:"includes"`
+	Errors   []resources.PartialError `json:"errors"`
 }
 
 func (r *FollowsFollowingGetResponse) HasPartialError() bool {
@@ -18,10 +16,8 @@ func (r *FollowsFollowingGetResponse) HasPartialError() bool {
 type FollowsFollowersResponse struct {
 	Data     []resources.User         `json:"data"`
 	Meta     resources.PaginationMeta `json:"meta"`
-	Includes struct {
-		Tweets []resources.Tweet `json:"tweets"`
-	} `json:"includes"`
-	Errors []resources.PartialError `json:"errors"`
+	Includes resources.Includes       `json:"includes"`
+	Errors   []resources.PartialError `json:"errors"`
 }
 
 func (r *FollowsFollowersResponse) HasPartialError() bool {
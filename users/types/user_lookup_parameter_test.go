@@ -475,3 +475,34 @@ func Test_UserLookupByUsernameParams_Body(t *testing.T) {
 		})
 	}
 }
+
+func Test_UserLookupMeParams_ResolveEndpoint(t *testing.T) {
+	const endpointBase = "test/endpoint/me"
+	cases := []struct {
+		name   string
+		params *types.UserLookupMeParams
+		expect string
+	}{
+		{
+			name:   "normal: no parameter",
+			params: &types.UserLookupMeParams{},
+			expect: endpointBase,
+		},
+		{
+			name: "normal: all query parameters",
+			params: &types.UserLookupMeParams{
+				Expansions:  fields.ExpansionList{"ex"},
+				UserFields:  fields.UserFieldList{"uf"},
+				TweetFields: fields.TweetFieldList{"tf"},
+			},
+			expect: endpointBase + "?expansions=ex&tweet.fields=tf&user.fields=uf",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(tt *testing.T) {
+			ep := c.params.ResolveEndpoint(endpointBase)
+			assert.Equal(tt, c.expect, ep)
+		})
+	}
+}
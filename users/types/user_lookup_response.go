@@ -3,11 +3,9 @@ package types
 import "github.com/michimani/gotwi/resources"
 
 type UserLookupResponse struct {
-	Data     []resources.User `json:"data"`
-	Includes struct {
-		Tweets []resources.Tweet `json:"tweets"`
-	} `json:"includes"`
-	Errors []resources.PartialError `json:"errors"`
+	Data     []resources.User         `json:"data"`
+	Includes resources.Includes       `json:"includes"`
+	Errors   []resources.PartialError `json:"errors"`
 }
 
 func (r *UserLookupResponse) HasPartialError() bool {
@@ -15,11 +13,9 @@ func (r *UserLookupResponse) HasPartialError() bool {
 }
 
 type UserLookupIDResponse struct {
-	Data     resources.User `json:"data"`
-	Includes struct {
-		Tweets []resources.Tweet `json:"tweets"`
-	} `json:"includes"`
-	Errors []resources.PartialError `json:"errors"`
+	Data     resources.User           `json:"data"`
+	Includes resources.Includes       `json:"includes"`
+	Errors   []resources.PartialError `json:"errors"`
 }
 
 func (r *UserLookupIDResponse) HasPartialError() bool {
@@ -27,11 +23,9 @@ func (r *UserLookupIDResponse) HasPartialError() bool {
 }
 
 type UserLookupByResponse struct {
-	Data     []resources.User `json:"data"`
-	Includes struct {
-		Tweets []resources.Tweet `json:"tweets"`
-	} `json:"includes"`
-	Errors []resources.PartialError `json:"errors"`
+	Data     []resources.User         `json:"data"`
+	Includes resources.Includes       `json:"includes"`
+	Errors   []resources.PartialError `json:"errors"`
 }
 
 func (r *UserLookupByResponse) HasPartialError() bool {
@@ -39,13 +33,21 @@ func (r *UserLookupByResponse) HasPartialError() bool {
 }
 
 type UserLookupByUsernameResponse struct {
-	Data     resources.User `json:"data"`
-	Includes struct {
-		Tweets []resources.Tweet `json:"tweets"`
-	} `json:"includes"`
-	Errors []resources.PartialError `json:"errors"`
+	Data     resources.User           `json:"data"`
+	Includes resources.Includes       `json:"includes"`
+	Errors   []resources.PartialError `json:"errors"`
 }
 
 func (r *UserLookupByUsernameResponse) HasPartialError() bool {
 	return !(r.Errors == nil || len(r.Errors) == 0)
 }
+
+type UserLookupMeResponse struct {
+	Data     resources.User           `json:"data"`
+	Includes resources.Includes       `json:"includes"`
+	Errors   []resources.PartialError `json:"errors"`
+}
+
+func (r *UserLookupMeResponse) HasPartialError() bool {
+	return !(r.Errors == nil || len(r.Errors) == 0)
+}